@@ -1,9 +1,17 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
@@ -16,26 +24,65 @@ import (
 	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph"
 	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/dataloader"
 	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/generated"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/gqlerrors"
 	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/subscriptions"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/health"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/metrics"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/tracing"
+	"github.com/Parnishkaspb/ozon_posts_pkg/logging"
+	"github.com/Parnishkaspb/ozon_posts_pkg/tlsconfig"
 
 	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
-	cfgPath := os.Getenv("CONFIG_PATH")
-	if cfgPath == "" {
-		cfgPath = "config/config.yaml"
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fatal("invalid config", "error", err)
 	}
-	cfg := config.MustLoad(cfgPath)
 
-	port := "8080"
-	grpcTarget := "service:9090"
+	logger, err := logging.New(os.Stderr, cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		fatal("invalid config", "error", err)
+	}
+	slog.SetDefault(logger)
 
-	conn, err := grpc.Dial(grpcTarget, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		ServiceName: "ozon-posts-graphql",
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
 	if err != nil {
-		log.Fatalf("grpc dial %s: %v", grpcTarget, err)
+		fatal("tracing", "error", err)
+	}
+
+	creds, err := dialCredentials(cfg.GRPC)
+	if err != nil {
+		fatal("grpc tls", "error", err)
+	}
+
+	conn, err := grpc.NewClient(cfg.GRPC.Target,
+		grpc.WithTransportCredentials(creds),
+		// trace context уходит в сервис через metadata; readyz-проверки не трассируем
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+			otelgrpc.WithFilter(filters.Not(filters.ServiceName(healthpb.Health_ServiceDesc.ServiceName))),
+		)),
+		grpc.WithChainUnaryInterceptor(logging.UnaryClientInterceptor(), auth.ForwardToken()),
+		grpc.WithStreamInterceptor(logging.StreamClientInterceptor()),
+	)
+	if err != nil {
+		fatal("grpc client", "target", cfg.GRPC.Target, "error", err)
 	}
 	defer conn.Close()
 
@@ -44,8 +91,29 @@ func main() {
 	postClient := servicepb.NewPostServiceClient(conn)
 	commentClient := servicepb.NewCommentServiceClient(conn)
 
-	subService := subscriptions.New()
-	jwtService := auth.New(cfg.JWT.Secret, cfg.JWT.TTL)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// фоновые циклы брокера останавливаются по сигналу; main ждёт их перед выходом
+	var background sync.WaitGroup
+	var subService subscriptions.Broker
+	switch cfg.Subscriptions.Broker {
+	case "grpc":
+		subService = subscriptions.NewGRPC(commentClient, postClient)
+	case "memory":
+		subService = subscriptions.NewMemory(commentClient, postClient)
+	case "postgres":
+		pg := subscriptions.NewPostgres(cfg.Subscriptions.PostgresDSN, commentClient, postClient)
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			pg.Run(ctx)
+		}()
+		subService = pg
+	default:
+		fatal("unknown subscriptions broker", "broker", cfg.Subscriptions.Broker)
+	}
+	jwtService := auth.New(authClient, cfg.JWKS.RefreshInterval)
 
 	srv := handler.New(generated.NewExecutableSchema(generated.Config{
 		Resolvers: &graph.Resolver{
@@ -55,26 +123,94 @@ func main() {
 			CommentSvc: commentClient,
 			SubSvc:     subService,
 		},
+		Directives: generated.DirectiveRoot{
+			Auth: auth.Directive,
+		},
 	}))
 
+	srv.Use(auth.Guard{})
+	srv.Use(metrics.GraphQL{})
+	srv.Use(tracing.GraphQL{})
+
+	srv.SetErrorPresenter(gqlerrors.Present)
+	srv.SetRecoverFunc(gqlerrors.Recover)
+
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
 	srv.AddTransport(transport.POST{})
 	srv.AddTransport(transport.Websocket{
 		Upgrader: websocket.Upgrader{
-			CheckOrigin: func(r *http.Request) bool { return true },
+			CheckOrigin: auth.CheckOrigin(cfg.Websocket.AllowedOrigins),
 		},
 		KeepAlivePingInterval: 15 * time.Second,
+		InitFunc:              auth.WebsocketInit(jwtService, authClient),
 	})
 
 	mux := http.NewServeMux()
 	mux.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	mux.Handle("/query", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		lds := dataloader.New(userClient)
+	mux.Handle("/query", tracing.Middleware(logging.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lds := dataloader.New(userClient, postClient, commentClient)
 		ctx := dataloader.Inject(r.Context(), lds)
-		auth.AuthMiddleware(jwtService, srv).ServeHTTP(w, r.WithContext(ctx))
-	}))
+		auth.AuthMiddleware(jwtService, authClient, srv).ServeHTTP(w, r.WithContext(ctx))
+	}))))
 
-	log.Printf("GraphQL started on :%s", port)
-	log.Fatal(http.ListenAndServe(":"+port, mux))
+	checker := health.New(conn)
+	mux.HandleFunc("/healthz", checker.Live)
+	mux.HandleFunc("/readyz", checker.Ready)
+	mux.Handle("/metrics", metrics.Handler())
+
+	httpServer := &http.Server{
+		Addr:    ":" + strconv.Itoa(cfg.HTTP.Port),
+		Handler: mux,
+	}
+
+	go func() {
+		slog.Info("GraphQL started", "addr", httpServer.Addr)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("http serve", "error", err)
+		}
+	}()
+
+	<-ctx.Done()
+
+	// /readyz отвечает 503 на время DrainDelay, затем закрываем HTTP
+	checker.Drain()
+	if cfg.HTTP.DrainDelay > 0 {
+		slog.Info("shutdown: not ready, draining", "delay", cfg.HTTP.DrainDelay)
+		time.Sleep(cfg.HTTP.DrainDelay)
+	}
+	slog.Info("shutdown: stopping HTTP server")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("shutdown", "error", err)
+	}
+	background.Wait()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Warn("shutdown: flush spans", "error", err)
+	}
+}
+
+// fatal пишет ошибку и завершает процесс, как log.Fatal.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+func dialCredentials(cfg config.GRPC) (credentials.TransportCredentials, error) {
+	if cfg.Insecure {
+		slog.Warn("grpc: GRPC_INSECURE is set, dialing the service without TLS")
+		return insecure.NewCredentials(), nil
+	}
+
+	reloader, err := tlsconfig.NewReloader(tlsconfig.Files{
+		CertFile: cfg.TLS.CertFile,
+		KeyFile:  cfg.TLS.KeyFile,
+		CAFile:   cfg.TLS.CAFile,
+	}, cfg.TLS.ReloadInterval)
+	if err != nil {
+		return nil, fmt.Errorf("grpc tls: %w", err)
+	}
+	return credentials.NewTLS(reloader.ClientConfig(cfg.TLS.ServerName)), nil
 }
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Значения extensions.code для ошибок аутентификации.
+const (
+	CodeUnauthenticated = "UNAUTHENTICATED"
+	CodeTokenExpired    = "TOKEN_EXPIRED"
+	CodeUnavailable     = "SERVICE_UNAVAILABLE"
+)
+
+// Error — ошибка аутентификации с машиночитаемым кодом для клиента.
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+var (
+	ErrMissingToken   = &Error{Code: CodeUnauthenticated, Message: "authentication required"}
+	ErrMalformedToken = &Error{Code: CodeUnauthenticated, Message: "malformed authorization token"}
+	ErrInvalidToken   = &Error{Code: CodeUnauthenticated, Message: "invalid token"}
+	ErrSessionRevoked = &Error{Code: CodeUnauthenticated, Message: "session revoked"}
+	ErrTokenExpired   = &Error{Code: CodeTokenExpired, Message: "token expired"}
+	// ErrAuthUnavailable — сессию не удалось проверить: сервис недоступен.
+	ErrAuthUnavailable = &Error{Code: CodeUnavailable, Message: "authentication temporarily unavailable"}
+)
+
+// classify сводит ошибки разбора JWT к публичным ошибкам, не раскрывая деталей.
+func classify(err error) *Error {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return ErrTokenExpired
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return ErrMalformedToken
+	case errors.Is(err, ErrJWKSUnavailable):
+		return ErrAuthUnavailable
+	default:
+		return ErrInvalidToken
+	}
+}
+
+// GQLError превращает ошибку аутентификации в GraphQL-ошибку с extensions.code.
+func GQLError(ctx context.Context, err *Error) *gqlerror.Error {
+	return &gqlerror.Error{
+		Err:        err,
+		Message:    err.Message,
+		Path:       graphql.GetPath(ctx),
+		Extensions: map[string]any{"code": err.Code},
+	}
+}
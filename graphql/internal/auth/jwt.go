@@ -1,41 +1,95 @@
 package auth
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
 	helper "github.com/Parnishkaspb/ozon_posts_graphql/internal/helper/model"
+	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"time"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultJWKSRefresh = 5 * time.Minute
+	// незнакомый kid вызывает внеплановую перезагрузку, но не чаще этого интервала
+	minJWKSRefetch   = 10 * time.Second
+	jwksFetchTimeout = 3 * time.Second
 )
 
+var (
+	ErrUnknownKeyID = errors.New("unknown signing key id")
+	// ErrJWKSUnavailable — ключи не удалось получить у сервиса.
+	ErrJWKSUnavailable = errors.New("jwks unavailable")
+)
+
+// KeySource — часть AuthServiceClient, отдающая публичные ключи сервиса.
+type KeySource interface {
+	GetJWKS(ctx context.Context, in *servicepb.GetJWKSRequest, opts ...grpc.CallOption) (*servicepb.GetJWKSResponse, error)
+}
+
+// Token проверяет access-токены по публичным ключам из JWKS сервиса;
+// приватного ключа у gateway нет, выписывать токены он не может.
 type Token struct {
-	Secret string
-	TTL    time.Duration
+	src     KeySource
+	refresh time.Duration
+
+	mu        sync.RWMutex
+	keys      map[string]verificationKey
+	fetchedAt time.Time
+
+	// fetches склеивает одновременные перезагрузки JWKS в один запрос
+	fetches singleflight.Group
+}
+
+type verificationKey struct {
+	method jwt.SigningMethod
+	public any
 }
 
 type Claims struct {
-	UserID  uuid.UUID `json:"user_id"`
-	Login   string    `json:"login"`
-	Name    string    `json:"name"`
-	Surname string    `json:"surname"`
+	UserID    uuid.UUID `json:"user_id"`
+	SessionID uuid.UUID `json:"sid"`
+	Login     string    `json:"login"`
+	Name      string    `json:"name"`
+	Surname   string    `json:"surname"`
 	jwt.RegisteredClaims
 }
 
-func New(secret string, ttl time.Duration) *Token {
+func New(src KeySource, refresh time.Duration) *Token {
+	if refresh <= 0 {
+		refresh = defaultJWKSRefresh
+	}
 	return &Token{
-		Secret: secret,
-		TTL:    ttl,
+		src:     src,
+		refresh: refresh,
+		keys:    make(map[string]verificationKey),
 	}
 }
 
-func (t *Token) ParseToken(tokenString string) (*helper.User, error) {
+func (t *Token) ParseToken(ctx context.Context, tokenString string) (*helper.User, error) {
 	token, err := jwt.ParseWithClaims(
 		tokenString,
 		&Claims{},
 		func(token *jwt.Token) (interface{}, error) {
-			if token.Method != jwt.SigningMethodHS256 {
+			kid, _ := token.Header["kid"].(string)
+			key, err := t.key(ctx, kid)
+			if err != nil {
+				return nil, err
+			}
+			if token.Method != key.method {
 				return nil, jwt.ErrTokenSignatureInvalid
 			}
-			return []byte(t.Secret), nil
+			return key.public, nil
 		},
 	)
 	if err != nil {
@@ -47,10 +101,91 @@ func (t *Token) ParseToken(tokenString string) (*helper.User, error) {
 		return nil, jwt.ErrTokenInvalidClaims
 	}
 
-	return &helper.User{
-		ID:      claims.UserID,
-		Login:   claims.Login,
-		Name:    claims.Name,
-		Surname: claims.Surname,
-	}, nil
+	user := &helper.User{
+		ID:        claims.UserID,
+		SessionID: claims.SessionID,
+		Login:     claims.Login,
+		Name:      claims.Name,
+		Surname:   claims.Surname,
+	}
+	if claims.ExpiresAt != nil {
+		user.ExpiresAt = claims.ExpiresAt.Time
+	}
+	return user, nil
+}
+
+func (t *Token) key(ctx context.Context, kid string) (verificationKey, error) {
+	t.mu.RLock()
+	key, ok := t.keys[kid]
+	age := time.Since(t.fetchedAt)
+	t.mu.RUnlock()
+
+	if ok && age < t.refresh {
+		return key, nil
+	}
+	if ok || age >= minJWKSRefetch {
+		_, err, _ := t.fetches.Do("jwks", func() (any, error) { return nil, t.fetch(ctx) })
+		if err != nil && !ok {
+			return verificationKey{}, err
+		}
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if key, ok := t.keys[kid]; ok {
+		return key, nil
+	}
+	return verificationKey{}, fmt.Errorf("%w: %q", ErrUnknownKeyID, kid)
+}
+
+func (t *Token) fetch(ctx context.Context) error {
+	// результат общий для всех ждущих, поэтому отмена одного запроса не должна его обрывать
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), jwksFetchTimeout)
+	defer cancel()
+
+	resp, err := t.src.GetJWKS(ctx, &servicepb.GetJWKSRequest{})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrJWKSUnavailable, err)
+	}
+
+	keys := make(map[string]verificationKey, len(resp.GetKeys()))
+	for _, jwk := range resp.GetKeys() {
+		key, err := parseJWK(jwk)
+		if err != nil {
+			return fmt.Errorf("jwk %q: %w", jwk.GetKid(), err)
+		}
+		keys[jwk.GetKid()] = key
+	}
+
+	t.mu.Lock()
+	t.keys = keys
+	t.fetchedAt = time.Now()
+	t.mu.Unlock()
+	return nil
+}
+
+func parseJWK(jwk *servicepb.JWK) (verificationKey, error) {
+	switch {
+	case jwk.GetKty() == "OKP" && jwk.GetCrv() == "Ed25519":
+		x, err := base64.RawURLEncoding.DecodeString(jwk.GetX())
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return verificationKey{}, errors.New("invalid Ed25519 public key")
+		}
+		return verificationKey{method: jwt.SigningMethodEdDSA, public: ed25519.PublicKey(x)}, nil
+	case jwk.GetKty() == "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.GetN())
+		if err != nil {
+			return verificationKey{}, errors.New("invalid RSA modulus")
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.GetE())
+		if err != nil {
+			return verificationKey{}, errors.New("invalid RSA exponent")
+		}
+		return verificationKey{
+			method: jwt.SigningMethodRS256,
+			public: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())},
+		}, nil
+	default:
+		return verificationKey{}, fmt.Errorf("unsupported key type %q", jwk.GetKty())
+	}
 }
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+)
+
+type fakeKeySource struct {
+	pub   ed25519.PublicKey
+	calls atomic.Int32
+	delay time.Duration
+}
+
+func (f *fakeKeySource) GetJWKS(ctx context.Context, _ *servicepb.GetJWKSRequest, _ ...grpc.CallOption) (*servicepb.GetJWKSResponse, error) {
+	f.calls.Add(1)
+	time.Sleep(f.delay)
+	return &servicepb.GetJWKSResponse{Keys: []*servicepb.JWK{{
+		Kid: "k1",
+		Kty: "OKP",
+		Crv: "Ed25519",
+		X:   base64.RawURLEncoding.EncodeToString(f.pub),
+	}}}, nil
+}
+
+func newSigner(t *testing.T) (*fakeKeySource, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &fakeKeySource{pub: pub}, priv
+}
+
+func signToken(t *testing.T, priv ed25519.PrivateKey, kid string, ttl time.Duration) string {
+	t.Helper()
+	claims := Claims{
+		UserID:    uuid.New(),
+		SessionID: uuid.New(),
+		Login:     "ivan",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+	}
+	tok := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	tok.Header["kid"] = kid
+	s, err := tok.SignedString(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestToken_ConcurrentRefetchIsShared(t *testing.T) {
+	src, priv := newSigner(t)
+	src.delay = 50 * time.Millisecond
+	tok := New(src, time.Minute)
+	raw := signToken(t, priv, "k1", time.Minute)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := tok.ParseToken(context.Background(), raw); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := src.calls.Load(); got != 1 {
+		t.Fatalf("GetJWKS called %d times, want 1", got)
+	}
+}
+
+func TestToken_UnknownKid(t *testing.T) {
+	src, priv := newSigner(t)
+	tok := New(src, time.Minute)
+
+	if _, err := tok.ParseToken(context.Background(), signToken(t, priv, "k1", time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	// незнакомый kid сразу после загрузки не вызывает повторный запрос
+	if _, err := tok.ParseToken(context.Background(), signToken(t, priv, "k2", time.Minute)); err == nil {
+		t.Fatal("expected error for unknown kid")
+	}
+	if got := src.calls.Load(); got != 1 {
+		t.Fatalf("GetJWKS called %d times, want 1", got)
+	}
+}
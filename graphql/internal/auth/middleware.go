@@ -1,27 +1,182 @@
 package auth
 
 import (
-	"github.com/Parnishkaspb/ozon_posts_graphql/internal/helper"
+	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/helper"
+	helpermodel "github.com/Parnishkaspb/ozon_posts_graphql/internal/helper/model"
+	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
-func AuthMiddleware(jwtService *Token, next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+// SessionChecker — часть AuthServiceClient, нужная для проверки отзыва токена.
+type SessionChecker interface {
+	CheckSession(ctx context.Context, in *servicepb.CheckSessionRequest, opts ...grpc.CallOption) (*servicepb.CheckSessionResponse, error)
+}
 
+// AuthMiddleware кладёт в контекст пользователя из заголовка Authorization.
+// Причина отказа для битого, просроченного или отозванного токена сохраняется
+// в контексте: операцию по ней отклоняет Guard, которому уже известен текст
+// запроса.
+func AuthMiddleware(jwtService *Token, sessions SessionChecker, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		h := r.Header.Get("Authorization")
-		const prefix = "Bearer "
+		if h == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
 
-		if strings.HasPrefix(h, prefix) {
-			token := strings.TrimSpace(strings.TrimPrefix(h, prefix))
-			if token != "" {
-				if user, err := jwtService.ParseToken(token); err == nil {
-					ctx := helper.WithUser(r.Context(), user)
-					r = r.WithContext(ctx)
-				}
-			}
+		ctx, err := authenticate(r.Context(), jwtService, sessions, h)
+		if err != nil {
+			ctx = context.WithValue(ctx, authErrorKey{}, err)
 		}
 
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+type authErrorKey struct{}
+
+// exemptFields — мутации, которые клиент вызывает как раз с устаревшим
+// заголовком: получить новый токен или завершить сессию.
+var exemptFields = map[string]bool{
+	"login":        true,
+	"refreshToken": true,
+	"logout":       true,
+}
+
+// Guard — расширение gqlgen: операцию с невалидным заголовком Authorization
+// отклоняет с кодом причины (TOKEN_EXPIRED, UNAUTHENTICATED), молча выполнять
+// её как анонимную нельзя. Пропускаются только мутации из exemptFields.
+type Guard struct{}
+
+var (
+	_ graphql.HandlerExtension     = Guard{}
+	_ graphql.OperationInterceptor = Guard{}
+)
+
+func (Guard) ExtensionName() string { return "AuthGuard" }
+
+func (Guard) Validate(graphql.ExecutableSchema) error { return nil }
+
+func (Guard) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	authErr, ok := ctx.Value(authErrorKey{}).(*Error)
+	if !ok || exempt(graphql.GetOperationContext(ctx)) {
+		return next(ctx)
+	}
+	return graphql.OneShot(&graphql.Response{Errors: gqlerror.List{GQLError(ctx, authErr)}})
+}
+
+func exempt(oc *graphql.OperationContext) bool {
+	if oc.Operation == nil || oc.Operation.Operation != ast.Mutation {
+		return false
+	}
+	fields := graphql.CollectFields(oc, oc.Operation.SelectionSet, []string{"Mutation"})
+	if len(fields) == 0 {
+		return false
+	}
+	for _, f := range fields {
+		if !exemptFields[f.Name] {
+			return false
+		}
+	}
+	return true
+}
+
+// WebsocketInit применяет те же правила к payload сообщения connection_init.
+// Протокол передаёт в connection_error только текст, поэтому код идёт префиксом.
+// Контекст соединения истекает вместе с токеном: gqlgen закрывает сокет,
+// и клиент переподключается с обновлённым токеном.
+func WebsocketInit(jwtService *Token, sessions SessionChecker) transport.WebsocketInitFunc {
+	return func(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+		h := payload.Authorization()
+		if h == "" {
+			return ctx, nil, nil
+		}
+
+		// ctx соединения отменяется, когда сокет закрыт
+		conn := ctx
+		ctx, err := authenticate(ctx, jwtService, sessions, h)
+		if err != nil {
+			return ctx, nil, fmt.Errorf("%s: %s", err.Code, err.Message)
+		}
+
+		user, _ := helper.FromContext(ctx)
+		if !user.ExpiresAt.IsZero() {
+			ctx = transport.AppendCloseReason(ctx, fmt.Sprintf("%s: %s", ErrTokenExpired.Code, ErrTokenExpired.Message))
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx, user.ExpiresAt)
+			// таймер дедлайна освобождается вместе с соединением, а не в момент истечения токена
+			context.AfterFunc(conn, cancel)
+		}
+		return ctx, nil, nil
+	}
+}
+
+// Directive реализует @auth: поле доступно только аутентифицированному пользователю.
+func Directive(ctx context.Context, obj any, next graphql.Resolver) (any, error) {
+	if _, ok := helper.FromContext(ctx); !ok {
+		if err, ok := ctx.Value(authErrorKey{}).(*Error); ok {
+			return nil, GQLError(ctx, err)
+		}
+		return nil, GQLError(ctx, ErrMissingToken)
+	}
+	return next(ctx)
+}
+
+// ForwardToken прокидывает токен пользователя в metadata исходящих gRPC-вызовов:
+// сервис сам проверяет его и определяет автора.
+func ForwardToken() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if token, ok := helper.TokenFromContext(ctx); ok {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func authenticate(ctx context.Context, jwtService *Token, sessions SessionChecker, header string) (context.Context, *Error) {
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return ctx, ErrMalformedToken
+	}
+	token := strings.TrimSpace(strings.TrimPrefix(header, prefix))
+	if token == "" {
+		return ctx, ErrMalformedToken
+	}
+
+	user, err := jwtService.ParseToken(ctx, token)
+	if err != nil {
+		return ctx, classify(err)
+	}
+	if err := checkSession(ctx, sessions, user); err != nil {
+		return ctx, err
+	}
+
+	ctx = helper.WithUser(ctx, user)
+	ctx = helper.WithToken(ctx, token)
+	return ctx, nil
+}
+
+// checkSession отличает отозванную сессию от недоступного сервиса: во втором
+// случае клиенту не нужно перелогиниваться, запрос стоит просто повторить.
+func checkSession(ctx context.Context, sessions SessionChecker, user *helpermodel.User) *Error {
+	resp, err := sessions.CheckSession(ctx, &servicepb.CheckSessionRequest{SessionId: user.SessionID.String()})
+	if err != nil {
+		slog.WarnContext(ctx, "auth: check session failed", "error", err)
+		return ErrAuthUnavailable
+	}
+	if !resp.GetActive() {
+		return ErrSessionRevoked
+	}
+	return nil
+}
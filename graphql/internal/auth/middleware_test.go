@@ -0,0 +1,320 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/helper"
+	helpermodel "github.com/Parnishkaspb/ozon_posts_graphql/internal/helper/model"
+	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeSessions struct {
+	active bool
+	err    error
+}
+
+func (f *fakeSessions) CheckSession(ctx context.Context, _ *servicepb.CheckSessionRequest, _ ...grpc.CallOption) (*servicepb.CheckSessionResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &servicepb.CheckSessionResponse{Active: f.active}, nil
+}
+
+type failingKeySource struct{}
+
+func (failingKeySource) GetJWKS(ctx context.Context, _ *servicepb.GetJWKSRequest, _ ...grpc.CallOption) (*servicepb.GetJWKSResponse, error) {
+	return nil, status.Error(codes.Unavailable, "connection refused")
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want *Error
+	}{
+		{name: "expired", err: fmt.Errorf("parse: %w", jwt.ErrTokenExpired), want: ErrTokenExpired},
+		{name: "malformed", err: jwt.ErrTokenMalformed, want: ErrMalformedToken},
+		{name: "jwks unavailable", err: fmt.Errorf("%w: %w", ErrJWKSUnavailable, errors.New("dial")), want: ErrAuthUnavailable},
+		{name: "bad signature", err: jwt.ErrTokenSignatureInvalid, want: ErrInvalidToken},
+		{name: "unknown kid", err: ErrUnknownKeyID, want: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classify(tt.err); got != tt.want {
+				t.Fatalf("classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	src, priv := newSigner(t)
+	valid := signToken(t, priv, "k1", time.Minute)
+	expired := signToken(t, priv, "k1", -time.Minute)
+
+	tests := []struct {
+		name     string
+		keys     KeySource
+		sessions *fakeSessions
+		header   string
+		want     *Error
+	}{
+		{name: "valid", header: "Bearer " + valid, sessions: &fakeSessions{active: true}},
+		{name: "not bearer", header: "Basic abc", sessions: &fakeSessions{active: true}, want: ErrMalformedToken},
+		{name: "empty bearer", header: "Bearer  ", sessions: &fakeSessions{active: true}, want: ErrMalformedToken},
+		{name: "garbage", header: "Bearer garbage", sessions: &fakeSessions{active: true}, want: ErrMalformedToken},
+		{name: "expired", header: "Bearer " + expired, sessions: &fakeSessions{active: true}, want: ErrTokenExpired},
+		{name: "revoked", header: "Bearer " + valid, sessions: &fakeSessions{}, want: ErrSessionRevoked},
+		{name: "session check fails", header: "Bearer " + valid, sessions: &fakeSessions{err: status.Error(codes.Unavailable, "down")}, want: ErrAuthUnavailable},
+		{name: "jwks fetch fails", keys: failingKeySource{}, header: "Bearer " + valid, sessions: &fakeSessions{active: true}, want: ErrAuthUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := tt.keys
+			if keys == nil {
+				keys = src
+			}
+
+			ctx, err := authenticate(context.Background(), New(keys, time.Minute), tt.sessions, tt.header)
+			if err != tt.want {
+				t.Fatalf("authenticate() error = %v, want %v", err, tt.want)
+			}
+
+			_, hasUser := helper.FromContext(ctx)
+			_, hasToken := helper.TokenFromContext(ctx)
+			if wantUser := tt.want == nil; hasUser != wantUser || hasToken != wantUser {
+				t.Fatalf("user in context = %v, token = %v, want %v", hasUser, hasToken, wantUser)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	src, priv := newSigner(t)
+	tokens := New(src, time.Minute)
+	valid := signToken(t, priv, "k1", time.Minute)
+	expired := signToken(t, priv, "k1", -time.Minute)
+
+	tests := []struct {
+		name     string
+		header   string
+		wantUser bool
+		// код, который вернёт поле с @auth
+		wantCode string
+	}{
+		{name: "anonymous", wantCode: CodeUnauthenticated},
+		{name: "valid token", header: "Bearer " + valid, wantUser: true},
+		// саму операцию отклоняет Guard, см. TestGuard
+		{name: "expired token", header: "Bearer " + expired, wantCode: CodeTokenExpired},
+		{name: "malformed token", header: "Bearer garbage", wantCode: CodeUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				called   bool
+				gotUser  bool
+				gotError error
+			)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				_, gotUser = helper.FromContext(r.Context())
+				_, gotError = Directive(r.Context(), nil, func(ctx context.Context) (any, error) { return nil, nil })
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/query", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			AuthMiddleware(tokens, &fakeSessions{active: true}, next).ServeHTTP(rec, req)
+
+			if !called || rec.Code != http.StatusOK {
+				t.Fatalf("request must reach the handler, status %d", rec.Code)
+			}
+			if gotUser != tt.wantUser {
+				t.Fatalf("user in context = %v, want %v", gotUser, tt.wantUser)
+			}
+			if code := errorCode(gotError); code != tt.wantCode {
+				t.Fatalf("@auth code = %q, want %q", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestGuard(t *testing.T) {
+	src, priv := newSigner(t)
+	tokens := New(src, time.Minute)
+	valid := signToken(t, priv, "k1", time.Minute)
+	expired := signToken(t, priv, "k1", -time.Minute)
+
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: `
+		type Query { posts: [String!]! }
+		type Mutation {
+			login(login: String!): String!
+			refreshToken(refreshToken: String!): String!
+			logout(refreshToken: String!): Boolean!
+			createPost(title: String!): String!
+		}
+	`})
+
+	tests := []struct {
+		name     string
+		header   string
+		query    string
+		wantCode string
+	}{
+		{name: "public query with expired token", header: "Bearer " + expired, query: `{ posts }`, wantCode: CodeTokenExpired},
+		{name: "public query with malformed token", header: "Bearer garbage", query: `{ posts }`, wantCode: CodeUnauthenticated},
+		{name: "mutation with expired token", header: "Bearer " + expired, query: `mutation { createPost(title: "t") }`, wantCode: CodeTokenExpired},
+		{name: "refresh with expired token", header: "Bearer " + expired, query: `mutation { refreshToken(refreshToken: "r") }`},
+		{name: "login and logout with expired token", header: "Bearer " + expired, query: `mutation { login(login: "l") logout(refreshToken: "r") }`},
+		{name: "refresh mixed with other mutation", header: "Bearer " + expired, query: `mutation { refreshToken(refreshToken: "r") createPost(title: "t") }`, wantCode: CodeTokenExpired},
+		{name: "public query without token", query: `{ posts }`},
+		{name: "public query with valid token", header: "Bearer " + valid, query: `{ posts }`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, errs := gqlparser.LoadQuery(schema, tt.query)
+			if errs != nil {
+				t.Fatal(errs)
+			}
+
+			var (
+				executed bool
+				resp     *graphql.Response
+			)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				oc := &graphql.OperationContext{Doc: doc, Operation: doc.Operations[0], Variables: map[string]any{}}
+				ctx := graphql.WithOperationContext(r.Context(), oc)
+				resp = Guard{}.InterceptOperation(ctx, func(ctx context.Context) graphql.ResponseHandler {
+					executed = true
+					return graphql.OneShot(&graphql.Response{})
+				})(ctx)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/query", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			AuthMiddleware(tokens, &fakeSessions{active: true}, next).ServeHTTP(httptest.NewRecorder(), req)
+
+			if tt.wantCode == "" {
+				if !executed || len(resp.Errors) != 0 {
+					t.Fatalf("operation must run, executed = %v, errors = %v", executed, resp.Errors)
+				}
+				return
+			}
+			if executed {
+				t.Fatal("operation must not run")
+			}
+			if len(resp.Errors) != 1 {
+				t.Fatalf("errors = %v, want one", resp.Errors)
+			}
+			if code := errorCode(resp.Errors[0]); code != tt.wantCode {
+				t.Fatalf("code = %q, want %q", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestDirective(t *testing.T) {
+	_, err := Directive(context.Background(), nil, func(ctx context.Context) (any, error) {
+		t.Fatal("resolver must not run without user")
+		return nil, nil
+	})
+	if code := errorCode(err); code != CodeUnauthenticated {
+		t.Fatalf("code = %q, want %q", code, CodeUnauthenticated)
+	}
+
+	ctx := helper.WithUser(context.Background(), &helpermodel.User{})
+	res, err := Directive(ctx, nil, func(ctx context.Context) (any, error) { return "ok", nil })
+	if err != nil || res != "ok" {
+		t.Fatalf("Directive() = %v, %v", res, err)
+	}
+}
+
+func TestWebsocketInit(t *testing.T) {
+	src, priv := newSigner(t)
+	init := WebsocketInit(New(src, time.Minute), &fakeSessions{active: true})
+
+	t.Run("anonymous", func(t *testing.T) {
+		ctx, _, err := init(context.Background(), transport.InitPayload{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := helper.FromContext(ctx); ok {
+			t.Fatal("unexpected user")
+		}
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		payload := transport.InitPayload{"Authorization": "Bearer " + signToken(t, priv, "k1", -time.Minute)}
+		_, _, err := init(context.Background(), payload)
+		if err == nil || !strings.HasPrefix(err.Error(), CodeTokenExpired+":") {
+			t.Fatalf("error = %v, want %s prefix", err, CodeTokenExpired)
+		}
+	})
+
+	t.Run("connection ends with token", func(t *testing.T) {
+		payload := transport.InitPayload{"Authorization": "Bearer " + signToken(t, priv, "k1", time.Minute)}
+		ctx, _, err := init(context.Background(), payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := helper.FromContext(ctx); !ok {
+			t.Fatal("user missing")
+		}
+		deadline, ok := ctx.Deadline()
+		if !ok || time.Until(deadline) > time.Minute {
+			t.Fatalf("deadline = %v, %v; want token expiry", deadline, ok)
+		}
+	})
+
+	t.Run("socket closed before expiry", func(t *testing.T) {
+		conn, closeConn := context.WithCancel(context.Background())
+		payload := transport.InitPayload{"Authorization": "Bearer " + signToken(t, priv, "k1", time.Minute)}
+		ctx, _, err := init(conn, payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		closeConn()
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+			t.Fatal("connection context outlived the socket")
+		}
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			t.Fatalf("ctx.Err() = %v, want %v", ctx.Err(), context.Canceled)
+		}
+	})
+}
+
+func errorCode(err error) string {
+	var gqlErr *gqlerror.Error
+	if !errors.As(err, &gqlErr) {
+		return ""
+	}
+	code, _ := gqlErr.Extensions["code"].(string)
+	return code
+}
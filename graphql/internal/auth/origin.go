@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// CheckOrigin разрешает websocket-подключения без Origin (не браузер), с того же
+// хоста, что и gateway, и из списка allowed; "*" в списке снимает ограничение.
+func CheckOrigin(allowed []string) func(r *http.Request) bool {
+	set := make(map[string]bool, len(allowed))
+	for _, o := range allowed {
+		set[o] = true
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" || set["*"] || set[origin] {
+			return true
+		}
+
+		u, err := url.Parse(origin)
+		return err == nil && u.Host == r.Host
+	}
+}
@@ -1,32 +1,134 @@
 package config
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"io"
 	"time"
 
-	"gopkg.in/yaml.v3"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/tracing"
+	"github.com/Parnishkaspb/ozon_posts_pkg/configload"
+	"github.com/Parnishkaspb/ozon_posts_pkg/logging"
 )
 
 type Config struct {
-	JWT Token `yaml:"jwt"`
+	HTTP          HTTP          `yaml:"http"`
+	GRPC          GRPC          `yaml:"grpc"`
+	JWKS          JWKS          `yaml:"jwks"`
+	Websocket     Websocket     `yaml:"websocket"`
+	Subscriptions Subscriptions `yaml:"subscriptions"`
+	Log           Log           `yaml:"log"`
+	Tracing       Tracing       `yaml:"tracing"`
 }
 
-type Token struct {
-	Secret string        `yaml:"secret_key"`
-	TTL    time.Duration `yaml:"ttl"`
+// Tracing — экспорт спанов OpenTelemetry.
+type Tracing struct {
+	// Exporter: none (по умолчанию), stdout или otlp.
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER" env-default:"none"`
+	// Endpoint — host:port OTLP/gRPC коллектора.
+	Endpoint    string  `yaml:"endpoint" env:"TRACING_OTLP_ENDPOINT"`
+	Insecure    bool    `yaml:"insecure" env:"TRACING_OTLP_INSECURE"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" env-default:"1"`
 }
 
-func MustLoad(path string) *Config {
-	b, err := os.ReadFile(path)
-	if err != nil {
-		panic(fmt.Errorf("read config: %w", err))
-	}
+type Log struct {
+	// Level: debug, info, warn или error.
+	Level string `yaml:"level" env:"LOG_LEVEL" env-default:"info"`
+	// Format: text или json.
+	Format string `yaml:"format" env:"LOG_FORMAT" env-default:"text"`
+}
+
+type HTTP struct {
+	Port int `yaml:"port" env:"HTTP_PORT" env-default:"8080"`
+	// DrainDelay — сколько /readyz отвечает 503 перед остановкой HTTP.
+	DrainDelay time.Duration `yaml:"drain_delay" env:"HTTP_DRAIN_DELAY"`
+}
+
+// GRPC — подключение к сервису. Без Insecure соединение идёт по TLS:
+// сервер проверяется по CAFile (или системным корням), а CertFile/KeyFile
+// включают клиентский сертификат для mTLS.
+type GRPC struct {
+	Target   string `yaml:"target" env:"GRPC_TARGET" env-default:"service:9090"`
+	Insecure bool   `yaml:"insecure" env:"GRPC_INSECURE"`
+	TLS      TLS    `yaml:"tls"`
+}
+
+type TLS struct {
+	CAFile   string `yaml:"ca_file" env:"GRPC_TLS_CA_FILE"`
+	CertFile string `yaml:"cert_file" env:"GRPC_TLS_CERT_FILE"`
+	KeyFile  string `yaml:"key_file" env:"GRPC_TLS_KEY_FILE"`
+	// ServerName — имя в сертификате сервиса, если оно не совпадает с хостом из Target.
+	ServerName     string        `yaml:"server_name" env:"GRPC_TLS_SERVER_NAME"`
+	ReloadInterval time.Duration `yaml:"reload_interval" env:"GRPC_TLS_RELOAD_INTERVAL" env-default:"10s"`
+}
+
+type Subscriptions struct {
+	// Broker: "grpc" (по умолчанию) — потоки WatchComments/WatchPosts сервиса,
+	// "memory" — те же потоки, общие для подписчиков одного поста,
+	// "postgres" — LISTEN на события сервиса.
+	Broker      string `yaml:"broker" env:"SUBSCRIPTIONS_BROKER" env-default:"grpc"`
+	PostgresDSN string `yaml:"postgres_dsn" env:"SUBSCRIPTIONS_POSTGRES_DSN"`
+}
+
+type Websocket struct {
+	// AllowedOrigins — origin'ы браузерных клиентов, помимо самого gateway.
+	AllowedOrigins []string `yaml:"allowed_origins" env:"WEBSOCKET_ALLOWED_ORIGINS"`
+}
+
+// JWKS — как часто перечитывать публичные ключи сервиса.
+type JWKS struct {
+	RefreshInterval time.Duration `yaml:"refresh_interval" env:"JWKS_REFRESH_INTERVAL"`
+}
 
+const defaultPath = "config/config.yaml"
+
+// Load собирает конфиг из YAML, переменных окружения и флагов (в порядке
+// возрастания приоритета) и проверяет его. Путь к YAML — флаг -config или
+// CONFIG_PATH.
+func Load(args []string) (*Config, error) {
 	var cfg Config
-	if err := yaml.Unmarshal(b, &cfg); err != nil {
-		panic(fmt.Errorf("yaml unmarshal: %w", err))
+	if _, err := configload.Load(&cfg, "graphql", defaultPath, args); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate перечисляет все ошибки конфига разом, а не первую попавшуюся.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if _, err := logging.New(io.Discard, c.Log.Level, c.Log.Format); err != nil {
+		errs = append(errs, fmt.Errorf("log: %w", err))
+	}
+	if err := (tracing.Options{Exporter: c.Tracing.Exporter, SampleRatio: c.Tracing.SampleRatio}).Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("tracing: %w", err))
+	}
+
+	if c.HTTP.Port <= 0 || c.HTTP.Port > 65535 {
+		errs = append(errs, fmt.Errorf("http.port: must be in 1..65535, got %d", c.HTTP.Port))
+	}
+	if c.GRPC.Target == "" {
+		errs = append(errs, fmt.Errorf("grpc.target: is required (yaml grpc.target, env GRPC_TARGET or flag -grpc.target)"))
+	}
+	if (c.GRPC.TLS.CertFile == "") != (c.GRPC.TLS.KeyFile == "") {
+		errs = append(errs, fmt.Errorf("grpc.tls: cert_file and key_file must be set together"))
+	}
+	if c.JWKS.RefreshInterval < 0 {
+		errs = append(errs, fmt.Errorf("jwks.refresh_interval: must not be negative"))
+	}
+
+	switch c.Subscriptions.Broker {
+	case "grpc", "memory":
+	case "postgres":
+		if c.Subscriptions.PostgresDSN == "" {
+			errs = append(errs, fmt.Errorf("subscriptions.postgres_dsn: is required for the postgres broker"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("subscriptions.broker: unknown %q, want grpc, memory or postgres", c.Subscriptions.Broker))
 	}
 
-	return &cfg
+	return errors.Join(errs...)
 }
@@ -7,6 +7,7 @@ package graph
 
 import (
 	"context"
+	"strings"
 
 	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/generated"
 	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/model"
@@ -24,7 +25,44 @@ func (r *mutationResolver) Login(ctx context.Context, login string, password str
 		return &model.AuthPayload{}, err
 	}
 
-	return &model.AuthPayload{Token: answer.Token}, nil
+	return &model.AuthPayload{Token: answer.Token, RefreshToken: answer.RefreshToken}, nil
+}
+
+// Register is the resolver for the register field.
+func (r *mutationResolver) Register(ctx context.Context, login string, password string, name string, surname string) (*model.AuthPayload, error) {
+	if _, err := r.UserSvc.CreateUser(ctx, &servicepb.CreateUserRequest{
+		Login:    login,
+		Password: password,
+		Name:     name,
+		Surname:  surname,
+	}); err != nil {
+		return &model.AuthPayload{}, err
+	}
+
+	// сервис сохраняет логин без краевых пробелов — входим под ним же
+	return r.Login(ctx, strings.TrimSpace(login), password)
+}
+
+// RefreshToken is the resolver for the refreshToken field.
+func (r *mutationResolver) RefreshToken(ctx context.Context, refreshToken string) (*model.AuthPayload, error) {
+	answer, err := r.AuthSvc.RefreshToken(ctx, &servicepb.RefreshTokenRequest{
+		RefreshToken: refreshToken,
+	})
+
+	if err != nil {
+		return &model.AuthPayload{}, err
+	}
+
+	return &model.AuthPayload{Token: answer.Token, RefreshToken: answer.RefreshToken}, nil
+}
+
+// Logout is the resolver for the logout field.
+func (r *mutationResolver) Logout(ctx context.Context, refreshToken string) (bool, error) {
+	if _, err := r.AuthSvc.Logout(ctx, &servicepb.LogoutRequest{RefreshToken: refreshToken}); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
 // Mutation returns generated.MutationResolver implementation.
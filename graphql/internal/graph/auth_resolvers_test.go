@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"google.golang.org/grpc"
+)
+
+type mockUserSvc struct {
+	servicepb.UserServiceClient
+	created *servicepb.CreateUserRequest
+}
+
+func (m *mockUserSvc) CreateUser(ctx context.Context, in *servicepb.CreateUserRequest, opts ...grpc.CallOption) (*servicepb.CreateUserResponse, error) {
+	m.created = in
+	return &servicepb.CreateUserResponse{Id: "id"}, nil
+}
+
+type mockAuthSvc struct {
+	servicepb.AuthServiceClient
+	login *servicepb.LoginRequest
+}
+
+func (m *mockAuthSvc) Login(ctx context.Context, in *servicepb.LoginRequest, opts ...grpc.CallOption) (*servicepb.LoginResponse, error) {
+	m.login = in
+	return &servicepb.LoginResponse{Token: "token", RefreshToken: "refresh"}, nil
+}
+
+func TestRegister_TrimsLoginForLogin(t *testing.T) {
+	users, auth := &mockUserSvc{}, &mockAuthSvc{}
+	r := &mutationResolver{&Resolver{AuthSvc: auth, UserSvc: users}}
+
+	payload, err := r.Register(context.Background(), "  ivan  ", "password", "Ivan", "Petrov")
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if users.created.GetLogin() != "  ivan  " {
+		t.Fatalf("CreateUser got login %q, want it unchanged", users.created.GetLogin())
+	}
+	if auth.login.GetLogin() != "ivan" {
+		t.Fatalf("Login got login %q, want %q", auth.login.GetLogin(), "ivan")
+	}
+	if payload.Token != "token" || payload.RefreshToken != "refresh" {
+		t.Fatalf("payload = %+v", payload)
+	}
+}
@@ -8,18 +8,42 @@ package graph
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/generated"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/gqlerrors"
 	helpergraph "github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/helper"
 	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/model"
-	"github.com/Parnishkaspb/ozon_posts_graphql/internal/helper"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/subscriptions"
 	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"github.com/google/uuid"
 )
 
+// Post is the resolver for the post field.
+func (r *commentResolver) Post(ctx context.Context, obj *model.Comment) (*model.Post, error) {
+	post, err := helpergraph.ResolvePost(ctx, obj.PostID)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil {
+		return nil, gqlerrors.NotFound("post not found")
+	}
+	return post, nil
+}
+
+// Parent is the resolver for the parent field.
+func (r *commentResolver) Parent(ctx context.Context, obj *model.Comment) (*model.Comment, error) {
+	if obj.ParentID == nil {
+		return nil, nil
+	}
+	return helpergraph.ResolveComment(ctx, *obj.ParentID)
+}
+
 // Author is the resolver for the author field.
 func (r *commentResolver) Author(ctx context.Context, obj *model.Comment) (*model.User, error) {
-	return helpergraph.ResolveAuthor(ctx, obj.AuthorID)
+	if obj.AuthorID == nil {
+		return nil, nil
+	}
+	return helpergraph.ResolveAuthor(ctx, *obj.AuthorID)
 }
 
 // Replies is the resolver for the replies field.
@@ -40,22 +64,9 @@ func (r *commentResolver) Replies(ctx context.Context, obj *model.Comment, first
 
 	edges := make([]*model.CommentEdge, 0, len(resp.GetComments()))
 	for _, c := range resp.GetComments() {
-		node := &model.Comment{
-			ID:        c.GetId(),
-			PostID:    c.GetPostId(),
-			Text:      c.GetText(),
-			CreatedAt: c.GetCreatedAt().AsTime().UTC().Format(time.RFC3339),
-			AuthorID:  c.GetAuthorId(),
-		}
-
-		if c.GetParentId() != "" {
-			pid := c.GetParentId()
-			node.ParentID = &pid
-		}
-
 		edges = append(edges, &model.CommentEdge{
 			Cursor: helpergraph.MakeCursor(c.GetCreatedAt(), c.GetId()),
-			Node:   node,
+			Node:   helpergraph.ToComment(c),
 		})
 	}
 
@@ -76,12 +87,6 @@ func (r *commentResolver) Replies(ctx context.Context, obj *model.Comment, first
 
 // CreateComment is the resolver for the createComment field.
 func (r *mutationResolver) CreateComment(ctx context.Context, postID string, parentID *string, text string) (*model.Comment, error) {
-	u, ok := helper.FromContext(ctx)
-
-	if !ok {
-		return nil, fmt.Errorf("unauthorized")
-	}
-
 	var parentIDstring string
 	if parentID != nil {
 		parentIDstring = *parentID
@@ -89,7 +94,6 @@ func (r *mutationResolver) CreateComment(ctx context.Context, postID string, par
 
 	resp, err := r.CommentSvc.CreateComment(ctx, &servicepb.CreateCommentRequest{
 		PostId:   postID,
-		AuthorId: u.ID.String(),
 		ParentId: parentIDstring,
 		Text:     text,
 	})
@@ -103,41 +107,94 @@ func (r *mutationResolver) CreateComment(ctx context.Context, postID string, par
 		return nil, fmt.Errorf("empty comment in response")
 	}
 
-	pid := c.GetParentId()
-	var pID *string
-	if pid != "" {
-		pID = &pid
-	}
+	return helpergraph.ToComment(c), nil
+}
 
-	createdAt := ""
-	if ts := c.GetCreatedAt(); ts != nil {
-		createdAt = ts.AsTime().UTC().Format(time.RFC3339)
+// EditComment is the resolver for the editComment field.
+func (r *mutationResolver) EditComment(ctx context.Context, id string, text string) (*model.Comment, error) {
+	resp, err := r.CommentSvc.EditComment(ctx, &servicepb.EditCommentRequest{
+		Id:   id,
+		Text: text,
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
-	comment := &model.Comment{
-		ID:        c.GetId(),
-		PostID:    c.GetPostId(),
-		ParentID:  pID,
-		Text:      c.GetText(),
-		CreatedAt: createdAt,
-		AuthorID:  c.GetAuthorId(),
+	return helpergraph.ToComment(resp.GetComment()), nil
+}
+
+// DeleteComment is the resolver for the deleteComment field.
+func (r *mutationResolver) DeleteComment(ctx context.Context, id string) (*model.Comment, error) {
+	resp, err := r.CommentSvc.DeleteComment(ctx, &servicepb.DeleteCommentRequest{
+		Id: id,
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
-	r.SubSvc.Publish(comment.PostID, comment)
+	return helpergraph.ToComment(resp.GetComment()), nil
+}
+
+// Comment is the resolver for the comment field.
+func (r *queryResolver) Comment(ctx context.Context, id string) (*model.Comment, error) {
+	// невалидный id уронил бы весь батч загрузчика, поэтому отсекаем его здесь
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, gqlerrors.BadUserInput("id", "invalid comment id")
+	}
 
-	return comment, nil
+	return helpergraph.ResolveComment(ctx, uid.String())
 }
 
 // CommentAdded is the resolver for the commentAdded field.
-func (r *subscriptionResolver) CommentAdded(ctx context.Context, postID string) (<-chan *model.Comment, error) {
-	ch := r.SubSvc.Subscribe(postID)
+func (r *subscriptionResolver) CommentAdded(ctx context.Context, postID string, after *string) (<-chan *model.CommentAddedEvent, error) {
+	var cursor string
+	if after != nil {
+		cursor = *after
+	}
+
+	return subscriptions.Watch[*model.CommentAddedEvent](ctx, r.SubSvc, subscriptions.PostTopic(postID), cursor)
+}
+
+// ReplyAdded is the resolver for the replyAdded field.
+func (r *subscriptionResolver) ReplyAdded(ctx context.Context, commentID string, after *string) (<-chan *model.CommentAddedEvent, error) {
+	if _, err := uuid.Parse(commentID); err != nil {
+		return nil, gqlerrors.BadUserInput("commentId", "invalid comment id")
+	}
+
+	// поток ветки привязан к посту, поэтому сначала узнаём пост комментария
+	resp, err := r.CommentSvc.GetCommentsByIDs(ctx, &servicepb.GetCommentsByIDsRequest{Ids: []string{commentID}})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.GetComments()) == 0 {
+		return nil, gqlerrors.NotFound("comment not found")
+	}
+	postID := resp.GetComments()[0].GetPostId()
+
+	var cursor string
+	if after != nil {
+		cursor = *after
+	}
+
+	return subscriptions.Watch[*model.CommentAddedEvent](ctx, r.SubSvc, subscriptions.ThreadTopic(postID, commentID), cursor)
+}
 
-	go func() {
-		<-ctx.Done()
-		r.SubSvc.Unsubscribe(postID, ch)
-	}()
+// CommentEdited is the resolver for the commentEdited field.
+func (r *subscriptionResolver) CommentEdited(ctx context.Context, postID string) (<-chan *model.CommentEditedEvent, error) {
+	return subscriptions.Watch[*model.CommentEditedEvent](ctx, r.SubSvc, subscriptions.PostTopic(postID), "")
+}
+
+// CommentDeleted is the resolver for the commentDeleted field.
+func (r *subscriptionResolver) CommentDeleted(ctx context.Context, postID string) (<-chan *model.CommentDeletedEvent, error) {
+	return subscriptions.Watch[*model.CommentDeletedEvent](ctx, r.SubSvc, subscriptions.PostTopic(postID), "")
+}
 
-	return ch, nil
+// CommentEvents is the resolver for the commentEvents field.
+func (r *subscriptionResolver) CommentEvents(ctx context.Context, postID string) (<-chan model.CommentEvent, error) {
+	return subscriptions.Watch[model.CommentEvent](ctx, r.SubSvc, subscriptions.PostTopic(postID), "")
 }
 
 // Comment returns generated.CommentResolver implementation.
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"time"
 
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/metrics"
 	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"github.com/google/uuid"
 	"github.com/graph-gophers/dataloader"
 )
 
@@ -13,20 +15,48 @@ var ErrNotInjected = errors.New("dataloader not injected")
 
 type key struct{}
 
+// Имена загрузчиков в метриках.
+const (
+	usersLoader    = "users"
+	postsLoader    = "posts"
+	commentsLoader = "comments"
+)
+
 type Loaders struct {
-	UsersByIDs *dataloader.Loader
+	UsersByIDs    *dataloader.Loader
+	PostsByIDs    *dataloader.Loader
+	CommentsByIDs *dataloader.Loader
 }
 
-func New(userSvc servicepb.UserServiceClient) *Loaders {
+func New(userSvc servicepb.UserServiceClient, postSvc servicepb.PostServiceClient, commentSvc servicepb.CommentServiceClient) *Loaders {
 	return &Loaders{
-		UsersByIDs: dataloader.NewBatchedLoader(
-			batchUsers(userSvc),
-			dataloader.WithWait(2*time.Millisecond),
-			dataloader.WithBatchCapacity(200),
-		),
+		UsersByIDs:    newLoader(usersLoader, batchUsers(userSvc)),
+		PostsByIDs:    newLoader(postsLoader, batchPosts(postSvc)),
+		CommentsByIDs: newLoader(commentsLoader, batchComments(commentSvc)),
 	}
 }
 
+func newLoader(name string, batch dataloader.BatchFunc) *dataloader.Loader {
+	return dataloader.NewBatchedLoader(
+		batch,
+		dataloader.WithWait(2*time.Millisecond),
+		dataloader.WithBatchCapacity(200),
+		dataloader.WithCache(&countingCache{Cache: dataloader.NewCache(), loader: name}),
+	)
+}
+
+// countingCache считает попадания в кэш загрузчика.
+type countingCache struct {
+	dataloader.Cache
+	loader string
+}
+
+func (c *countingCache) Get(ctx context.Context, key dataloader.Key) (dataloader.Thunk, bool) {
+	thunk, ok := c.Cache.Get(ctx, key)
+	metrics.LoaderCache(c.loader, ok)
+	return thunk, ok
+}
+
 func Inject(ctx context.Context, loaders *Loaders) context.Context {
 	return context.WithValue(ctx, key{}, loaders)
 }
@@ -37,11 +67,58 @@ func FromContext(ctx context.Context) (*Loaders, bool) {
 }
 
 func batchUsers(userSvc servicepb.UserServiceClient) dataloader.BatchFunc {
+	return batchByIDs(usersLoader, func(ctx context.Context, ids []string) ([]*servicepb.User, error) {
+		resp, err := userSvc.GetUsers(ctx, &servicepb.GetUsersRequest{Ids: ids})
+		if err != nil {
+			return nil, err
+		}
+		return resp.GetUsers(), nil
+	})
+}
+
+func batchPosts(postSvc servicepb.PostServiceClient) dataloader.BatchFunc {
+	return batchByIDs(postsLoader, func(ctx context.Context, ids []string) ([]*servicepb.Post, error) {
+		resp, err := postSvc.GetPosts(ctx, &servicepb.GetPostsRequest{Ids: ids})
+		if err != nil {
+			return nil, err
+		}
+		return resp.GetPosts(), nil
+	})
+}
+
+func batchComments(commentSvc servicepb.CommentServiceClient) dataloader.BatchFunc {
+	return batchByIDs(commentsLoader, func(ctx context.Context, ids []string) ([]*servicepb.Comment, error) {
+		resp, err := commentSvc.GetCommentsByIDs(ctx, &servicepb.GetCommentsByIDsRequest{Ids: ids})
+		if err != nil {
+			return nil, err
+		}
+		return resp.GetComments(), nil
+	})
+}
+
+type identified interface {
+	comparable
+	GetId() string
+}
+
+// canonicalID приводит UUID к виду, в котором его возвращает сервис
+// (нижний регистр, без скобок); не-UUID остаётся как есть.
+func canonicalID(id string) string {
+	if uid, err := uuid.Parse(id); err == nil {
+		return uid.String()
+	}
+	return id
+}
+
+// batchByIDs собирает уникальные непустые ключи, делает один запрос fetch
+// и раскладывает ответ обратно по ключам. Ключи сравниваются в каноническом
+// виде UUID, поэтому "ABC…" и "{abc…}" находят тот же объект. Ненайденный ключ => Data: nil.
+func batchByIDs[T identified](loader string, fetch func(ctx context.Context, ids []string) ([]T, error)) dataloader.BatchFunc {
 	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
 		uniq := make([]string, 0, len(keys))
 		seen := make(map[string]struct{}, len(keys))
 		for _, k := range keys {
-			id := k.String()
+			id := canonicalID(k.String())
 			if id == "" {
 				continue
 			}
@@ -62,8 +139,9 @@ func batchUsers(userSvc servicepb.UserServiceClient) dataloader.BatchFunc {
 
 		rpcCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
 		defer cancel()
-		resp, err := userSvc.GetUsers(rpcCtx, &servicepb.GetUsersRequest{Ids: uniq})
+		items, err := fetch(rpcCtx, uniq)
 		if err != nil {
+			metrics.LoaderBatch(loader, len(uniq), 0, 0, err)
 			out := make([]*dataloader.Result, len(keys))
 			for i := range out {
 				out[i] = &dataloader.Result{Error: err}
@@ -71,22 +149,24 @@ func batchUsers(userSvc servicepb.UserServiceClient) dataloader.BatchFunc {
 			return out
 		}
 
-		m := make(map[string]*servicepb.User, len(resp.GetUsers()))
-		for _, u := range resp.GetUsers() {
-			if u == nil || u.GetId() == "" {
+		var zero T
+		m := make(map[string]T, len(items))
+		for _, item := range items {
+			if item == zero || item.GetId() == "" {
 				continue
 			}
-			m[u.GetId()] = u
+			m[item.GetId()] = item
 		}
+		metrics.LoaderBatch(loader, len(uniq), len(m), len(uniq)-len(m), nil)
 
 		out := make([]*dataloader.Result, len(keys))
 		for i, k := range keys {
-			id := k.String()
-			if id == "" {
-				out[i] = &dataloader.Result{Data: nil, Error: nil}
+			item, ok := m[canonicalID(k.String())]
+			if !ok {
+				out[i] = &dataloader.Result{Data: nil, Error: nil} // nil = not found
 				continue
 			}
-			out[i] = &dataloader.Result{Data: m[id], Error: nil} // nil = not found
+			out[i] = &dataloader.Result{Data: item, Error: nil}
 		}
 		return out
 	}
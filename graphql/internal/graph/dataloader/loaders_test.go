@@ -0,0 +1,41 @@
+package dataloader
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"github.com/graph-gophers/dataloader"
+)
+
+func TestBatchByIDs_CanonicalKeys(t *testing.T) {
+	const id = "0b5c1f7e-3c4d-4e8a-9f6b-2a1d3c4e5f60"
+
+	var requested []string
+	batch := batchByIDs(commentsLoader, func(ctx context.Context, ids []string) ([]*servicepb.Comment, error) {
+		requested = ids
+		return []*servicepb.Comment{{Id: id}}, nil
+	})
+
+	keys := dataloader.NewKeysFromStrings([]string{
+		id,
+		strings.ToUpper(id),
+		"{" + id + "}",
+		"6f1e2d3c-4b5a-4978-8a6b-5c4d3e2f1a0b",
+	})
+	results := batch(context.Background(), keys)
+
+	if len(requested) != 2 || requested[0] != id {
+		t.Fatalf("requested ids = %v, want %s and the missing id once each", requested, id)
+	}
+	for i, res := range results[:3] {
+		c, ok := res.Data.(*servicepb.Comment)
+		if res.Error != nil || !ok || c.GetId() != id {
+			t.Fatalf("key %q: got %v, %v", keys[i].String(), res.Data, res.Error)
+		}
+	}
+	if res := results[3]; res.Data != nil || res.Error != nil {
+		t.Fatalf("missing key: got %v, %v, want nil", res.Data, res.Error)
+	}
+}
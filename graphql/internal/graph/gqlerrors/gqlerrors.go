@@ -0,0 +1,188 @@
+// Package gqlerrors переводит ошибки резолверов в GraphQL-ошибки с
+// extensions.code: статусы gRPC сервиса, ошибки аутентификации и собственные
+// ошибки gateway. Внутренние ошибки и паники клиент видит только как
+// INTERNAL с errorId, по которому подробности ищутся в логе.
+package gqlerrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/auth"
+	"github.com/Parnishkaspb/ozon_posts_pkg/logging"
+)
+
+// Значения extensions.code.
+const (
+	CodeNotFound        = "NOT_FOUND"
+	CodeBadUserInput    = "BAD_USER_INPUT"
+	CodeForbidden       = "FORBIDDEN"
+	CodeUnauthenticated = auth.CodeUnauthenticated
+	// CodeUnavailable — временный сбой сервиса, запрос стоит повторить.
+	CodeUnavailable = auth.CodeUnavailable
+	CodeInternal    = "INTERNAL"
+)
+
+const (
+	internalMessage    = "internal server error"
+	unavailableMessage = "service temporarily unavailable"
+)
+
+// Error — ошибка самого gateway с кодом для клиента; Field — аргумент
+// GraphQL, к которому она относится.
+type Error struct {
+	Code    string
+	Message string
+	Field   string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func NotFound(msg string) *Error { return &Error{Code: CodeNotFound, Message: msg} }
+
+func BadUserInput(field, msg string) *Error {
+	return &Error{Code: CodeBadUserInput, Message: msg, Field: field}
+}
+
+// FieldError — нарушение валидации одного аргумента, extensions.fields.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Present — ErrorPresenterFunc для gqlgen. Ошибки резолверов приходят
+// обёрнутыми в gqlerror с путём поля, поэтому код выбирается по причине.
+func Present(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	if _, ok := gqlErr.Extensions["code"]; ok || gqlErr.Err == nil {
+		// ошибки разбора, валидации и @auth уже оформлены
+		return gqlErr
+	}
+	cause := gqlErr.Err
+
+	var (
+		authErr *auth.Error
+		ownErr  *Error
+	)
+	switch {
+	case errors.As(cause, &authErr):
+		return withExtensions(gqlErr, authErr.Message, map[string]any{"code": authErr.Code})
+	case errors.As(cause, &ownErr):
+		ext := map[string]any{"code": ownErr.Code}
+		if ownErr.Field != "" {
+			ext["field"] = ownErr.Field
+			ext["fields"] = []FieldError{{Field: ownErr.Field, Message: ownErr.Message}}
+		}
+		return withExtensions(gqlErr, ownErr.Message, ext)
+	}
+
+	if st, ok := status.FromError(cause); ok {
+		return presentStatus(ctx, gqlErr, st)
+	}
+	return internal(ctx, gqlErr)
+}
+
+// Recover — RecoverFunc для gqlgen: паника пишется в лог со стеком, клиент
+// получает только errorId.
+func Recover(ctx context.Context, p any) error {
+	id := logging.NewRequestID()
+	slog.ErrorContext(ctx, "graphql panic",
+		"error_id", id,
+		"path", graphql.GetPath(ctx).String(),
+		"panic", fmt.Sprint(p),
+		"stack", string(debug.Stack()),
+	)
+	return &gqlerror.Error{
+		Message:    internalMessage,
+		Path:       graphql.GetPath(ctx),
+		Extensions: map[string]any{"code": CodeInternal, "errorId": id},
+	}
+}
+
+func presentStatus(ctx context.Context, gqlErr *gqlerror.Error, st *status.Status) *gqlerror.Error {
+	code := codeOf(st.Code())
+	switch code {
+	case CodeInternal:
+		return internal(ctx, gqlErr)
+	case CodeUnavailable:
+		// текст статуса может содержать адреса и причины обрыва соединения
+		slog.WarnContext(ctx, "graphql service unavailable", "path", gqlErr.Path.String(), "error", gqlErr.Err)
+		return withExtensions(gqlErr, unavailableMessage, map[string]any{"code": CodeUnavailable})
+	}
+
+	ext := map[string]any{"code": code}
+	var fields []FieldError
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			ext["reason"] = d.GetReason()
+			if f := d.GetMetadata()["field"]; f != "" {
+				ext["field"] = argName(f)
+			}
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				fields = append(fields, FieldError{Field: argName(v.GetField()), Message: v.GetDescription()})
+			}
+		}
+	}
+	if len(fields) > 0 {
+		ext["fields"] = fields
+	}
+	return withExtensions(gqlErr, st.Message(), ext)
+}
+
+func codeOf(c codes.Code) string {
+	switch c {
+	case codes.NotFound:
+		return CodeNotFound
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition, codes.AlreadyExists:
+		return CodeBadUserInput
+	case codes.PermissionDenied:
+		return CodeForbidden
+	case codes.Unauthenticated:
+		return CodeUnauthenticated
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return CodeUnavailable
+	default:
+		return CodeInternal
+	}
+}
+
+// internal прячет причину от клиента и пишет её в лог под errorId.
+func internal(ctx context.Context, gqlErr *gqlerror.Error) *gqlerror.Error {
+	id := logging.NewRequestID()
+	slog.ErrorContext(ctx, "graphql internal error",
+		"error_id", id,
+		"path", gqlErr.Path.String(),
+		"error", gqlErr.Err,
+	)
+	return withExtensions(gqlErr, internalMessage, map[string]any{"code": CodeInternal, "errorId": id})
+}
+
+func withExtensions(gqlErr *gqlerror.Error, msg string, ext map[string]any) *gqlerror.Error {
+	out := *gqlErr
+	out.Message = msg
+	out.Extensions = ext
+	return &out
+}
+
+// argName переводит имя поля protobuf в имя аргумента GraphQL: post_id → postId.
+func argName(field string) string {
+	parts := strings.Split(field, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
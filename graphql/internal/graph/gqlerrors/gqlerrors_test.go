@@ -0,0 +1,68 @@
+package gqlerrors
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want string
+	}{
+		{code: codes.NotFound, want: CodeNotFound},
+		{code: codes.InvalidArgument, want: CodeBadUserInput},
+		{code: codes.OutOfRange, want: CodeBadUserInput},
+		{code: codes.FailedPrecondition, want: CodeBadUserInput},
+		{code: codes.AlreadyExists, want: CodeBadUserInput},
+		{code: codes.PermissionDenied, want: CodeForbidden},
+		{code: codes.Unauthenticated, want: CodeUnauthenticated},
+		{code: codes.Unavailable, want: CodeUnavailable},
+		{code: codes.DeadlineExceeded, want: CodeUnavailable},
+		{code: codes.Internal, want: CodeInternal},
+		{code: codes.Unknown, want: CodeInternal},
+		{code: codes.DataLoss, want: CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			if got := codeOf(tt.code); got != tt.want {
+				t.Fatalf("codeOf(%v) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPresent(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    string
+		wantMessage string
+		wantErrorID bool
+	}{
+		{name: "not found", err: status.Error(codes.NotFound, "post not found"), wantCode: CodeNotFound, wantMessage: "post not found"},
+		{name: "unavailable", err: status.Error(codes.Unavailable, "dial tcp 10.0.0.1:9090: connection refused"), wantCode: CodeUnavailable, wantMessage: unavailableMessage},
+		{name: "deadline exceeded", err: status.Error(codes.DeadlineExceeded, "context deadline exceeded"), wantCode: CodeUnavailable, wantMessage: unavailableMessage},
+		{name: "internal", err: status.Error(codes.Internal, "pq: relation does not exist"), wantCode: CodeInternal, wantMessage: internalMessage, wantErrorID: true},
+		{name: "own error", err: BadUserInput("postId", "invalid post id"), wantCode: CodeBadUserInput, wantMessage: "invalid post id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Present(context.Background(), tt.err)
+			if got.Message != tt.wantMessage {
+				t.Fatalf("message = %q, want %q", got.Message, tt.wantMessage)
+			}
+			if code := got.Extensions["code"]; code != tt.wantCode {
+				t.Fatalf("code = %v, want %q", code, tt.wantCode)
+			}
+			if _, ok := got.Extensions["errorId"]; ok != tt.wantErrorID {
+				t.Fatalf("errorId present = %v, want %v", ok, tt.wantErrorID)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	graphdataloader "github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/dataloader"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/gqlerrors"
 	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/model"
 	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
 	"github.com/graph-gophers/dataloader"
@@ -28,7 +29,7 @@ func ResolveAuthor(ctx context.Context, authorID string) (*model.User, error) {
 		return nil, err
 	}
 	if data == nil {
-		return nil, fmt.Errorf("author not found")
+		return nil, gqlerrors.NotFound("author not found")
 	}
 
 	u := data.(*servicepb.User)
@@ -39,7 +40,91 @@ func ResolveAuthor(ctx context.Context, authorID string) (*model.User, error) {
 	}, nil
 }
 
+// ResolvePost загружает пост через батчевый загрузчик; nil => не найден.
+func ResolvePost(ctx context.Context, postID string) (*model.Post, error) {
+	lds, ok := graphdataloader.FromContext(ctx)
+	if !ok || lds.PostsByIDs == nil {
+		return nil, graphdataloader.ErrNotInjected
+	}
+
+	thunk := lds.PostsByIDs.Load(ctx, dataloader.StringKey(postID))
+	data, err := thunk()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	return ToPost(data.(*servicepb.Post)), nil
+}
+
+// ResolveComment загружает комментарий через батчевый загрузчик; nil => не найден.
+func ResolveComment(ctx context.Context, commentID string) (*model.Comment, error) {
+	lds, ok := graphdataloader.FromContext(ctx)
+	if !ok || lds.CommentsByIDs == nil {
+		return nil, graphdataloader.ErrNotInjected
+	}
+
+	thunk := lds.CommentsByIDs.Load(ctx, dataloader.StringKey(commentID))
+	data, err := thunk()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	return ToComment(data.(*servicepb.Comment)), nil
+}
+
 func MakeCursor(ts *timestamppb.Timestamp, id string) string {
 	raw := ts.AsTime().UTC().Format(time.RFC3339Nano) + "|" + id
 	return base64.RawURLEncoding.EncodeToString([]byte(raw))
 }
+
+// ToPost переводит пост из gRPC-ответа в GraphQL-модель.
+func ToPost(p *servicepb.Post) *model.Post {
+	return &model.Post{
+		ID:             p.GetId(),
+		Text:           p.GetText(),
+		WithoutComment: p.GetWithoutComment(),
+		CreatedAt:      p.GetCreatedAt().AsTime().UTC().Format(time.RFC3339),
+		UpdatedAt:      p.GetUpdatedAt().AsTime().UTC().Format(time.RFC3339),
+		AuthorID:       p.GetAuthorId(),
+	}
+}
+
+// ToCommentAddedEvent оборачивает комментарий в событие подписки с его cursor.
+func ToCommentAddedEvent(c *servicepb.Comment) *model.CommentAddedEvent {
+	return &model.CommentAddedEvent{
+		Comment: ToComment(c),
+		Cursor:  MakeCursor(c.GetCreatedAt(), c.GetId()),
+	}
+}
+
+// ToComment переводит комментарий из gRPC-ответа в GraphQL-модель.
+func ToComment(c *servicepb.Comment) *model.Comment {
+	node := &model.Comment{
+		ID:        c.GetId(),
+		PostID:    c.GetPostId(),
+		Text:      c.GetText(),
+		CreatedAt: c.GetCreatedAt().AsTime().UTC().Format(time.RFC3339),
+		Deleted:   c.GetDeleted(),
+	}
+
+	if c.GetParentId() != "" {
+		pid := c.GetParentId()
+		node.ParentID = &pid
+	}
+	if c.GetAuthorId() != "" {
+		aid := c.GetAuthorId()
+		node.AuthorID = &aid
+	}
+	if c.GetEditedAt() != nil {
+		editedAt := c.GetEditedAt().AsTime().UTC().Format(time.RFC3339)
+		node.EditedAt = &editedAt
+	}
+
+	return node
+}
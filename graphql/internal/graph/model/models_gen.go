@@ -2,31 +2,76 @@
 
 package model
 
+type CommentEvent interface {
+	IsCommentEvent()
+}
+
+type SubscriptionEvent interface {
+	IsSubscriptionEvent()
+	GetDropped() int
+}
+
 type AuthPayload struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
 }
 
 type Comment struct {
 	ID        string             `json:"id"`
 	PostID    string             `json:"postId"`
+	Post      *Post              `json:"post"`
 	ParentID  *string            `json:"parentId,omitempty"`
+	Parent    *Comment           `json:"parent,omitempty"`
 	Text      string             `json:"text"`
 	CreatedAt string             `json:"createdAt"`
-	AuthorID  string             `json:"authorId"`
-	Author    *User              `json:"author"`
+	EditedAt  *string            `json:"editedAt,omitempty"`
+	Deleted   bool               `json:"deleted"`
+	AuthorID  *string            `json:"authorId,omitempty"`
+	Author    *User              `json:"author,omitempty"`
 	Replies   *CommentConnection `json:"replies"`
 }
 
+type CommentAddedEvent struct {
+	Comment *Comment `json:"comment"`
+	Cursor  string   `json:"cursor"`
+	Dropped int      `json:"dropped"`
+}
+
+func (CommentAddedEvent) IsSubscriptionEvent() {}
+func (this CommentAddedEvent) GetDropped() int { return this.Dropped }
+
+func (CommentAddedEvent) IsCommentEvent() {}
+
 type CommentConnection struct {
 	Edges    []*CommentEdge `json:"edges"`
 	PageInfo *PageInfo      `json:"pageInfo"`
 }
 
+type CommentDeletedEvent struct {
+	Comment *Comment `json:"comment"`
+	Dropped int      `json:"dropped"`
+}
+
+func (CommentDeletedEvent) IsSubscriptionEvent() {}
+func (this CommentDeletedEvent) GetDropped() int { return this.Dropped }
+
+func (CommentDeletedEvent) IsCommentEvent() {}
+
 type CommentEdge struct {
 	Cursor string   `json:"cursor"`
 	Node   *Comment `json:"node"`
 }
 
+type CommentEditedEvent struct {
+	Comment *Comment `json:"comment"`
+	Dropped int      `json:"dropped"`
+}
+
+func (CommentEditedEvent) IsSubscriptionEvent() {}
+func (this CommentEditedEvent) GetDropped() int { return this.Dropped }
+
+func (CommentEditedEvent) IsCommentEvent() {}
+
 type Mutation struct {
 }
 
@@ -46,6 +91,14 @@ type Post struct {
 	Comments       *CommentConnection `json:"comments"`
 }
 
+type PostAddedEvent struct {
+	Post    *Post `json:"post"`
+	Dropped int   `json:"dropped"`
+}
+
+func (PostAddedEvent) IsSubscriptionEvent() {}
+func (this PostAddedEvent) GetDropped() int { return this.Dropped }
+
 type PostConnection struct {
 	Edges    []*PostEdge `json:"edges"`
 	PageInfo *PageInfo   `json:"pageInfo"`
@@ -7,31 +7,24 @@ package graph
 
 import (
 	"context"
-	"fmt"
-	"time"
 
 	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/generated"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/gqlerrors"
 	helpergraph "github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/helper"
 	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/model"
-	"github.com/Parnishkaspb/ozon_posts_graphql/internal/helper"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/subscriptions"
 	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"github.com/google/uuid"
 )
 
 // CreatePost is the resolver for the createPost field.
 func (r *mutationResolver) CreatePost(ctx context.Context, text string, withoutComment *bool) (*model.Post, error) {
-	u, ok := helper.FromContext(ctx)
-
-	if !ok {
-		return nil, fmt.Errorf("unauthorized")
-	}
-
 	wc := false
 	if withoutComment != nil {
 		wc = *withoutComment
 	}
 
 	resp, err := r.PostSvc.CreatePost(ctx, &servicepb.CreatePostRequest{
-		AuthorId:       u.ID.String(),
 		Text:           text,
 		WithoutComment: wc,
 	})
@@ -40,14 +33,35 @@ func (r *mutationResolver) CreatePost(ctx context.Context, text string, withoutC
 		return nil, err
 	}
 
-	return &model.Post{
-		ID:             resp.Post.Id,
-		Text:           resp.Post.Text,
-		WithoutComment: resp.Post.WithoutComment,
-		CreatedAt:      resp.GetPost().GetCreatedAt().AsTime().Format(time.RFC3339),
-		UpdatedAt:      resp.GetPost().GetUpdatedAt().AsTime().Format(time.RFC3339),
-		AuthorID:       resp.Post.AuthorId,
-	}, nil
+	return helpergraph.ToPost(resp.GetPost()), nil
+}
+
+// UpdatePost is the resolver for the updatePost field.
+func (r *mutationResolver) UpdatePost(ctx context.Context, id string, text *string, withoutComment *bool) (*model.Post, error) {
+	resp, err := r.PostSvc.UpdatePost(ctx, &servicepb.UpdatePostRequest{
+		Id:             id,
+		Text:           text,
+		WithoutComment: withoutComment,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return helpergraph.ToPost(resp.GetPost()), nil
+}
+
+// DeletePost is the resolver for the deletePost field.
+func (r *mutationResolver) DeletePost(ctx context.Context, id string) (bool, error) {
+	_, err := r.PostSvc.DeletePost(ctx, &servicepb.DeletePostRequest{
+		Id: id,
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
 // Author is the resolver for the author field.
@@ -73,22 +87,9 @@ func (r *postResolver) Comments(ctx context.Context, obj *model.Post, first int,
 
 	edges := make([]*model.CommentEdge, 0, len(resp.GetComments()))
 	for _, c := range resp.GetComments() {
-		node := &model.Comment{
-			ID:        c.GetId(),
-			PostID:    c.GetPostId(),
-			Text:      c.GetText(),
-			CreatedAt: c.GetCreatedAt().AsTime().UTC().Format(time.RFC3339),
-			AuthorID:  c.GetAuthorId(),
-		}
-
-		if c.GetParentId() != "" {
-			pid := c.GetParentId()
-			node.ParentID = &pid
-		}
-
 		edges = append(edges, &model.CommentEdge{
 			Cursor: helpergraph.MakeCursor(c.GetCreatedAt(), c.GetId()),
-			Node:   node,
+			Node:   helpergraph.ToComment(c),
 		})
 	}
 
@@ -125,18 +126,9 @@ func (r *queryResolver) Posts(ctx context.Context, first int, after *string) (*m
 
 	edges := make([]*model.PostEdge, 0, len(posts))
 	for _, p := range posts {
-		node := &model.Post{
-			ID:             p.GetId(),
-			Text:           p.GetText(),
-			WithoutComment: p.GetWithoutComment(),
-			CreatedAt:      p.GetCreatedAt().AsTime().UTC().Format(time.RFC3339),
-			UpdatedAt:      p.GetUpdatedAt().AsTime().UTC().Format(time.RFC3339),
-			AuthorID:       p.GetAuthorId(),
-		}
-
 		edges = append(edges, &model.PostEdge{
 			Cursor: helpergraph.MakeCursor(p.GetCreatedAt(), p.GetId()),
-			Node:   node,
+			Node:   helpergraph.ToPost(p),
 		})
 	}
 
@@ -165,14 +157,47 @@ func (r *queryResolver) Post(ctx context.Context, id string) (*model.Post, error
 		return nil, nil
 	}
 
-	return &model.Post{
-		ID:             p.GetId(),
-		Text:           p.GetText(),
-		WithoutComment: p.GetWithoutComment(),
-		CreatedAt:      p.GetCreatedAt().AsTime().UTC().Format(time.RFC3339),
-		UpdatedAt:      p.GetUpdatedAt().AsTime().UTC().Format(time.RFC3339),
-		AuthorID:       p.GetAuthorId(),
-	}, nil
+	return helpergraph.ToPost(p), nil
+}
+
+// PostsByIds is the resolver for the postsByIds field.
+func (r *queryResolver) PostsByIds(ctx context.Context, ids []string) ([]*model.Post, error) {
+	if len(ids) == 0 {
+		return []*model.Post{}, nil
+	}
+
+	// сервис возвращает UUID в каноническом виде, по нему и сопоставляем ответ
+	canonical := make([]string, len(ids))
+	for i, id := range ids {
+		uid, err := uuid.Parse(id)
+		if err != nil {
+			return nil, gqlerrors.BadUserInput("ids", "invalid post id")
+		}
+		canonical[i] = uid.String()
+	}
+
+	resp, err := r.PostSvc.GetPosts(ctx, &servicepb.GetPostsRequest{Ids: canonical})
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*servicepb.Post, len(resp.GetPosts()))
+	for _, p := range resp.GetPosts() {
+		byID[p.GetId()] = p
+	}
+
+	result := make([]*model.Post, len(canonical))
+	for i, id := range canonical {
+		if p, ok := byID[id]; ok {
+			result[i] = helpergraph.ToPost(p)
+		}
+	}
+	return result, nil
+}
+
+// PostAdded is the resolver for the postAdded field.
+func (r *subscriptionResolver) PostAdded(ctx context.Context) (<-chan *model.PostAddedEvent, error) {
+	return subscriptions.Watch[*model.PostAddedEvent](ctx, r.SubSvc, subscriptions.FeedTopic, "")
 }
 
 // Post returns generated.PostResolver implementation.
@@ -16,5 +16,5 @@ type Resolver struct {
 	PostSvc    servicepb.PostServiceClient
 	CommentSvc servicepb.CommentServiceClient
 
-	SubSvc *subscriptions.Subscription
+	SubSvc subscriptions.Broker
 }
@@ -0,0 +1,16 @@
+package subscriptions
+
+import (
+	"context"
+
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/model"
+)
+
+// Broker доставляет события подписок: новые посты, добавление, правку и
+// удаление комментариев. Источник событий — только сервис.
+type Broker interface {
+	// Subscribe возвращает канал событий kinds из topic; подписка снимается при
+	// отмене ctx. Непустой after — cursor, с которого сначала досылаются
+	// пропущенные комментарии (только вместе с KindCommentAdded).
+	Subscribe(ctx context.Context, topic Topic, kinds Kind, after string) (<-chan model.SubscriptionEvent, error)
+}
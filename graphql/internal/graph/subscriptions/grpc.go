@@ -0,0 +1,133 @@
+package subscriptions
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/gqlerrors"
+	helpergraph "github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/helper"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/model"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/metrics"
+	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CommentWatcher — часть CommentServiceClient с потоком событий о комментариях.
+type CommentWatcher interface {
+	WatchComments(ctx context.Context, in *servicepb.WatchCommentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[servicepb.WatchCommentsResponse], error)
+}
+
+// PostWatcher — часть PostServiceClient с потоком новых постов.
+type PostWatcher interface {
+	WatchPosts(ctx context.Context, in *servicepb.WatchPostsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[servicepb.WatchPostsResponse], error)
+}
+
+// grpcMetrics — метка потоков WatchComments/WatchPosts и потерь, о которых сообщил сервис.
+const grpcMetrics = "grpc"
+
+// GRPC открывает на каждую подписку один поток сервиса только с нужными ей
+// типами событий: события приходят от любой реплики и не требуют доступа
+// gateway к базе, а потери сервис считает уже после фильтрации.
+type GRPC struct {
+	comments CommentWatcher
+	posts    PostWatcher
+}
+
+var _ Broker = (*GRPC)(nil)
+
+func NewGRPC(comments CommentWatcher, posts PostWatcher) *GRPC {
+	return &GRPC{comments: comments, posts: posts}
+}
+
+var commentEventTypes = map[Kind]servicepb.CommentEventType{
+	KindCommentAdded:   servicepb.CommentEventType_COMMENT_EVENT_TYPE_CREATED,
+	KindCommentEdited:  servicepb.CommentEventType_COMMENT_EVENT_TYPE_EDITED,
+	KindCommentDeleted: servicepb.CommentEventType_COMMENT_EVENT_TYPE_DELETED,
+}
+
+func (g *GRPC) Subscribe(ctx context.Context, topic Topic, kinds Kind, after string) (<-chan model.SubscriptionEvent, error) {
+	if topic == FeedTopic {
+		stream, err := g.posts.WatchPosts(ctx, &servicepb.WatchPostsRequest{})
+		if err != nil {
+			return nil, err
+		}
+		return forward(ctx, stream, func(resp *servicepb.WatchPostsResponse) (model.SubscriptionEvent, int) {
+			dropped := int(resp.GetDropped())
+			return &model.PostAddedEvent{Post: helpergraph.ToPost(resp.GetPost()), Dropped: dropped}, dropped
+		}), nil
+	}
+
+	// ошибку валидации сервис вернул бы только при первом Recv, уже после ответа клиенту
+	if _, err := uuid.Parse(topic.PostID); err != nil {
+		return nil, gqlerrors.BadUserInput("postId", "invalid post id")
+	}
+
+	req := &servicepb.WatchCommentsRequest{PostId: topic.PostID, ParentId: topic.ParentID}
+	for _, kind := range []Kind{KindCommentAdded, KindCommentEdited, KindCommentDeleted} {
+		if kinds.Has(kind) {
+			req.Types = append(req.Types, commentEventTypes[kind])
+		}
+	}
+	// история и пропуски считает сервис: WatchComments досылает всё после after
+	if kinds.Has(KindCommentAdded) {
+		req.After = after
+	}
+
+	stream, err := g.comments.WatchComments(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return forward(ctx, stream, func(resp *servicepb.WatchCommentsResponse) (model.SubscriptionEvent, int) {
+		return toCommentEvent(resp), int(resp.GetDropped())
+	}), nil
+}
+
+func toCommentEvent(resp *servicepb.WatchCommentsResponse) model.SubscriptionEvent {
+	dropped := int(resp.GetDropped())
+	switch resp.GetType() {
+	case servicepb.CommentEventType_COMMENT_EVENT_TYPE_EDITED:
+		return &model.CommentEditedEvent{Comment: helpergraph.ToComment(resp.GetComment()), Dropped: dropped}
+	case servicepb.CommentEventType_COMMENT_EVENT_TYPE_DELETED:
+		return &model.CommentDeletedEvent{Comment: helpergraph.ToComment(resp.GetComment()), Dropped: dropped}
+	default:
+		// сервисы без types присылают только новые комментарии
+		ev := helpergraph.ToCommentAddedEvent(resp.GetComment())
+		ev.Dropped = dropped
+		return ev
+	}
+}
+
+// forward переносит ответы потока в канал подписки; канал закрывается, когда
+// поток завершился (отмена клиента, остановка сервиса) или отменён ctx.
+func forward[R any](ctx context.Context, stream grpc.ServerStreamingClient[R], convert func(*R) (model.SubscriptionEvent, int)) <-chan model.SubscriptionEvent {
+	ch := make(chan model.SubscriptionEvent, subscriberBuffer)
+	metrics.SubscriptionOpened(grpcMetrics)
+	go func() {
+		defer close(ch)
+		defer metrics.SubscriptionClosed(grpcMetrics)
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				if !errors.Is(err, io.EOF) && status.Code(err) != codes.Canceled {
+					slog.ErrorContext(ctx, "subscriptions: watch stream", "error", err)
+				}
+				return
+			}
+
+			ev, dropped := convert(resp)
+			metrics.SubscriptionDropped(grpcMetrics, dropped)
+
+			select {
+			case ch <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,148 @@
+package subscriptions
+
+import (
+	"context"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/model"
+	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"google.golang.org/grpc"
+)
+
+type fakeStream[R any] struct {
+	grpc.ClientStream
+	resps []*R
+}
+
+func (s *fakeStream[R]) Recv() (*R, error) {
+	if len(s.resps) == 0 {
+		return nil, io.EOF
+	}
+	resp := s.resps[0]
+	s.resps = s.resps[1:]
+	return resp, nil
+}
+
+type fakeWatcher struct {
+	comments []*servicepb.WatchCommentsResponse
+	posts    []*servicepb.WatchPostsResponse
+	reqs     []*servicepb.WatchCommentsRequest
+}
+
+func (f *fakeWatcher) WatchComments(ctx context.Context, in *servicepb.WatchCommentsRequest, _ ...grpc.CallOption) (grpc.ServerStreamingClient[servicepb.WatchCommentsResponse], error) {
+	f.reqs = append(f.reqs, in)
+	return &fakeStream[servicepb.WatchCommentsResponse]{resps: f.comments}, nil
+}
+
+func (f *fakeWatcher) WatchPosts(ctx context.Context, in *servicepb.WatchPostsRequest, _ ...grpc.CallOption) (grpc.ServerStreamingClient[servicepb.WatchPostsResponse], error) {
+	return &fakeStream[servicepb.WatchPostsResponse]{resps: f.posts}, nil
+}
+
+const testPostID = "6f1f6a52-1f55-4b7a-9d0e-0a3c1a2f9c11"
+
+func TestGRPC_RequestsOnlyWatchedTypes(t *testing.T) {
+	tests := []struct {
+		name      string
+		kinds     Kind
+		after     string
+		wantTypes []servicepb.CommentEventType
+		wantAfter string
+	}{
+		{
+			name:      "added with cursor",
+			kinds:     KindCommentAdded,
+			after:     "cur",
+			wantTypes: []servicepb.CommentEventType{servicepb.CommentEventType_COMMENT_EVENT_TYPE_CREATED},
+			wantAfter: "cur",
+		},
+		{
+			name:      "edited ignores cursor",
+			kinds:     KindCommentEdited,
+			after:     "cur",
+			wantTypes: []servicepb.CommentEventType{servicepb.CommentEventType_COMMENT_EVENT_TYPE_EDITED},
+		},
+		{
+			name:  "all comment events",
+			kinds: KindComments,
+			wantTypes: []servicepb.CommentEventType{
+				servicepb.CommentEventType_COMMENT_EVENT_TYPE_CREATED,
+				servicepb.CommentEventType_COMMENT_EVENT_TYPE_EDITED,
+				servicepb.CommentEventType_COMMENT_EVENT_TYPE_DELETED,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &fakeWatcher{}
+			if _, err := NewGRPC(w, w).Subscribe(context.Background(), PostTopic(testPostID), tt.kinds, tt.after); err != nil {
+				t.Fatal(err)
+			}
+			if len(w.reqs) != 1 {
+				t.Fatalf("opened %d streams, want 1", len(w.reqs))
+			}
+			if got := w.reqs[0]; !slices.Equal(got.GetTypes(), tt.wantTypes) || got.GetAfter() != tt.wantAfter {
+				t.Fatalf("request types=%v after=%q, want %v %q", got.GetTypes(), got.GetAfter(), tt.wantTypes, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestGRPC_ConvertsEvents(t *testing.T) {
+	w := &fakeWatcher{comments: []*servicepb.WatchCommentsResponse{
+		{Comment: &servicepb.Comment{Id: "c1", PostId: testPostID}, Type: servicepb.CommentEventType_COMMENT_EVENT_TYPE_CREATED},
+		{Comment: &servicepb.Comment{Id: "c1", PostId: testPostID}, Type: servicepb.CommentEventType_COMMENT_EVENT_TYPE_EDITED, Dropped: 2},
+		{Comment: &servicepb.Comment{Id: "c1", PostId: testPostID}, Type: servicepb.CommentEventType_COMMENT_EVENT_TYPE_DELETED},
+	}}
+
+	ch, err := Watch[model.CommentEvent](context.Background(), NewGRPC(w, w), PostTopic(testPostID), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for ev := range ch {
+		switch e := ev.(type) {
+		case *model.CommentAddedEvent:
+			got = append(got, "added")
+		case *model.CommentEditedEvent:
+			if e.Dropped != 2 {
+				t.Fatalf("edited dropped = %d, want 2", e.Dropped)
+			}
+			got = append(got, "edited")
+		case *model.CommentDeletedEvent:
+			got = append(got, "deleted")
+		}
+	}
+	if want := []string{"added", "edited", "deleted"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestGRPC_Feed(t *testing.T) {
+	w := &fakeWatcher{posts: []*servicepb.WatchPostsResponse{{Post: &servicepb.Post{Id: "p1"}, Dropped: 1}}}
+
+	ch, err := Watch[*model.PostAddedEvent](context.Background(), NewGRPC(w, w), FeedTopic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev := <-ch
+	if ev == nil || ev.Post.ID != "p1" || ev.Dropped != 1 {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+	if len(w.reqs) != 0 {
+		t.Fatalf("feed must not open WatchComments")
+	}
+}
+
+func TestGRPC_InvalidPostID(t *testing.T) {
+	w := &fakeWatcher{}
+	if _, err := NewGRPC(w, w).Subscribe(context.Background(), PostTopic("nope"), KindCommentAdded, ""); err == nil {
+		t.Fatal("expected error for invalid post id")
+	}
+	if len(w.reqs) != 0 {
+		t.Fatalf("stream must not be opened for invalid post id")
+	}
+}
@@ -0,0 +1,172 @@
+package subscriptions
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/gqlerrors"
+	helpergraph "github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/helper"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/model"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/metrics"
+	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"github.com/google/uuid"
+)
+
+// MemoryComments — часть CommentServiceClient для брокера memory: поток
+// событий поста и история после cursor.
+type MemoryComments interface {
+	CommentWatcher
+	History
+}
+
+// Memory раздаёт события из хаба в памяти gateway. Хаб питается потоками
+// сервиса: на ленту и на каждый пост, у которого есть подписчики, открыт один
+// поток WatchPosts/WatchComments, сколько бы клиентов его ни слушало.
+// Поток закрывается вместе с последней подпиской.
+type Memory struct {
+	comments MemoryComments
+	posts    PostWatcher
+	hub      *Subscription
+
+	mu    sync.Mutex
+	feeds map[string]*feed // ключ — PostID, "" — лента
+}
+
+type feed struct {
+	refs   int
+	cancel context.CancelFunc
+}
+
+var _ Broker = (*Memory)(nil)
+
+func NewMemory(comments MemoryComments, posts PostWatcher) *Memory {
+	return &Memory{comments: comments, posts: posts, hub: New(comments), feeds: make(map[string]*feed)}
+}
+
+func (m *Memory) Subscribe(ctx context.Context, topic Topic, kinds Kind, after string) (<-chan model.SubscriptionEvent, error) {
+	if topic != FeedTopic {
+		if _, err := uuid.Parse(topic.PostID); err != nil {
+			return nil, gqlerrors.BadUserInput("postId", "invalid post id")
+		}
+	}
+
+	if err := m.acquire(topic.PostID); err != nil {
+		return nil, err
+	}
+	ch, err := m.hub.Subscribe(ctx, topic, kinds, after)
+	if err != nil {
+		m.release(topic.PostID)
+		return nil, err
+	}
+	context.AfterFunc(ctx, func() { m.release(topic.PostID) })
+	return ch, nil
+}
+
+// acquire открывает поток сервиса для postID, если его ещё нет; ошибка
+// открытия первого потока возвращается клиенту при подписке.
+func (m *Memory) acquire(postID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if f, ok := m.feeds[postID]; ok {
+		f.refs++
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	recv, err := m.open(ctx, postID)
+	if err != nil {
+		cancel()
+		return err
+	}
+	m.feeds[postID] = &feed{refs: 1, cancel: cancel}
+	go m.run(ctx, postID, recv)
+	return nil
+}
+
+func (m *Memory) release(postID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, ok := m.feeds[postID]
+	if !ok {
+		return
+	}
+	if f.refs--; f.refs == 0 {
+		f.cancel()
+		delete(m.feeds, postID)
+	}
+}
+
+// open открывает поток сервиса и возвращает функцию чтения следующего события.
+func (m *Memory) open(ctx context.Context, postID string) (func() (model.SubscriptionEvent, error), error) {
+	if postID == "" {
+		stream, err := m.posts.WatchPosts(ctx, &servicepb.WatchPostsRequest{})
+		if err != nil {
+			return nil, err
+		}
+		return func() (model.SubscriptionEvent, error) {
+			resp, err := stream.Recv()
+			if err != nil {
+				return nil, err
+			}
+			metrics.SubscriptionDropped(grpcMetrics, int(resp.GetDropped()))
+			return &model.PostAddedEvent{Post: helpergraph.ToPost(resp.GetPost())}, nil
+		}, nil
+	}
+
+	// все типы сразу: подписчики поста и его веток делят один поток,
+	// а лишние события хаб отфильтрует сам
+	stream, err := m.comments.WatchComments(ctx, &servicepb.WatchCommentsRequest{
+		PostId: postID,
+		Types: []servicepb.CommentEventType{
+			servicepb.CommentEventType_COMMENT_EVENT_TYPE_CREATED,
+			servicepb.CommentEventType_COMMENT_EVENT_TYPE_EDITED,
+			servicepb.CommentEventType_COMMENT_EVENT_TYPE_DELETED,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func() (model.SubscriptionEvent, error) {
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		metrics.SubscriptionDropped(grpcMetrics, int(resp.GetDropped()))
+		return toCommentEvent(resp), nil
+	}, nil
+}
+
+// run переносит события потока в хаб до отмены ctx, переоткрывая поток,
+// если сервис его завершил.
+func (m *Memory) run(ctx context.Context, postID string, recv func() (model.SubscriptionEvent, error)) {
+	for {
+		ev, err := recv()
+		if err == nil {
+			m.hub.publish(ev)
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if !errors.Is(err, io.EOF) {
+			slog.Warn("subscriptions: watch stream failed, reopening", "post_id", postID, "error", err, "delay", reconnectDelay)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+
+		if recv, err = m.open(ctx, postID); err != nil {
+			// ошибка открытия уйдёт в лог на следующем шаге, и попытка повторится
+			recv = func() (model.SubscriptionEvent, error) { return nil, err }
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package subscriptions
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/model"
+	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"google.golang.org/grpc"
+)
+
+// chanStream отдаёт ответы из ch, пока не отменён ctx потока.
+type chanStream[R any] struct {
+	grpc.ClientStream
+	ctx context.Context
+	ch  chan *R
+}
+
+func (s *chanStream[R]) Recv() (*R, error) {
+	select {
+	case resp := <-s.ch:
+		return resp, nil
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+// fakeMemoryComments запоминает открытые потоки WatchComments; все они читают
+// один канал events.
+type fakeMemoryComments struct {
+	fakeHistory
+	events chan *servicepb.WatchCommentsResponse
+
+	mu      sync.Mutex
+	streams []context.Context
+}
+
+func (f *fakeMemoryComments) WatchComments(ctx context.Context, in *servicepb.WatchCommentsRequest, _ ...grpc.CallOption) (grpc.ServerStreamingClient[servicepb.WatchCommentsResponse], error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.streams = append(f.streams, ctx)
+	return &chanStream[servicepb.WatchCommentsResponse]{ctx: ctx, ch: f.events}, nil
+}
+
+func (f *fakeMemoryComments) opened() []context.Context {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.streams
+}
+
+func receive(t *testing.T, ch <-chan model.SubscriptionEvent) model.SubscriptionEvent {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("no event")
+		return nil
+	}
+}
+
+func TestMemory_SharesStreamPerPost(t *testing.T) {
+	comments := &fakeMemoryComments{events: make(chan *servicepb.WatchCommentsResponse)}
+	broker := NewMemory(comments, &fakeWatcher{})
+
+	postCtx, cancelPost := context.WithCancel(context.Background())
+	defer cancelPost()
+	threadCtx, cancelThread := context.WithCancel(context.Background())
+	defer cancelThread()
+
+	post, err := broker.Subscribe(postCtx, PostTopic(testPostID), KindComments, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	thread, err := broker.Subscribe(threadCtx, ThreadTopic(testPostID, "c0"), KindCommentAdded, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(comments.opened()); n != 1 {
+		t.Fatalf("opened %d streams, want 1", n)
+	}
+
+	reply := &servicepb.Comment{Id: "c1", PostId: testPostID, ParentId: "c0"}
+	comments.events <- &servicepb.WatchCommentsResponse{Comment: reply, Type: servicepb.CommentEventType_COMMENT_EVENT_TYPE_CREATED}
+	comments.events <- &servicepb.WatchCommentsResponse{Comment: reply, Type: servicepb.CommentEventType_COMMENT_EVENT_TYPE_EDITED}
+
+	if _, ok := receive(t, post).(*model.CommentAddedEvent); !ok {
+		t.Fatal("post subscriber: want comment added")
+	}
+	if _, ok := receive(t, post).(*model.CommentEditedEvent); !ok {
+		t.Fatal("post subscriber: want comment edited")
+	}
+	if _, ok := receive(t, thread).(*model.CommentAddedEvent); !ok {
+		t.Fatal("thread subscriber: want comment added")
+	}
+
+	// поток живёт, пока есть хотя бы один подписчик поста
+	stream := comments.opened()[0]
+	cancelPost()
+	cancelThread()
+	select {
+	case <-stream.Done():
+	case <-time.After(time.Second):
+		t.Fatal("stream not closed after the last subscriber left")
+	}
+
+	again, cancelAgain := context.WithCancel(context.Background())
+	defer cancelAgain()
+	if _, err := broker.Subscribe(again, PostTopic(testPostID), KindCommentAdded, ""); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(comments.opened()); n != 2 {
+		t.Fatalf("opened %d streams, want a new one after resubscribe", n)
+	}
+}
+
+func TestMemory_InvalidPostID(t *testing.T) {
+	comments := &fakeMemoryComments{}
+	if _, err := NewMemory(comments, &fakeWatcher{}).Subscribe(context.Background(), PostTopic("nope"), KindCommentAdded, ""); err == nil {
+		t.Fatal("expected error for invalid post id")
+	}
+	if n := len(comments.opened()); n != 0 {
+		t.Fatalf("stream must not be opened for invalid post id")
+	}
+}
@@ -0,0 +1,161 @@
+package subscriptions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	helpergraph "github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/helper"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/model"
+	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc"
+)
+
+// CommentsChannel совпадает с каналом, в который сервис делает pg_notify.
+const CommentsChannel = "comment_events"
+
+// Значения kind в NOTIFY сервиса; пустой kind присылали прежние версии
+// сервиса, он означает новый комментарий.
+var notifyKinds = map[string]Kind{
+	"":                KindCommentAdded,
+	"comment_created": KindCommentAdded,
+	"comment_edited":  KindCommentEdited,
+	"comment_deleted": KindCommentDeleted,
+	"post_created":    KindPostAdded,
+}
+
+const (
+	reconnectDelay = 2 * time.Second
+	loadTimeout    = 3 * time.Second
+)
+
+// CommentLoader — часть CommentServiceClient для дочитывания комментария по id
+// и истории после cursor.
+type CommentLoader interface {
+	History
+	GetCommentsByIDs(ctx context.Context, in *servicepb.GetCommentsByIDsRequest, opts ...grpc.CallOption) (*servicepb.GetCommentsByIDsResponse, error)
+}
+
+// PostLoader — часть PostServiceClient для дочитывания поста по id.
+type PostLoader interface {
+	GetPosts(ctx context.Context, in *servicepb.GetPostsRequest, opts ...grpc.CallOption) (*servicepb.GetPostsResponse, error)
+}
+
+// Postgres получает события сервиса через LISTEN и раздаёт их локальным
+// подписчикам, поэтому работает с любым числом реплик gateway и видит
+// изменения, сделанные напрямую через gRPC.
+type Postgres struct {
+	dsn      string
+	comments CommentLoader
+	posts    PostLoader
+	hub      *Subscription
+}
+
+type notification struct {
+	Kind     string `json:"kind"`
+	ID       string `json:"id"`
+	PostID   string `json:"post_id"`
+	ParentID string `json:"parent_id,omitempty"`
+}
+
+var _ Broker = (*Postgres)(nil)
+
+func NewPostgres(dsn string, comments CommentLoader, posts PostLoader) *Postgres {
+	return &Postgres{dsn: dsn, comments: comments, posts: posts, hub: New(comments)}
+}
+
+func (p *Postgres) Subscribe(ctx context.Context, topic Topic, kinds Kind, after string) (<-chan model.SubscriptionEvent, error) {
+	return p.hub.Subscribe(ctx, topic, kinds, after)
+}
+
+// Run слушает канал до отмены ctx, переподключаясь при обрыве соединения.
+func (p *Postgres) Run(ctx context.Context) {
+	for {
+		err := p.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Warn("subscriptions: listen failed, reconnecting", "channel", CommentsChannel, "error", err, "delay", reconnectDelay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (p *Postgres) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, p.dsn)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+CommentsChannel); err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		p.dispatch(ctx, n.Payload)
+	}
+}
+
+func (p *Postgres) dispatch(ctx context.Context, payload string) {
+	var n notification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		slog.Error("subscriptions: bad event payload", "payload", payload, "error", err)
+		return
+	}
+	kind, ok := notifyKinds[n.Kind]
+	if !ok {
+		slog.Warn("subscriptions: unknown event kind", "kind", n.Kind)
+		return
+	}
+
+	topics := []Topic{FeedTopic}
+	if kind != KindPostAdded {
+		topics = []Topic{PostTopic(n.PostID), ThreadTopic(n.PostID, n.ParentID)}
+	}
+	if !p.hub.HasSubscribers(kind, topics...) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, loadTimeout)
+	defer cancel()
+
+	if kind == KindPostAdded {
+		resp, err := p.posts.GetPosts(ctx, &servicepb.GetPostsRequest{Ids: []string{n.ID}})
+		if err != nil {
+			slog.Error("subscriptions: load post", "post_id", n.ID, "error", err)
+			return
+		}
+		for _, post := range resp.GetPosts() {
+			p.hub.publish(&model.PostAddedEvent{Post: helpergraph.ToPost(post)})
+		}
+		return
+	}
+
+	resp, err := p.comments.GetCommentsByIDs(ctx, &servicepb.GetCommentsByIDsRequest{Ids: []string{n.ID}})
+	if err != nil {
+		slog.Error("subscriptions: load comment", "comment_id", n.ID, "error", err)
+		return
+	}
+	for _, c := range resp.GetComments() {
+		switch kind {
+		case KindCommentEdited:
+			p.hub.publish(&model.CommentEditedEvent{Comment: helpergraph.ToComment(c)})
+		case KindCommentDeleted:
+			p.hub.publish(&model.CommentDeletedEvent{Comment: helpergraph.ToComment(c)})
+		default:
+			p.hub.publish(helpergraph.ToCommentAddedEvent(c))
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package subscriptions
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	helpergraph "github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/helper"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/model"
+	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"google.golang.org/grpc"
+)
+
+const replayPageSize = 100
+
+// History — часть CommentServiceClient для дочитывания комментариев после cursor.
+type History interface {
+	GetCommentsSince(ctx context.Context, in *servicepb.GetCommentsSinceRequest, opts ...grpc.CallOption) (*servicepb.GetCommentsSinceResponse, error)
+}
+
+// withReplay сначала отдаёт комментарии topic новее after, затем события из live,
+// пропуская уже отправленные. Первая страница читается сразу, чтобы ошибка
+// (например, битый cursor) вернулась клиенту при подписке.
+func withReplay(ctx context.Context, history History, topic Topic, after string, live <-chan model.SubscriptionEvent) (<-chan model.SubscriptionEvent, error) {
+	if topic == FeedTopic {
+		return nil, fmt.Errorf("replay is supported only for comments")
+	}
+
+	req := &servicepb.GetCommentsSinceRequest{
+		PostId:   topic.PostID,
+		ParentId: topic.ParentID,
+		After:    after,
+		First:    replayPageSize,
+	}
+	page, err := history.GetCommentsSince(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan model.SubscriptionEvent, subscriberBuffer)
+	go func() {
+		defer close(out)
+
+		send := func(ev model.SubscriptionEvent) bool {
+			select {
+			case out <- ev:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		replayed := make(map[string]struct{})
+		for {
+			for _, c := range page.GetComments() {
+				replayed[c.GetId()] = struct{}{}
+				if !send(helpergraph.ToCommentAddedEvent(c)) {
+					return
+				}
+			}
+			if !page.GetHasNextPage() {
+				break
+			}
+
+			req.After = page.GetEndCursor()
+			if page, err = history.GetCommentsSince(ctx, req); err != nil {
+				slog.ErrorContext(ctx, "subscriptions: replay comments", "post_id", topic.PostID, "error", err)
+				return
+			}
+		}
+
+		for ev := range live {
+			if added, ok := ev.(*model.CommentAddedEvent); ok {
+				if _, seen := replayed[added.Comment.ID]; seen {
+					continue
+				}
+			}
+			if !send(ev) {
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
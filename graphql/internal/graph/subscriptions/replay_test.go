@@ -0,0 +1,79 @@
+package subscriptions
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/model"
+	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"google.golang.org/grpc"
+)
+
+// fakeHistory отдаёт pages по очереди, запоминая cursor каждого запроса.
+type fakeHistory struct {
+	pages  []*servicepb.GetCommentsSinceResponse
+	err    error
+	afters []string
+}
+
+func (f *fakeHistory) GetCommentsSince(ctx context.Context, in *servicepb.GetCommentsSinceRequest, _ ...grpc.CallOption) (*servicepb.GetCommentsSinceResponse, error) {
+	f.afters = append(f.afters, in.GetAfter())
+	if f.err != nil {
+		return nil, f.err
+	}
+	page := f.pages[0]
+	f.pages = f.pages[1:]
+	return page, nil
+}
+
+func TestWithReplay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	history := &fakeHistory{pages: []*servicepb.GetCommentsSinceResponse{
+		{Comments: []*servicepb.Comment{{Id: "c1", PostId: "p1"}}, HasNextPage: true, EndCursor: "cur1"},
+		{Comments: []*servicepb.Comment{{Id: "c2", PostId: "p1"}}},
+	}}
+
+	live := make(chan model.SubscriptionEvent, 3)
+	// c2 пришёл и из истории, и живым событием — отдать его нужно один раз
+	live <- &model.CommentAddedEvent{Comment: &model.Comment{ID: "c2", PostID: "p1"}}
+	live <- &model.CommentEditedEvent{Comment: &model.Comment{ID: "c2", PostID: "p1"}}
+	live <- &model.CommentAddedEvent{Comment: &model.Comment{ID: "c3", PostID: "p1"}}
+	close(live)
+
+	out, err := withReplay(ctx, history, PostTopic("p1"), "cur0", live)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for ev := range out {
+		switch e := ev.(type) {
+		case *model.CommentAddedEvent:
+			got = append(got, "added:"+e.Comment.ID)
+		case *model.CommentEditedEvent:
+			got = append(got, "edited:"+e.Comment.ID)
+		}
+	}
+
+	if want := []string{"added:c1", "added:c2", "edited:c2", "added:c3"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if want := []string{"cur0", "cur1"}; !slices.Equal(history.afters, want) {
+		t.Fatalf("requested cursors %v, want %v", history.afters, want)
+	}
+}
+
+func TestWithReplay_FirstPageError(t *testing.T) {
+	history := &fakeHistory{err: errors.New("invalid cursor")}
+
+	if _, err := withReplay(context.Background(), history, PostTopic("p1"), "bad", nil); err == nil {
+		t.Fatal("expected error from the first page")
+	}
+	if _, err := withReplay(context.Background(), history, FeedTopic, "cur", nil); err == nil {
+		t.Fatal("expected error for the feed topic")
+	}
+}
@@ -1,57 +1,121 @@
 package subscriptions
 
 import (
+	"context"
 	"sync"
+	"sync/atomic"
 
 	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/model"
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/metrics"
 )
 
+const subscriberBuffer = 16
+
+// hubMetrics — метка хаба в метриках (брокеры memory и postgres).
+const hubMetrics = "hub"
+
+type subscriber struct {
+	ch    chan model.SubscriptionEvent
+	kinds Kind
+	// dropped — сколько событий kinds не влезло в буфер с последней доставки
+	dropped atomic.Int64
+}
+
+// Subscription — хаб в памяти процесса: раздаёт локальным подписчикам
+// события, которые Memory и Postgres получают от сервиса.
 type Subscription struct {
-	mu   sync.RWMutex
-	subs map[string]map[chan *model.Comment]struct{}
+	mu      sync.RWMutex
+	subs    map[Topic]map[*subscriber]struct{}
+	history History
 }
 
-func New() *Subscription {
+func New(history History) *Subscription {
 	return &Subscription{
-		subs: make(map[string]map[chan *model.Comment]struct{}),
+		subs:    make(map[Topic]map[*subscriber]struct{}),
+		history: history,
 	}
 }
 
-func (ps *Subscription) Subscribe(postID string) chan *model.Comment {
-	ch := make(chan *model.Comment, 16)
+func (ps *Subscription) Subscribe(ctx context.Context, topic Topic, kinds Kind, after string) (<-chan model.SubscriptionEvent, error) {
+	sub := &subscriber{ch: make(chan model.SubscriptionEvent, subscriberBuffer), kinds: kinds}
 
 	ps.mu.Lock()
-	defer ps.mu.Unlock()
+	if ps.subs[topic] == nil {
+		ps.subs[topic] = make(map[*subscriber]struct{})
+	}
+	ps.subs[topic][sub] = struct{}{}
+	ps.mu.Unlock()
+	metrics.SubscriptionOpened(hubMetrics)
+
+	stop := context.AfterFunc(ctx, func() { ps.unsubscribe(topic, sub) })
+
+	if after == "" || !kinds.Has(KindCommentAdded) {
+		return sub.ch, nil
+	}
 
-	if ps.subs[postID] == nil {
-		ps.subs[postID] = make(map[chan *model.Comment]struct{})
+	// живые события копятся в буфере подписчика, пока досылается история
+	ch, err := withReplay(ctx, ps.history, topic, after, sub.ch)
+	if err != nil {
+		if stop() {
+			ps.unsubscribe(topic, sub)
+		}
+		return nil, err
 	}
-	ps.subs[postID][ch] = struct{}{}
+	return ch, nil
+}
+
+// HasSubscribers позволяет не загружать то, что некому отдать: ждёт ли
+// кто-нибудь в topics событие типа kind.
+func (ps *Subscription) HasSubscribers(kind Kind, topics ...Topic) bool {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
 
-	return ch
+	for _, t := range topics {
+		for sub := range ps.subs[t] {
+			if sub.kinds.Has(kind) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
-func (ps *Subscription) Unsubscribe(postID string, ch chan *model.Comment) {
+func (ps *Subscription) unsubscribe(topic Topic, sub *subscriber) {
 	ps.mu.Lock()
 	defer ps.mu.Unlock()
 
-	if m := ps.subs[postID]; m != nil {
-		delete(m, ch)
+	if m := ps.subs[topic]; m != nil {
+		delete(m, sub)
 		if len(m) == 0 {
-			delete(ps.subs, postID)
+			delete(ps.subs, topic)
 		}
 	}
-	close(ch)
+	close(sub.ch)
+	metrics.SubscriptionClosed(hubMetrics)
 }
 
-func (ps *Subscription) Publish(postID string, c *model.Comment) {
+// publish не ждёт медленных подписчиков: событие, не влезшее в буфер,
+// теряется, а число потерь уходит в Dropped следующего события. Подписчик
+// получает и считает потерянными только события своих kinds.
+func (ps *Subscription) publish(ev model.SubscriptionEvent) {
+	kind := kindOf(ev)
+
 	ps.mu.RLock()
 	defer ps.mu.RUnlock()
 
-	for ch := range ps.subs[postID] {
-		select {
-		case ch <- c:
-		default:
+	for _, topic := range topicsOf(ev) {
+		for sub := range ps.subs[topic] {
+			if !sub.kinds.Has(kind) {
+				continue
+			}
+			dropped := sub.dropped.Swap(0)
+
+			select {
+			case sub.ch <- withDropped(ev, int(dropped)):
+			default:
+				sub.dropped.Add(dropped + 1)
+				metrics.SubscriptionDropped(hubMetrics, 1)
+			}
 		}
 	}
 }
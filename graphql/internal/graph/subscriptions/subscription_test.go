@@ -0,0 +1,92 @@
+package subscriptions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/model"
+)
+
+func TestSubscription_DeliversOnlySubscribedKinds(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := New(nil)
+	topic := PostTopic("p1")
+	comment := &model.Comment{ID: "c1", PostID: "p1"}
+
+	edited, err := hub.Subscribe(ctx, topic, KindCommentEdited, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := hub.Subscribe(ctx, topic, KindComments, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hub.publish(&model.CommentAddedEvent{Comment: comment})
+	hub.publish(&model.CommentEditedEvent{Comment: comment})
+	hub.publish(&model.CommentDeletedEvent{Comment: comment})
+
+	if len(edited) != 1 {
+		t.Fatalf("edited subscriber got %d events, want 1", len(edited))
+	}
+	if _, ok := (<-edited).(*model.CommentEditedEvent); !ok {
+		t.Fatalf("edited subscriber got a foreign event")
+	}
+	if len(all) != 3 {
+		t.Fatalf("comments subscriber got %d events, want 3", len(all))
+	}
+}
+
+func TestSubscription_DroppedCountsOnlySubscribedKinds(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := New(nil)
+	comment := &model.Comment{ID: "c1", PostID: "p1"}
+	ch, err := hub.Subscribe(ctx, PostTopic("p1"), KindCommentDeleted, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range subscriberBuffer + 2 {
+		hub.publish(&model.CommentDeletedEvent{Comment: comment})
+	}
+	for range subscriberBuffer {
+		<-ch
+	}
+
+	// события других типов подписчику не доставляются и не сбрасывают потери
+	hub.publish(&model.CommentAddedEvent{Comment: comment})
+	hub.publish(&model.CommentEditedEvent{Comment: comment})
+	hub.publish(&model.CommentDeletedEvent{Comment: comment})
+
+	ev, ok := (<-ch).(*model.CommentDeletedEvent)
+	if !ok || ev.Dropped != 2 {
+		t.Fatalf("got %+v, want deleted event with dropped=2", ev)
+	}
+	if len(ch) != 0 {
+		t.Fatalf("unexpected %d extra events", len(ch))
+	}
+}
+
+func TestSubscription_HasSubscribers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := New(nil)
+	if _, err := hub.Subscribe(ctx, ThreadTopic("p1", "c1"), KindCommentAdded, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if !hub.HasSubscribers(KindCommentAdded, PostTopic("p1"), ThreadTopic("p1", "c1")) {
+		t.Fatalf("reply must match the thread subscription")
+	}
+	if hub.HasSubscribers(KindCommentEdited, PostTopic("p1"), ThreadTopic("p1", "c1")) {
+		t.Fatalf("edit must not match an added-only subscription")
+	}
+	if hub.HasSubscribers(KindCommentAdded, PostTopic("p1")) {
+		t.Fatalf("root comment must not match the thread subscription")
+	}
+}
@@ -0,0 +1,151 @@
+package subscriptions
+
+import (
+	"context"
+
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/model"
+)
+
+// Topic — область, на события которой подписывается клиент:
+// пустой Topic — лента постов, PostID — комментарии поста,
+// PostID и ParentID — только ответы на комментарий.
+type Topic struct {
+	PostID   string
+	ParentID string
+}
+
+// FeedTopic — новые посты всех авторов.
+var FeedTopic = Topic{}
+
+func PostTopic(postID string) Topic {
+	return Topic{PostID: postID}
+}
+
+func ThreadTopic(postID, commentID string) Topic {
+	return Topic{PostID: postID, ParentID: commentID}
+}
+
+// Kind — тип события подписки; значения комбинируются через |.
+type Kind uint8
+
+const (
+	KindCommentAdded Kind = 1 << iota
+	KindCommentEdited
+	KindCommentDeleted
+	KindPostAdded
+)
+
+// KindComments — все события о комментариях.
+const KindComments = KindCommentAdded | KindCommentEdited | KindCommentDeleted
+
+func (k Kind) Has(other Kind) bool { return k&other != 0 }
+
+// kindOf возвращает тип события; 0 — событие неизвестного типа.
+func kindOf(ev model.SubscriptionEvent) Kind {
+	switch ev.(type) {
+	case *model.CommentAddedEvent:
+		return KindCommentAdded
+	case *model.CommentEditedEvent:
+		return KindCommentEdited
+	case *model.CommentDeletedEvent:
+		return KindCommentDeleted
+	case *model.PostAddedEvent:
+		return KindPostAdded
+	}
+	return 0
+}
+
+// kindsOf возвращает типы событий, которые приводятся к T.
+func kindsOf[T any]() Kind {
+	var kinds Kind
+	for _, ev := range []model.SubscriptionEvent{
+		&model.CommentAddedEvent{},
+		&model.CommentEditedEvent{},
+		&model.CommentDeletedEvent{},
+		&model.PostAddedEvent{},
+	} {
+		if _, ok := ev.(T); ok {
+			kinds |= kindOf(ev)
+		}
+	}
+	return kinds
+}
+
+// topicsOf возвращает все области, которых касается событие: событие
+// ответа попадает и в поток поста, и в поток ветки его родителя.
+func topicsOf(ev model.SubscriptionEvent) []Topic {
+	var c *model.Comment
+	switch e := ev.(type) {
+	case *model.PostAddedEvent:
+		return []Topic{FeedTopic}
+	case *model.CommentAddedEvent:
+		c = e.Comment
+	case *model.CommentEditedEvent:
+		c = e.Comment
+	case *model.CommentDeletedEvent:
+		c = e.Comment
+	default:
+		return nil
+	}
+
+	topics := []Topic{PostTopic(c.PostID)}
+	if c.ParentID != nil {
+		topics = append(topics, ThreadTopic(c.PostID, *c.ParentID))
+	}
+	return topics
+}
+
+// withDropped возвращает копию события с заданным числом потерь, не трогая
+// исходное: одно событие уходит нескольким подписчикам.
+func withDropped(ev model.SubscriptionEvent, n int) model.SubscriptionEvent {
+	switch e := ev.(type) {
+	case *model.CommentAddedEvent:
+		out := *e
+		out.Dropped = n
+		return &out
+	case *model.CommentEditedEvent:
+		out := *e
+		out.Dropped = n
+		return &out
+	case *model.CommentDeletedEvent:
+		out := *e
+		out.Dropped = n
+		return &out
+	case *model.PostAddedEvent:
+		out := *e
+		out.Dropped = n
+		return &out
+	}
+	return ev
+}
+
+// Only приводит поток к типу T, например *model.CommentEditedEvent
+// или model.CommentEvent для всех событий комментариев.
+func Only[T any](ctx context.Context, in <-chan model.SubscriptionEvent) <-chan T {
+	out := make(chan T, subscriberBuffer)
+	go func() {
+		defer close(out)
+		for ev := range in {
+			v, ok := ev.(T)
+			if !ok {
+				continue
+			}
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Watch подписывается на события topic, которые можно привести к типу T:
+// брокер не присылает и не считает в Dropped события других типов.
+func Watch[T any](ctx context.Context, b Broker, topic Topic, after string) (<-chan T, error) {
+	ch, err := b.Subscribe(ctx, topic, kindsOf[T](), after)
+	if err != nil {
+		return nil, err
+	}
+	return Only[T](ctx, ch), nil
+}
@@ -0,0 +1,108 @@
+package subscriptions
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Parnishkaspb/ozon_posts_graphql/internal/graph/model"
+)
+
+func TestTopicsOf(t *testing.T) {
+	parentID := "c1"
+	root := &model.Comment{ID: "c2", PostID: "p1"}
+	reply := &model.Comment{ID: "c3", PostID: "p1", ParentID: &parentID}
+
+	tests := []struct {
+		name string
+		ev   model.SubscriptionEvent
+		want []Topic
+	}{
+		{name: "post", ev: &model.PostAddedEvent{Post: &model.Post{ID: "p1"}}, want: []Topic{FeedTopic}},
+		{name: "root comment", ev: &model.CommentAddedEvent{Comment: root}, want: []Topic{PostTopic("p1")}},
+		{name: "reply", ev: &model.CommentAddedEvent{Comment: reply}, want: []Topic{PostTopic("p1"), ThreadTopic("p1", "c1")}},
+		{name: "edited reply", ev: &model.CommentEditedEvent{Comment: reply}, want: []Topic{PostTopic("p1"), ThreadTopic("p1", "c1")}},
+		{name: "deleted root", ev: &model.CommentDeletedEvent{Comment: root}, want: []Topic{PostTopic("p1")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := topicsOf(tt.ev); !slices.Equal(got, tt.want) {
+				t.Fatalf("topicsOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKindsOf(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Kind
+		want Kind
+	}{
+		{name: "added", got: kindsOf[*model.CommentAddedEvent](), want: KindCommentAdded},
+		{name: "edited", got: kindsOf[*model.CommentEditedEvent](), want: KindCommentEdited},
+		{name: "post", got: kindsOf[*model.PostAddedEvent](), want: KindPostAdded},
+		{name: "comment union", got: kindsOf[model.CommentEvent](), want: KindComments},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Fatalf("kindsOf() = %b, want %b", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOnly(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan model.SubscriptionEvent, 3)
+	in <- &model.CommentAddedEvent{Comment: &model.Comment{ID: "c1"}}
+	in <- &model.PostAddedEvent{Post: &model.Post{ID: "p1"}}
+	in <- &model.CommentDeletedEvent{Comment: &model.Comment{ID: "c2"}}
+	close(in)
+
+	var got []string
+	for ev := range Only[model.CommentEvent](ctx, in) {
+		switch e := ev.(type) {
+		case *model.CommentAddedEvent:
+			got = append(got, e.Comment.ID)
+		case *model.CommentDeletedEvent:
+			got = append(got, e.Comment.ID)
+		}
+	}
+	if want := []string{"c1", "c2"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestOnly_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan model.SubscriptionEvent)
+	out := Only[*model.CommentAddedEvent](ctx, in)
+
+	// выход заполнен, отправитель ждёт читателя — отмена должна его отпустить
+	go func() {
+		for range subscriberBuffer + 1 {
+			in <- &model.CommentAddedEvent{}
+		}
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	deadline := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-out:
+			if !ok {
+				return
+			}
+		case <-deadline:
+			t.Fatalf("Only did not stop after cancel")
+		}
+	}
+}
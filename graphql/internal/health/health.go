@@ -0,0 +1,82 @@
+// Package health — HTTP-пробы gateway: /healthz (процесс жив) и
+// /readyz (сервис за gRPC доступен и gateway не останавливается).
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const checkTimeout = time.Second
+
+type Checker struct {
+	conn     *grpc.ClientConn
+	client   healthpb.HealthClient
+	draining atomic.Bool
+}
+
+func New(conn *grpc.ClientConn) *Checker {
+	return &Checker{conn: conn, client: healthpb.NewHealthClient(conn)}
+}
+
+// Drain переводит /readyz в 503, чтобы балансировщик перестал слать запросы.
+func (c *Checker) Drain() { c.draining.Store(true) }
+
+type report struct {
+	Status string `json:"status"`
+	GRPC   string `json:"grpc,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Live отвечает 200, пока процесс обслуживает HTTP.
+func (c *Checker) Live(w http.ResponseWriter, _ *http.Request) {
+	write(w, http.StatusOK, report{Status: "ok"})
+}
+
+// Ready проверяет состояние gRPC-соединения и статус grpc.health.v1 сервиса,
+// который, в свою очередь, отражает доступность базы.
+func (c *Checker) Ready(w http.ResponseWriter, r *http.Request) {
+	if c.draining.Load() {
+		write(w, http.StatusServiceUnavailable, report{Status: "draining"})
+		return
+	}
+
+	state := c.conn.GetState()
+	switch state {
+	case connectivity.Shutdown, connectivity.TransientFailure:
+		write(w, http.StatusServiceUnavailable, report{Status: "unavailable", GRPC: state.String()})
+		return
+	case connectivity.Idle:
+		// соединение ленивое: будим его, а готовность покажет сама проверка
+		c.conn.Connect()
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+
+	resp, err := c.client.Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		write(w, http.StatusServiceUnavailable, report{Status: "unavailable", GRPC: c.conn.GetState().String(), Error: err.Error()})
+		return
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		write(w, http.StatusServiceUnavailable, report{Status: "unavailable", GRPC: c.conn.GetState().String(), Error: "service is " + resp.GetStatus().String()})
+		return
+	}
+
+	write(w, http.StatusOK, report{Status: "ok", GRPC: c.conn.GetState().String()})
+}
+
+func write(w http.ResponseWriter, code int, body report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -7,7 +7,10 @@ import (
 
 type contextKey string
 
-const userContextKey contextKey = "auth_user"
+const (
+	userContextKey  contextKey = "auth_user"
+	tokenContextKey contextKey = "auth_token"
+)
 
 func WithUser(ctx context.Context, user *helper.User) context.Context {
 	return context.WithValue(ctx, userContextKey, user)
@@ -17,3 +20,13 @@ func FromContext(ctx context.Context) (*helper.User, bool) {
 	u, ok := ctx.Value(userContextKey).(*helper.User)
 	return u, ok
 }
+
+// WithToken сохраняет исходный access-токен, чтобы переслать его в gRPC-сервис.
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenContextKey, token)
+}
+
+func TokenFromContext(ctx context.Context) (string, bool) {
+	t, ok := ctx.Value(tokenContextKey).(string)
+	return t, ok && t != ""
+}
@@ -1,10 +1,17 @@
 package helper
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type User struct {
-	ID      uuid.UUID
-	Login   string
-	Name    string
-	Surname string
+	ID        uuid.UUID
+	SessionID uuid.UUID
+	Login     string
+	Name      string
+	Surname   string
+	// ExpiresAt — срок действия access-токена, по которому пришёл пользователь.
+	ExpiresAt time.Time
 }
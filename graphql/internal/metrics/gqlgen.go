@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// GraphQL — расширение gqlgen: длительность и ошибки по операциям.
+// У подписки InterceptResponse вызывается на каждое событие, поэтому для
+// неё считаются только ошибки.
+type GraphQL struct{}
+
+var (
+	_ graphql.HandlerExtension    = GraphQL{}
+	_ graphql.ResponseInterceptor = GraphQL{}
+)
+
+func (GraphQL) ExtensionName() string { return "Metrics" }
+
+func (GraphQL) Validate(graphql.ExecutableSchema) error { return nil }
+
+func (GraphQL) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	start := time.Now()
+	resp := next(ctx)
+	if !graphql.HasOperationContext(ctx) {
+		return resp
+	}
+
+	name, typ := operation(graphql.GetOperationContext(ctx))
+	if typ != string(ast.Subscription) {
+		operationDuration.WithLabelValues(name, typ).Observe(time.Since(start).Seconds())
+	}
+	if resp != nil && len(resp.Errors) > 0 {
+		operationErrors.WithLabelValues(name, typ).Inc()
+	}
+	return resp
+}
+
+func operation(oc *graphql.OperationContext) (name, typ string) {
+	name, typ = oc.OperationName, "unknown"
+	if oc.Operation != nil {
+		if name == "" {
+			name = oc.Operation.Name
+		}
+		typ = string(oc.Operation.Operation)
+	}
+	if name == "" {
+		name = "anonymous"
+	}
+	return name, typ
+}
@@ -0,0 +1,85 @@
+// Package metrics — метрики Prometheus gateway: операции GraphQL,
+// батчи загрузчиков и подписки.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	operationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "graphql_operation_duration_seconds",
+		Help:    "Длительность запросов и мутаций GraphQL.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation", "type"})
+
+	operationErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "graphql_operation_errors_total",
+		Help: "Ответы GraphQL с ошибками; для подписок — по событиям.",
+	}, []string{"operation", "type"})
+
+	loaderBatchSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "graphql_dataloader_batch_size",
+		Help:    "Число ключей в одном батче загрузчика.",
+		Buckets: []float64{1, 2, 5, 10, 20, 50, 100, 200},
+	}, []string{"loader"})
+
+	loaderCache = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "graphql_dataloader_cache_requests_total",
+		Help: "Обращения к кэшу загрузчика в рамках запроса: hit — без похода в сервис.",
+	}, []string{"loader", "result"})
+
+	loaderKeys = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "graphql_dataloader_keys_total",
+		Help: "Ключи батчей по результату: found, missing или error.",
+	}, []string{"loader", "result"})
+
+	subscriptionsActive = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "graphql_subscriptions_active",
+		Help: "Открытые подписки по брокеру.",
+	}, []string{"broker"})
+
+	subscriptionDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "graphql_subscription_events_dropped_total",
+		Help: "События, потерянные из-за переполненного буфера подписчика.",
+	}, []string{"broker"})
+)
+
+// Handler отдаёт метрики реестра по умолчанию (включая Go и process).
+func Handler() http.Handler { return promhttp.Handler() }
+
+// LoaderBatch учитывает батч: размер и сколько ключей найдено.
+func LoaderBatch(loader string, keys, found, missing int, err error) {
+	loaderBatchSize.WithLabelValues(loader).Observe(float64(keys))
+	if err != nil {
+		loaderKeys.WithLabelValues(loader, "error").Add(float64(keys))
+		return
+	}
+	loaderKeys.WithLabelValues(loader, "found").Add(float64(found))
+	loaderKeys.WithLabelValues(loader, "missing").Add(float64(missing))
+}
+
+// LoaderCache учитывает обращение к кэшу загрузчика.
+func LoaderCache(loader string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	loaderCache.WithLabelValues(loader, result).Inc()
+}
+
+// SubscriptionOpened и SubscriptionClosed ведут число активных подписок брокера.
+func SubscriptionOpened(broker string) { subscriptionsActive.WithLabelValues(broker).Inc() }
+
+func SubscriptionClosed(broker string) { subscriptionsActive.WithLabelValues(broker).Dec() }
+
+// SubscriptionDropped учитывает события, не доставленные подписчикам.
+func SubscriptionDropped(broker string, n int) {
+	if n > 0 {
+		subscriptionDropped.WithLabelValues(broker).Add(float64(n))
+	}
+}
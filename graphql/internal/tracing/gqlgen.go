@@ -0,0 +1,83 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GraphQL — расширение gqlgen: спан на каждый ответ (у подписки — на каждое
+// событие) и вложенные спаны полей с резолверами. Поля, которые просто
+// читаются из структуры, спанов не получают, иначе трасса тонет в шуме.
+type GraphQL struct{}
+
+var (
+	_ graphql.HandlerExtension    = GraphQL{}
+	_ graphql.ResponseInterceptor = GraphQL{}
+	_ graphql.FieldInterceptor    = GraphQL{}
+)
+
+func (GraphQL) ExtensionName() string { return "Tracing" }
+
+func (GraphQL) Validate(graphql.ExecutableSchema) error { return nil }
+
+func (GraphQL) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+
+	typ, name := operation(graphql.GetOperationContext(ctx))
+	ctx, span := otel.Tracer(scope).Start(ctx, typ+" "+name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("graphql.operation.type", typ),
+			attribute.String("graphql.operation.name", name),
+		),
+	)
+	defer span.End()
+
+	resp := next(ctx)
+	if resp != nil && len(resp.Errors) > 0 {
+		span.SetStatus(codes.Error, resp.Errors.Error())
+	}
+	return resp
+}
+
+func (GraphQL) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	ctx, span := otel.Tracer(scope).Start(ctx, fc.Object+"."+fc.Field.Name,
+		trace.WithAttributes(
+			attribute.String("graphql.field.path", fc.Path().String()),
+		),
+	)
+	defer span.End()
+
+	res, err := next(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return res, err
+}
+
+func operation(oc *graphql.OperationContext) (typ, name string) {
+	typ, name = "unknown", oc.OperationName
+	if oc.Operation != nil {
+		typ = string(oc.Operation.Operation)
+		if name == "" {
+			name = oc.Operation.Name
+		}
+	}
+	if name == "" {
+		name = "anonymous"
+	}
+	return typ, name
+}
@@ -0,0 +1,208 @@
+// Package configload заполняет структуру конфига из YAML, переменных
+// окружения и флагов по тегам yaml, env, env-default и env-required.
+package configload
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// field — настраиваемое поле конфига. Флаг называется по yaml-пути
+// (-postgresql.pool.max_conns), переменная окружения берётся из тега env.
+type field struct {
+	path     string
+	env      string
+	def      string
+	required bool
+	value    reflect.Value
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Load заполняет cfg (указатель на структуру): env-default < YAML < env < флаги.
+// name — имя набора флагов, defaultPath — YAML без -config и CONFIG_PATH.
+// Возвращает аргументы после флагов.
+func Load(cfg any, name, defaultPath string, args []string) ([]string, error) {
+	fields := collect(reflect.ValueOf(cfg).Elem(), "")
+
+	fset := flag.NewFlagSet(name, flag.ContinueOnError)
+	path := fset.String("config", "", "путь к YAML-конфигу (env CONFIG_PATH, по умолчанию "+defaultPath+")")
+
+	flags := make(map[string]string)
+	for _, f := range fields {
+		usage := f.path
+		if f.env != "" {
+			usage += " (env " + f.env + ")"
+		}
+		fset.Func(f.path, usage, func(s string) error {
+			// проверяем значение сразу, чтобы ошибка указывала на флаг
+			if err := set(reflect.New(f.value.Type()).Elem(), s); err != nil {
+				return err
+			}
+			flags[f.path] = s
+			return nil
+		})
+	}
+	if err := fset.Parse(args); err != nil {
+		return nil, err
+	}
+
+	// умолчания ставятся до YAML: явный ноль или false в файле должен остаться
+	for _, f := range fields {
+		if f.def == "" {
+			continue
+		}
+		if err := set(f.value, f.def); err != nil {
+			return nil, fmt.Errorf("%s: bad env-default: %w", f.path, err)
+		}
+	}
+
+	if err := readYAML(cfg, *path, defaultPath); err != nil {
+		return nil, err
+	}
+
+	for _, f := range fields {
+		if s, ok := flags[f.path]; ok {
+			if err := set(f.value, s); err != nil {
+				return nil, fmt.Errorf("flag -%s: %w", f.path, err)
+			}
+			continue
+		}
+		if s, ok := os.LookupEnv(f.env); ok && f.env != "" {
+			if err := set(f.value, s); err != nil {
+				return nil, fmt.Errorf("env %s: %w", f.env, err)
+			}
+		}
+	}
+
+	return fset.Args(), nil
+}
+
+// readYAML читает файл; отсутствие файла по умолчанию не ошибка —
+// тогда конфиг целиком задаётся окружением.
+func readYAML(cfg any, path, defaultPath string) error {
+	explicit := true
+	if path == "" {
+		path = os.Getenv("CONFIG_PATH")
+	}
+	if path == "" {
+		path, explicit = defaultPath, false
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+	if err := yaml.Unmarshal(b, cfg); err != nil {
+		return fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return nil
+}
+
+func collect(v reflect.Value, prefix string) []field {
+	var out []field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+		fv := v.Field(i)
+
+		if sf.Type.Kind() == reflect.Struct && sf.Type != durationType {
+			out = append(out, collect(fv, prefix+name+".")...)
+			continue
+		}
+		if !settable(sf.Type) {
+			continue
+		}
+		out = append(out, field{
+			path:     prefix + name,
+			env:      sf.Tag.Get("env"),
+			def:      sf.Tag.Get("env-default"),
+			required: sf.Tag.Get("env-required") == "true",
+			value:    fv,
+		})
+	}
+	return out
+}
+
+func settable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int32, reflect.Int64, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+func set(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("bad duration %q", s)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("bad bool %q", s)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("bad integer %q", s)
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("bad number %q", s)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// Missing возвращает незаполненные env-required поля с путём, начинающимся с prefix.
+func Missing(cfg any, prefix string) []error {
+	var errs []error
+	for _, f := range collect(reflect.ValueOf(cfg).Elem(), "") {
+		if !f.required || !strings.HasPrefix(f.path, prefix) || !f.value.IsZero() {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("%s: is required (yaml %s, env %s or flag -%s)", f.path, f.path, f.env, f.path))
+	}
+	return errs
+}
@@ -0,0 +1,156 @@
+package configload
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	Name    string        `yaml:"name" env:"TEST_NAME" env-default:"default-name"`
+	Port    int           `yaml:"port" env:"TEST_PORT" env-default:"8080"`
+	Timeout time.Duration `yaml:"timeout" env:"TEST_TIMEOUT" env-default:"5s"`
+	Enabled bool          `yaml:"enabled" env:"TEST_ENABLED" env-default:"true"`
+	Ratio   float64       `yaml:"ratio" env:"TEST_RATIO" env-default:"1"`
+	Hosts   []string      `yaml:"hosts" env:"TEST_HOSTS" env-default:"a,b"`
+	DB      struct {
+		Host string `yaml:"host" env:"TEST_DB_HOST" env-required:"true"`
+		User string `yaml:"user" env:"TEST_DB_USER" env-required:"true"`
+	} `yaml:"db"`
+}
+
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	var cfg testConfig
+	// файла по умолчанию нет: конфиг целиком из env-default
+	if _, err := Load(&cfg, "test", filepath.Join(t.TempDir(), "missing.yaml"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Name != "default-name" || cfg.Port != 8080 || cfg.Timeout != 5*time.Second ||
+		!cfg.Enabled || cfg.Ratio != 1 || !reflect.DeepEqual(cfg.Hosts, []string{"a", "b"}) {
+		t.Fatalf("env-default not applied: %+v", cfg)
+	}
+}
+
+func TestLoad_Priority(t *testing.T) {
+	path := writeConfig(t, `
+name: yaml-name
+port: 9000
+timeout: 10s
+hosts: [x]
+`)
+	t.Setenv("TEST_PORT", "9100")
+	t.Setenv("TEST_TIMEOUT", "20s")
+
+	var cfg testConfig
+	rest, err := Load(&cfg, "test", "", []string{"-config", path, "-timeout", "30s", "migrate", "up"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Timeout != 30*time.Second {
+		t.Fatalf("flag must win over env: timeout=%s", cfg.Timeout)
+	}
+	if cfg.Port != 9100 {
+		t.Fatalf("env must win over yaml: port=%d", cfg.Port)
+	}
+	if cfg.Name != "yaml-name" || !reflect.DeepEqual(cfg.Hosts, []string{"x"}) {
+		t.Fatalf("yaml must win over env-default: name=%q hosts=%v", cfg.Name, cfg.Hosts)
+	}
+	if cfg.Ratio != 1 {
+		t.Fatalf("env-default not applied: ratio=%v", cfg.Ratio)
+	}
+	if strings.Join(rest, " ") != "migrate up" {
+		t.Fatalf("rest = %v", rest)
+	}
+}
+
+func TestLoad_ExplicitZero(t *testing.T) {
+	path := writeConfig(t, `
+name: ""
+port: 0
+timeout: 0s
+enabled: false
+ratio: 0
+hosts: []
+`)
+
+	var cfg testConfig
+	if _, err := Load(&cfg, "test", "", []string{"-config", path}); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Name != "" || cfg.Port != 0 || cfg.Timeout != 0 || cfg.Enabled || cfg.Ratio != 0 || len(cfg.Hosts) != 0 {
+		t.Fatalf("explicit zero values from yaml replaced by env-default: %+v", cfg)
+	}
+}
+
+func TestLoad_EnvZero(t *testing.T) {
+	t.Setenv("TEST_ENABLED", "false")
+	t.Setenv("TEST_RATIO", "0")
+
+	var cfg testConfig
+	if _, err := Load(&cfg, "test", filepath.Join(t.TempDir(), "missing.yaml"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Enabled || cfg.Ratio != 0 {
+		t.Fatalf("explicit zero values from env replaced by env-default: enabled=%v ratio=%v", cfg.Enabled, cfg.Ratio)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	t.Run("bad env", func(t *testing.T) {
+		t.Setenv("TEST_PORT", "abc")
+		var cfg testConfig
+		_, err := Load(&cfg, "test", filepath.Join(t.TempDir(), "missing.yaml"), nil)
+		if err == nil || !strings.Contains(err.Error(), "TEST_PORT") {
+			t.Fatalf("error = %v, want TEST_PORT", err)
+		}
+	})
+
+	t.Run("bad flag", func(t *testing.T) {
+		var cfg testConfig
+		if _, err := Load(&cfg, "test", filepath.Join(t.TempDir(), "missing.yaml"), []string{"-timeout", "soon"}); err == nil {
+			t.Fatal("expected error for bad duration flag")
+		}
+	})
+
+	t.Run("explicit config missing", func(t *testing.T) {
+		var cfg testConfig
+		if _, err := Load(&cfg, "test", "", []string{"-config", filepath.Join(t.TempDir(), "missing.yaml")}); err == nil {
+			t.Fatal("expected error for missing -config file")
+		}
+	})
+}
+
+func TestMissing(t *testing.T) {
+	var cfg testConfig
+	cfg.DB.Host = "localhost"
+
+	errs := Missing(&cfg, "db.")
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "db.user") || !strings.Contains(errs[0].Error(), "TEST_DB_USER") {
+		t.Fatalf("Missing() = %v, want only db.user", errs)
+	}
+
+	if errs := Missing(&cfg, "other."); len(errs) != 0 {
+		t.Fatalf("prefix must filter fields: %v", errs)
+	}
+
+	cfg.DB.User = "user"
+	if errs := Missing(&cfg, "db."); len(errs) != 0 {
+		t.Fatalf("Missing() = %v, want none", errs)
+	}
+}
@@ -0,0 +1,20 @@
+module github.com/Parnishkaspb/ozon_posts_pkg
+
+go 1.24.0
+
+toolchain go1.24.12
+
+require (
+	go.opentelemetry.io/otel/trace v1.38.0
+	google.golang.org/grpc v1.78.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	go.opentelemetry.io/otel v1.38.0 // indirect
+	golang.org/x/net v0.47.0 // indirect
+	golang.org/x/sys v0.38.0 // indirect
+	golang.org/x/text v0.31.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20251029180050-ab9386a59fda // indirect
+	google.golang.org/protobuf v1.36.10 // indirect
+)
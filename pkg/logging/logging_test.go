@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		level, format string
+		wantErr       bool
+	}{
+		{level: "info", format: "text"},
+		{level: "DEBUG", format: "json"},
+		{level: "warn", format: ""},
+		{level: "verbose", format: "text", wantErr: true},
+		{level: "info", format: "xml", wantErr: true},
+	}
+	for _, tt := range tests {
+		_, err := New(&bytes.Buffer{}, tt.level, tt.format)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("New(%q, %q) error = %v, wantErr %v", tt.level, tt.format, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRequestIDInRecords(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	logger.With("component", "test").InfoContext(ctx, "hello")
+	logger.DebugContext(ctx, "filtered out by level")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected one record, got %d: %s", len(lines), buf.String())
+	}
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec["request_id"] != "req-1" || rec["component"] != "test" || rec["level"] != slog.LevelInfo.String() {
+		t.Fatalf("unexpected record: %v", rec)
+	}
+}
+
+func TestValidRequestID(t *testing.T) {
+	if !ValidRequestID(NewRequestID()) {
+		t.Fatalf("generated id must be valid")
+	}
+	for _, id := range []string{"", "with space", "line\nbreak", strings.Repeat("a", 129)} {
+		if ValidRequestID(id) {
+			t.Fatalf("%q must be rejected", id)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// Package tlsconfig собирает tls.Config для gRPC и перечитывает сертификаты
+// с диска, когда файлы меняются, без перезапуска процесса.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultReloadInterval = 10 * time.Second
+
+var ErrNoCertificates = errors.New("no certificates found in CA file")
+
+// Files — пути к PEM-файлам. CertFile и KeyFile задаются вместе;
+// CAFile — корни для проверки другой стороны.
+type Files struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
+}
+
+// Reloader держит последние прочитанные сертификаты. Файлы проверяются при
+// рукопожатии, но не чаще раза в interval: ротация подхватывается новыми
+// соединениями, уже открытые не рвутся.
+type Reloader struct {
+	files    Files
+	interval time.Duration
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	pool    *x509.CertPool
+	modTime map[string]time.Time
+	checked time.Time
+}
+
+// NewReloader читает файлы сразу, чтобы ошибка конфигурации была видна на старте.
+func NewReloader(files Files, interval time.Duration) (*Reloader, error) {
+	if interval <= 0 {
+		interval = defaultReloadInterval
+	}
+	r := &Reloader{files: files, interval: interval}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ServerConfig — конфиг слушателя. С CAFile включается mTLS: клиент обязан
+// предъявить сертификат, подписанный этим CA.
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := r.current()
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				// gRPC требует согласования h2 через ALPN
+				NextProtos: []string{"h2"},
+			}
+			if pool != nil {
+				cfg.ClientCAs = pool
+				cfg.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+			return cfg, nil
+		},
+	}
+}
+
+// ClientConfig — конфиг для dial. Без CAFile сервер проверяется системными
+// корнями; клиентский сертификат (mTLS) отдаётся, если задан CertFile.
+// Корни CA читаются один раз: gRPC копирует RootCAs при создании credentials.
+func (r *Reloader) ClientConfig(serverName string) *tls.Config {
+	_, pool := r.current()
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		RootCAs:    pool,
+	}
+	if r.files.CertFile != "" {
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := r.current()
+			return cert, nil
+		}
+	}
+	return cfg
+}
+
+// current возвращает актуальные сертификаты, при необходимости перечитывая файлы.
+// Если новые файлы битые (например, записаны наполовину), остаются старые.
+func (r *Reloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checked) >= r.interval {
+		r.checked = time.Now()
+		if r.changed() {
+			_ = r.loadLocked()
+		}
+	}
+	return r.cert, r.pool
+}
+
+func (r *Reloader) load() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checked = time.Now()
+	return r.loadLocked()
+}
+
+func (r *Reloader) loadLocked() error {
+	modTime := make(map[string]time.Time)
+	for _, path := range r.paths() {
+		st, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		modTime[path] = st.ModTime()
+	}
+
+	var cert *tls.Certificate
+	if r.files.CertFile != "" {
+		c, err := tls.LoadX509KeyPair(r.files.CertFile, r.files.KeyFile)
+		if err != nil {
+			return fmt.Errorf("load key pair: %w", err)
+		}
+		cert = &c
+	}
+
+	var pool *x509.CertPool
+	if r.files.CAFile != "" {
+		pem, err := os.ReadFile(r.files.CAFile)
+		if err != nil {
+			return fmt.Errorf("read CA: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("%w: %s", ErrNoCertificates, r.files.CAFile)
+		}
+	}
+
+	r.cert, r.pool, r.modTime = cert, pool, modTime
+	return nil
+}
+
+func (r *Reloader) changed() bool {
+	for _, path := range r.paths() {
+		st, err := os.Stat(path)
+		if err != nil {
+			return false
+		}
+		if !st.ModTime().Equal(r.modTime[path]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Reloader) paths() []string {
+	var out []string
+	for _, p := range []string{r.files.CertFile, r.files.KeyFile, r.files.CAFile} {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
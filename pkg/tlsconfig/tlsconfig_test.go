@@ -0,0 +1,259 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue выпускает сертификат для localhost и пишет cert/key в dir.
+func (ca *testCA) issue(t *testing.T, dir, name string, serial int64, usage x509.ExtKeyUsage) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	writeFile(t, certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	writeFile(t, keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	return certFile, keyFile
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type pki struct {
+	dir                 string
+	ca                  *testCA
+	caFile              string
+	serverCert, serverK string
+	clientCert, clientK string
+}
+
+func newPKI(t *testing.T) *pki {
+	t.Helper()
+	p := &pki{dir: t.TempDir(), ca: newCA(t)}
+	p.caFile = filepath.Join(p.dir, "ca.crt")
+	writeFile(t, p.caFile, p.ca.pem)
+	p.serverCert, p.serverK = p.ca.issue(t, p.dir, "server", 10, x509.ExtKeyUsageServerAuth)
+	p.clientCert, p.clientK = p.ca.issue(t, p.dir, "client", 20, x509.ExtKeyUsageClientAuth)
+	return p
+}
+
+func serveHealth(t *testing.T, creds credentials.TransportCredentials) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer(grpc.Creds(creds))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+func checkHealth(addr string, creds credentials.TransportCredentials) error {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func TestMutualTLS(t *testing.T) {
+	p := newPKI(t)
+
+	server, err := NewReloader(Files{CertFile: p.serverCert, KeyFile: p.serverK, CAFile: p.caFile}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := serveHealth(t, credentials.NewTLS(server.ServerConfig()))
+
+	client, err := NewReloader(Files{CertFile: p.clientCert, KeyFile: p.clientK, CAFile: p.caFile}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkHealth(addr, credentials.NewTLS(client.ClientConfig("localhost"))); err != nil {
+		t.Fatalf("mTLS call failed: %v", err)
+	}
+
+	// без клиентского сертификата сервер рвёт рукопожатие
+	anonymous, err := NewReloader(Files{CAFile: p.caFile}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkHealth(addr, credentials.NewTLS(anonymous.ClientConfig("localhost"))); err == nil {
+		t.Fatalf("expected call without client certificate to fail")
+	}
+}
+
+func TestServerTLSWithoutClientAuth(t *testing.T) {
+	p := newPKI(t)
+
+	server, err := NewReloader(Files{CertFile: p.serverCert, KeyFile: p.serverK}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := serveHealth(t, credentials.NewTLS(server.ServerConfig()))
+
+	client, err := NewReloader(Files{CAFile: p.caFile}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkHealth(addr, credentials.NewTLS(client.ClientConfig("localhost"))); err != nil {
+		t.Fatalf("TLS call failed: %v", err)
+	}
+}
+
+func TestReloader_PicksUpRotatedCertificate(t *testing.T) {
+	p := newPKI(t)
+
+	server, err := NewReloader(Files{CertFile: p.serverCert, KeyFile: p.serverK}, time.Nanosecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", server.ServerConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			_ = conn.Close()
+		}
+	}()
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(p.ca.pem)
+	serial := func() int64 {
+		t.Helper()
+		conn, err := tls.Dial("tcp", lis.Addr().String(), &tls.Config{RootCAs: pool, ServerName: "localhost", NextProtos: []string{"h2"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+	}
+
+	if got := serial(); got != 10 {
+		t.Fatalf("serial = %d, want 10", got)
+	}
+
+	p.ca.issue(t, p.dir, "server", 11, x509.ExtKeyUsageServerAuth)
+	future := time.Now().Add(time.Minute)
+	for _, f := range []string{p.serverCert, p.serverK} {
+		if err := os.Chtimes(f, future, future); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := serial(); got != 11 {
+		t.Fatalf("rotated certificate not picked up: serial = %d, want 11", got)
+	}
+
+	// битый файл не ломает сервер: остаётся последний рабочий сертификат
+	writeFile(t, p.serverCert, []byte("garbage"))
+	later := future.Add(time.Minute)
+	if err := os.Chtimes(p.serverCert, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if got := serial(); got != 11 {
+		t.Fatalf("serial after broken rotation = %d, want 11", got)
+	}
+}
+
+func TestNewReloader_Errors(t *testing.T) {
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.pem")
+	writeFile(t, bad, []byte("not a pem"))
+
+	if _, err := NewReloader(Files{CertFile: filepath.Join(dir, "missing.crt"), KeyFile: bad}, 0); err == nil {
+		t.Fatalf("expected error for missing cert")
+	}
+	if _, err := NewReloader(Files{CAFile: bad}, 0); err == nil {
+		t.Fatalf("expected error for CA without certificates")
+	}
+}
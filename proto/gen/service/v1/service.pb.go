@@ -22,6 +22,58 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type CommentEventType int32
+
+const (
+	CommentEventType_COMMENT_EVENT_TYPE_UNSPECIFIED CommentEventType = 0
+	CommentEventType_COMMENT_EVENT_TYPE_CREATED     CommentEventType = 1
+	CommentEventType_COMMENT_EVENT_TYPE_EDITED      CommentEventType = 2
+	CommentEventType_COMMENT_EVENT_TYPE_DELETED     CommentEventType = 3
+)
+
+// Enum value maps for CommentEventType.
+var (
+	CommentEventType_name = map[int32]string{
+		0: "COMMENT_EVENT_TYPE_UNSPECIFIED",
+		1: "COMMENT_EVENT_TYPE_CREATED",
+		2: "COMMENT_EVENT_TYPE_EDITED",
+		3: "COMMENT_EVENT_TYPE_DELETED",
+	}
+	CommentEventType_value = map[string]int32{
+		"COMMENT_EVENT_TYPE_UNSPECIFIED": 0,
+		"COMMENT_EVENT_TYPE_CREATED":     1,
+		"COMMENT_EVENT_TYPE_EDITED":      2,
+		"COMMENT_EVENT_TYPE_DELETED":     3,
+	}
+)
+
+func (x CommentEventType) Enum() *CommentEventType {
+	p := new(CommentEventType)
+	*p = x
+	return p
+}
+
+func (x CommentEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CommentEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_service_v1_service_proto_enumTypes[0].Descriptor()
+}
+
+func (CommentEventType) Type() protoreflect.EnumType {
+	return &file_service_v1_service_proto_enumTypes[0]
+}
+
+func (x CommentEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CommentEventType.Descriptor instead.
+func (CommentEventType) EnumDescriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{0}
+}
+
 type LoginRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Login         string                 `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
@@ -77,6 +129,7 @@ func (x *LoginRequest) GetPassword() string {
 type LoginResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -118,30 +171,34 @@ func (x *LoginResponse) GetToken() string {
 	return ""
 }
 
-type CreateUserRequest struct {
+func (x *LoginResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type RefreshTokenRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Login         string                 `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
-	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
-	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	Surname       string                 `protobuf:"bytes,4,opt,name=surname,proto3" json:"surname,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateUserRequest) Reset() {
-	*x = CreateUserRequest{}
+func (x *RefreshTokenRequest) Reset() {
+	*x = RefreshTokenRequest{}
 	mi := &file_service_v1_service_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateUserRequest) String() string {
+func (x *RefreshTokenRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateUserRequest) ProtoMessage() {}
+func (*RefreshTokenRequest) ProtoMessage() {}
 
-func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+func (x *RefreshTokenRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_service_v1_service_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -153,60 +210,40 @@ func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
-func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RefreshTokenRequest.ProtoReflect.Descriptor instead.
+func (*RefreshTokenRequest) Descriptor() ([]byte, []int) {
 	return file_service_v1_service_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *CreateUserRequest) GetLogin() string {
-	if x != nil {
-		return x.Login
-	}
-	return ""
-}
-
-func (x *CreateUserRequest) GetPassword() string {
-	if x != nil {
-		return x.Password
-	}
-	return ""
-}
-
-func (x *CreateUserRequest) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
-}
-
-func (x *CreateUserRequest) GetSurname() string {
+func (x *RefreshTokenRequest) GetRefreshToken() string {
 	if x != nil {
-		return x.Surname
+		return x.RefreshToken
 	}
 	return ""
 }
 
-type CreateUserResponse struct {
+type RefreshTokenResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateUserResponse) Reset() {
-	*x = CreateUserResponse{}
+func (x *RefreshTokenResponse) Reset() {
+	*x = RefreshTokenResponse{}
 	mi := &file_service_v1_service_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateUserResponse) String() string {
+func (x *RefreshTokenResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateUserResponse) ProtoMessage() {}
+func (*RefreshTokenResponse) ProtoMessage() {}
 
-func (x *CreateUserResponse) ProtoReflect() protoreflect.Message {
+func (x *RefreshTokenResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_service_v1_service_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -218,39 +255,46 @@ func (x *CreateUserResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateUserResponse.ProtoReflect.Descriptor instead.
-func (*CreateUserResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use RefreshTokenResponse.ProtoReflect.Descriptor instead.
+func (*RefreshTokenResponse) Descriptor() ([]byte, []int) {
 	return file_service_v1_service_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *CreateUserResponse) GetId() string {
+func (x *RefreshTokenResponse) GetToken() string {
 	if x != nil {
-		return x.Id
+		return x.Token
 	}
 	return ""
 }
 
-type GetUsersRequest struct {
+func (x *RefreshTokenResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type LogoutRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUsersRequest) Reset() {
-	*x = GetUsersRequest{}
+func (x *LogoutRequest) Reset() {
+	*x = LogoutRequest{}
 	mi := &file_service_v1_service_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUsersRequest) String() string {
+func (x *LogoutRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUsersRequest) ProtoMessage() {}
+func (*LogoutRequest) ProtoMessage() {}
 
-func (x *GetUsersRequest) ProtoReflect() protoreflect.Message {
+func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_service_v1_service_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -262,41 +306,38 @@ func (x *GetUsersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUsersRequest.ProtoReflect.Descriptor instead.
-func (*GetUsersRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use LogoutRequest.ProtoReflect.Descriptor instead.
+func (*LogoutRequest) Descriptor() ([]byte, []int) {
 	return file_service_v1_service_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *GetUsersRequest) GetIds() []string {
+func (x *LogoutRequest) GetRefreshToken() string {
 	if x != nil {
-		return x.Ids
+		return x.RefreshToken
 	}
-	return nil
+	return ""
 }
 
-type User struct {
+type LogoutResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Surname       string                 `protobuf:"bytes,3,opt,name=surname,proto3" json:"surname,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *User) Reset() {
-	*x = User{}
+func (x *LogoutResponse) Reset() {
+	*x = LogoutResponse{}
 	mi := &file_service_v1_service_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *User) String() string {
+func (x *LogoutResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*User) ProtoMessage() {}
+func (*LogoutResponse) ProtoMessage() {}
 
-func (x *User) ProtoReflect() protoreflect.Message {
+func (x *LogoutResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_service_v1_service_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -308,53 +349,32 @@ func (x *User) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use User.ProtoReflect.Descriptor instead.
-func (*User) Descriptor() ([]byte, []int) {
+// Deprecated: Use LogoutResponse.ProtoReflect.Descriptor instead.
+func (*LogoutResponse) Descriptor() ([]byte, []int) {
 	return file_service_v1_service_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *User) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
-}
-
-func (x *User) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
-}
-
-func (x *User) GetSurname() string {
-	if x != nil {
-		return x.Surname
-	}
-	return ""
-}
-
-type GetUsersResponse struct {
+type CheckSessionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUsersResponse) Reset() {
-	*x = GetUsersResponse{}
+func (x *CheckSessionRequest) Reset() {
+	*x = CheckSessionRequest{}
 	mi := &file_service_v1_service_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUsersResponse) String() string {
+func (x *CheckSessionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUsersResponse) ProtoMessage() {}
+func (*CheckSessionRequest) ProtoMessage() {}
 
-func (x *GetUsersResponse) ProtoReflect() protoreflect.Message {
+func (x *CheckSessionRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_service_v1_service_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -366,41 +386,39 @@ func (x *GetUsersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUsersResponse.ProtoReflect.Descriptor instead.
-func (*GetUsersResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CheckSessionRequest.ProtoReflect.Descriptor instead.
+func (*CheckSessionRequest) Descriptor() ([]byte, []int) {
 	return file_service_v1_service_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *GetUsersResponse) GetUsers() []*User {
+func (x *CheckSessionRequest) GetSessionId() string {
 	if x != nil {
-		return x.Users
+		return x.SessionId
 	}
-	return nil
+	return ""
 }
 
-type CreatePostRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	AuthorId       string                 `protobuf:"bytes,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
-	Text           string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
-	WithoutComment bool                   `protobuf:"varint,3,opt,name=without_comment,json=withoutComment,proto3" json:"without_comment,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+type CheckSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Active        bool                   `protobuf:"varint,1,opt,name=active,proto3" json:"active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreatePostRequest) Reset() {
-	*x = CreatePostRequest{}
+func (x *CheckSessionResponse) Reset() {
+	*x = CheckSessionResponse{}
 	mi := &file_service_v1_service_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreatePostRequest) String() string {
+func (x *CheckSessionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreatePostRequest) ProtoMessage() {}
+func (*CheckSessionResponse) ProtoMessage() {}
 
-func (x *CreatePostRequest) ProtoReflect() protoreflect.Message {
+func (x *CheckSessionResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_service_v1_service_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -412,58 +430,47 @@ func (x *CreatePostRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreatePostRequest.ProtoReflect.Descriptor instead.
-func (*CreatePostRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CheckSessionResponse.ProtoReflect.Descriptor instead.
+func (*CheckSessionResponse) Descriptor() ([]byte, []int) {
 	return file_service_v1_service_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *CreatePostRequest) GetAuthorId() string {
-	if x != nil {
-		return x.AuthorId
-	}
-	return ""
-}
-
-func (x *CreatePostRequest) GetText() string {
-	if x != nil {
-		return x.Text
-	}
-	return ""
-}
-
-func (x *CreatePostRequest) GetWithoutComment() bool {
+func (x *CheckSessionResponse) GetActive() bool {
 	if x != nil {
-		return x.WithoutComment
+		return x.Active
 	}
 	return false
 }
 
-type Post struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	AuthorId       string                 `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
-	Text           string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
-	WithoutComment bool                   `protobuf:"varint,4,opt,name=without_comment,json=withoutComment,proto3" json:"without_comment,omitempty"`
-	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+// Публичный ключ в формате JWK (RFC 7517); заполняются поля, нужные для kty.
+type JWK struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kid           string                 `protobuf:"bytes,1,opt,name=kid,proto3" json:"kid,omitempty"`
+	Kty           string                 `protobuf:"bytes,2,opt,name=kty,proto3" json:"kty,omitempty"`
+	Alg           string                 `protobuf:"bytes,3,opt,name=alg,proto3" json:"alg,omitempty"`
+	Use           string                 `protobuf:"bytes,4,opt,name=use,proto3" json:"use,omitempty"`
+	Crv           string                 `protobuf:"bytes,5,opt,name=crv,proto3" json:"crv,omitempty"`
+	X             string                 `protobuf:"bytes,6,opt,name=x,proto3" json:"x,omitempty"`
+	N             string                 `protobuf:"bytes,7,opt,name=n,proto3" json:"n,omitempty"`
+	E             string                 `protobuf:"bytes,8,opt,name=e,proto3" json:"e,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Post) Reset() {
-	*x = Post{}
+func (x *JWK) Reset() {
+	*x = JWK{}
 	mi := &file_service_v1_service_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Post) String() string {
+func (x *JWK) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Post) ProtoMessage() {}
+func (*JWK) ProtoMessage() {}
 
-func (x *Post) ProtoReflect() protoreflect.Message {
+func (x *JWK) ProtoReflect() protoreflect.Message {
 	mi := &file_service_v1_service_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -475,74 +482,87 @@ func (x *Post) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Post.ProtoReflect.Descriptor instead.
-func (*Post) Descriptor() ([]byte, []int) {
+// Deprecated: Use JWK.ProtoReflect.Descriptor instead.
+func (*JWK) Descriptor() ([]byte, []int) {
 	return file_service_v1_service_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *Post) GetId() string {
+func (x *JWK) GetKid() string {
 	if x != nil {
-		return x.Id
+		return x.Kid
 	}
 	return ""
 }
 
-func (x *Post) GetAuthorId() string {
+func (x *JWK) GetKty() string {
 	if x != nil {
-		return x.AuthorId
+		return x.Kty
 	}
 	return ""
 }
 
-func (x *Post) GetText() string {
+func (x *JWK) GetAlg() string {
 	if x != nil {
-		return x.Text
+		return x.Alg
 	}
 	return ""
 }
 
-func (x *Post) GetWithoutComment() bool {
+func (x *JWK) GetUse() string {
 	if x != nil {
-		return x.WithoutComment
+		return x.Use
 	}
-	return false
+	return ""
 }
 
-func (x *Post) GetCreatedAt() *timestamppb.Timestamp {
+func (x *JWK) GetCrv() string {
 	if x != nil {
-		return x.CreatedAt
+		return x.Crv
 	}
-	return nil
+	return ""
 }
 
-func (x *Post) GetUpdatedAt() *timestamppb.Timestamp {
+func (x *JWK) GetX() string {
 	if x != nil {
-		return x.UpdatedAt
+		return x.X
 	}
-	return nil
+	return ""
 }
 
-type CreatePostResponse struct {
+func (x *JWK) GetN() string {
+	if x != nil {
+		return x.N
+	}
+	return ""
+}
+
+func (x *JWK) GetE() string {
+	if x != nil {
+		return x.E
+	}
+	return ""
+}
+
+type GetJWKSRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Post          *Post                  `protobuf:"bytes,1,opt,name=post,proto3" json:"post,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreatePostResponse) Reset() {
-	*x = CreatePostResponse{}
+func (x *GetJWKSRequest) Reset() {
+	*x = GetJWKSRequest{}
 	mi := &file_service_v1_service_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreatePostResponse) String() string {
+func (x *GetJWKSRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreatePostResponse) ProtoMessage() {}
+func (*GetJWKSRequest) ProtoMessage() {}
 
-func (x *CreatePostResponse) ProtoReflect() protoreflect.Message {
+func (x *GetJWKSRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_service_v1_service_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -554,39 +574,32 @@ func (x *CreatePostResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreatePostResponse.ProtoReflect.Descriptor instead.
-func (*CreatePostResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetJWKSRequest.ProtoReflect.Descriptor instead.
+func (*GetJWKSRequest) Descriptor() ([]byte, []int) {
 	return file_service_v1_service_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *CreatePostResponse) GetPost() *Post {
-	if x != nil {
-		return x.Post
-	}
-	return nil
-}
-
-type GetPostRequest struct {
+type GetJWKSResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Keys          []*JWK                 `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPostRequest) Reset() {
-	*x = GetPostRequest{}
+func (x *GetJWKSResponse) Reset() {
+	*x = GetJWKSResponse{}
 	mi := &file_service_v1_service_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPostRequest) String() string {
+func (x *GetJWKSResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPostRequest) ProtoMessage() {}
+func (*GetJWKSResponse) ProtoMessage() {}
 
-func (x *GetPostRequest) ProtoReflect() protoreflect.Message {
+func (x *GetJWKSResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_service_v1_service_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -598,40 +611,1162 @@ func (x *GetPostRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPostRequest.ProtoReflect.Descriptor instead.
-func (*GetPostRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetJWKSResponse.ProtoReflect.Descriptor instead.
+func (*GetJWKSResponse) Descriptor() ([]byte, []int) {
 	return file_service_v1_service_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *GetPostRequest) GetId() string {
+func (x *GetJWKSResponse) GetKeys() []*JWK {
 	if x != nil {
-		return x.Id
+		return x.Keys
 	}
-	return ""
+	return nil
 }
 
-type GetPostResponse struct {
+type CreateUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Post          *Post                  `protobuf:"bytes,1,opt,name=post,proto3" json:"post,omitempty"`
+	Login         string                 `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Surname       string                 `protobuf:"bytes,4,opt,name=surname,proto3" json:"surname,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPostResponse) Reset() {
-	*x = GetPostResponse{}
-	mi := &file_service_v1_service_proto_msgTypes[11]
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateUserRequest) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetSurname() string {
+	if x != nil {
+		return x.Surname
+	}
+	return ""
+}
+
+type CreateUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserResponse) Reset() {
+	*x = CreateUserResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserResponse) ProtoMessage() {}
+
+func (x *CreateUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserResponse.ProtoReflect.Descriptor instead.
+func (*CreateUserResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CreateUserResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsersRequest) Reset() {
+	*x = GetUsersRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsersRequest) ProtoMessage() {}
+
+func (x *GetUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsersRequest.ProtoReflect.Descriptor instead.
+func (*GetUsersRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetUsersRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Surname       string                 `protobuf:"bytes,3,opt,name=surname,proto3" json:"surname,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_service_v1_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *User) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *User) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *User) GetSurname() string {
+	if x != nil {
+		return x.Surname
+	}
+	return ""
+}
+
+type GetUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsersResponse) Reset() {
+	*x = GetUsersResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsersResponse) ProtoMessage() {}
+
+func (x *GetUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsersResponse.ProtoReflect.Descriptor instead.
+func (*GetUsersResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+type CreatePostRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AuthorId       string                 `protobuf:"bytes,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"` // необязателен: автор берётся из токена, при расхождении PERMISSION_DENIED
+	Text           string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	WithoutComment bool                   `protobuf:"varint,3,opt,name=without_comment,json=withoutComment,proto3" json:"without_comment,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreatePostRequest) Reset() {
+	*x = CreatePostRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePostRequest) ProtoMessage() {}
+
+func (x *CreatePostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePostRequest.ProtoReflect.Descriptor instead.
+func (*CreatePostRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CreatePostRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *CreatePostRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *CreatePostRequest) GetWithoutComment() bool {
+	if x != nil {
+		return x.WithoutComment
+	}
+	return false
+}
+
+type Post struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AuthorId       string                 `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Text           string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	WithoutComment bool                   `protobuf:"varint,4,opt,name=without_comment,json=withoutComment,proto3" json:"without_comment,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Post) Reset() {
+	*x = Post{}
+	mi := &file_service_v1_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Post) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Post) ProtoMessage() {}
+
+func (x *Post) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Post.ProtoReflect.Descriptor instead.
+func (*Post) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Post) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Post) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *Post) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Post) GetWithoutComment() bool {
+	if x != nil {
+		return x.WithoutComment
+	}
+	return false
+}
+
+func (x *Post) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Post) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type CreatePostResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Post          *Post                  `protobuf:"bytes,1,opt,name=post,proto3" json:"post,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePostResponse) Reset() {
+	*x = CreatePostResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePostResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePostResponse) ProtoMessage() {}
+
+func (x *CreatePostResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePostResponse.ProtoReflect.Descriptor instead.
+func (*CreatePostResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CreatePostResponse) GetPost() *Post {
+	if x != nil {
+		return x.Post
+	}
+	return nil
+}
+
+type GetPostRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPostRequest) Reset() {
+	*x = GetPostRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPostRequest) ProtoMessage() {}
+
+func (x *GetPostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPostRequest.ProtoReflect.Descriptor instead.
+func (*GetPostRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetPostRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetPostResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Post          *Post                  `protobuf:"bytes,1,opt,name=post,proto3" json:"post,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPostResponse) Reset() {
+	*x = GetPostResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPostResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPostResponse) ProtoMessage() {}
+
+func (x *GetPostResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPostResponse.ProtoReflect.Descriptor instead.
+func (*GetPostResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetPostResponse) GetPost() *Post {
+	if x != nil {
+		return x.Post
+	}
+	return nil
+}
+
+type GetPostsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	First         int32                  `protobuf:"varint,2,opt,name=first,proto3" json:"first,omitempty"`
+	After         string                 `protobuf:"bytes,3,opt,name=after,proto3" json:"after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPostsRequest) Reset() {
+	*x = GetPostsRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPostsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPostsRequest) ProtoMessage() {}
+
+func (x *GetPostsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPostsRequest.ProtoReflect.Descriptor instead.
+func (*GetPostsRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetPostsRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *GetPostsRequest) GetFirst() int32 {
+	if x != nil {
+		return x.First
+	}
+	return 0
+}
+
+func (x *GetPostsRequest) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+type GetPostsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Posts         []*Post                `protobuf:"bytes,1,rep,name=posts,proto3" json:"posts,omitempty"` // при заданных ids — в порядке запроса
+	EndCursor     string                 `protobuf:"bytes,2,opt,name=end_cursor,json=endCursor,proto3" json:"end_cursor,omitempty"`
+	HasNextPage   bool                   `protobuf:"varint,3,opt,name=has_next_page,json=hasNextPage,proto3" json:"has_next_page,omitempty"`
+	MissingIds    []string               `protobuf:"bytes,4,rep,name=missing_ids,json=missingIds,proto3" json:"missing_ids,omitempty"` // ids, для которых пост не найден
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPostsResponse) Reset() {
+	*x = GetPostsResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPostsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPostsResponse) ProtoMessage() {}
+
+func (x *GetPostsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPostsResponse.ProtoReflect.Descriptor instead.
+func (*GetPostsResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetPostsResponse) GetPosts() []*Post {
+	if x != nil {
+		return x.Posts
+	}
+	return nil
+}
+
+func (x *GetPostsResponse) GetEndCursor() string {
+	if x != nil {
+		return x.EndCursor
+	}
+	return ""
+}
+
+func (x *GetPostsResponse) GetHasNextPage() bool {
+	if x != nil {
+		return x.HasNextPage
+	}
+	return false
+}
+
+func (x *GetPostsResponse) GetMissingIds() []string {
+	if x != nil {
+		return x.MissingIds
+	}
+	return nil
+}
+
+type UpdatePostRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AuthorId       string                 `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"` // необязателен: автор берётся из токена, при расхождении PERMISSION_DENIED
+	Text           *string                `protobuf:"bytes,3,opt,name=text,proto3,oneof" json:"text,omitempty"`                   // не задано => без изменений
+	WithoutComment *bool                  `protobuf:"varint,4,opt,name=without_comment,json=withoutComment,proto3,oneof" json:"without_comment,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UpdatePostRequest) Reset() {
+	*x = UpdatePostRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePostRequest) ProtoMessage() {}
+
+func (x *UpdatePostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePostRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePostRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *UpdatePostRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdatePostRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *UpdatePostRequest) GetText() string {
+	if x != nil && x.Text != nil {
+		return *x.Text
+	}
+	return ""
+}
+
+func (x *UpdatePostRequest) GetWithoutComment() bool {
+	if x != nil && x.WithoutComment != nil {
+		return *x.WithoutComment
+	}
+	return false
+}
+
+type UpdatePostResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Post          *Post                  `protobuf:"bytes,1,opt,name=post,proto3" json:"post,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePostResponse) Reset() {
+	*x = UpdatePostResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePostResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePostResponse) ProtoMessage() {}
+
+func (x *UpdatePostResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePostResponse.ProtoReflect.Descriptor instead.
+func (*UpdatePostResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *UpdatePostResponse) GetPost() *Post {
+	if x != nil {
+		return x.Post
+	}
+	return nil
+}
+
+type DeletePostRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AuthorId      string                 `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"` // необязателен: автор берётся из токена, при расхождении PERMISSION_DENIED
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePostRequest) Reset() {
+	*x = DeletePostRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePostRequest) ProtoMessage() {}
+
+func (x *DeletePostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePostRequest.ProtoReflect.Descriptor instead.
+func (*DeletePostRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *DeletePostRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeletePostRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+type DeletePostResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePostResponse) Reset() {
+	*x = DeletePostResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePostResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePostResponse) ProtoMessage() {}
+
+func (x *DeletePostResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePostResponse.ProtoReflect.Descriptor instead.
+func (*DeletePostResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{26}
+}
+
+// Поток новых постов ленты.
+type WatchPostsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchPostsRequest) Reset() {
+	*x = WatchPostsRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchPostsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPostsRequest) ProtoMessage() {}
+
+func (x *WatchPostsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPostsRequest.ProtoReflect.Descriptor instead.
+func (*WatchPostsRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{27}
+}
+
+type WatchPostsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Post  *Post                  `protobuf:"bytes,1,opt,name=post,proto3" json:"post,omitempty"`
+	// сколько событий перед этим потеряно, потому что клиент не успевал читать
+	Dropped       int32 `protobuf:"varint,2,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchPostsResponse) Reset() {
+	*x = WatchPostsResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchPostsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPostsResponse) ProtoMessage() {}
+
+func (x *WatchPostsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPostsResponse.ProtoReflect.Descriptor instead.
+func (*WatchPostsResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *WatchPostsResponse) GetPost() *Post {
+	if x != nil {
+		return x.Post
+	}
+	return nil
+}
+
+func (x *WatchPostsResponse) GetDropped() int32 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+type CreateCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PostId        string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	AuthorId      string                 `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"` // необязателен: автор берётся из токена, при расхождении PERMISSION_DENIED
+	ParentId      string                 `protobuf:"bytes,3,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"` // "" => root
+	Text          string                 `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCommentRequest) Reset() {
+	*x = CreateCommentRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCommentRequest) ProtoMessage() {}
+
+func (x *CreateCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCommentRequest.ProtoReflect.Descriptor instead.
+func (*CreateCommentRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *CreateCommentRequest) GetPostId() string {
+	if x != nil {
+		return x.PostId
+	}
+	return ""
+}
+
+func (x *CreateCommentRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *CreateCommentRequest) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *CreateCommentRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type Comment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PostId        string                 `protobuf:"bytes,2,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	AuthorId      string                 `protobuf:"bytes,3,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	ParentId      string                 `protobuf:"bytes,4,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Text          string                 `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	EditedAt      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=edited_at,json=editedAt,proto3" json:"edited_at,omitempty"` // не задано => не редактировался
+	Deleted       bool                   `protobuf:"varint,8,opt,name=deleted,proto3" json:"deleted,omitempty"`                  // true => text = "[deleted]", author_id = ""
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Comment) Reset() {
+	*x = Comment{}
+	mi := &file_service_v1_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Comment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Comment) ProtoMessage() {}
+
+func (x *Comment) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Comment.ProtoReflect.Descriptor instead.
+func (*Comment) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *Comment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Comment) GetPostId() string {
+	if x != nil {
+		return x.PostId
+	}
+	return ""
+}
+
+func (x *Comment) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *Comment) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Comment) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Comment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Comment) GetEditedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EditedAt
+	}
+	return nil
+}
+
+func (x *Comment) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+type CreateCommentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comment       *Comment               `protobuf:"bytes,1,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCommentResponse) Reset() {
+	*x = CreateCommentResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPostResponse) String() string {
+func (x *CreateCommentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPostResponse) ProtoMessage() {}
+func (*CreateCommentResponse) ProtoMessage() {}
 
-func (x *GetPostResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[11]
+func (x *CreateCommentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -642,42 +1777,43 @@ func (x *GetPostResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPostResponse.ProtoReflect.Descriptor instead.
-func (*GetPostResponse) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use CreateCommentResponse.ProtoReflect.Descriptor instead.
+func (*CreateCommentResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *GetPostResponse) GetPost() *Post {
+func (x *CreateCommentResponse) GetComment() *Comment {
 	if x != nil {
-		return x.Post
+		return x.Comment
 	}
 	return nil
 }
 
-type GetPostsRequest struct {
+type GetCommentsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
-	First         int32                  `protobuf:"varint,2,opt,name=first,proto3" json:"first,omitempty"`
-	After         string                 `protobuf:"bytes,3,opt,name=after,proto3" json:"after,omitempty"`
+	PostId        string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	ParentId      string                 `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	First         int32                  `protobuf:"varint,3,opt,name=first,proto3" json:"first,omitempty"`
+	After         string                 `protobuf:"bytes,4,opt,name=after,proto3" json:"after,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPostsRequest) Reset() {
-	*x = GetPostsRequest{}
-	mi := &file_service_v1_service_proto_msgTypes[12]
+func (x *GetCommentsRequest) Reset() {
+	*x = GetCommentsRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPostsRequest) String() string {
+func (x *GetCommentsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPostsRequest) ProtoMessage() {}
+func (*GetCommentsRequest) ProtoMessage() {}
 
-func (x *GetPostsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[12]
+func (x *GetCommentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -688,56 +1824,192 @@ func (x *GetPostsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPostsRequest.ProtoReflect.Descriptor instead.
-func (*GetPostsRequest) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use GetCommentsRequest.ProtoReflect.Descriptor instead.
+func (*GetCommentsRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *GetPostsRequest) GetIds() []string {
+func (x *GetCommentsRequest) GetPostId() string {
 	if x != nil {
-		return x.Ids
+		return x.PostId
+	}
+	return ""
+}
+
+func (x *GetCommentsRequest) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *GetCommentsRequest) GetFirst() int32 {
+	if x != nil {
+		return x.First
+	}
+	return 0
+}
+
+func (x *GetCommentsRequest) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+type GetCommentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comments      []*Comment             `protobuf:"bytes,1,rep,name=comments,proto3" json:"comments,omitempty"`
+	EndCursor     string                 `protobuf:"bytes,2,opt,name=end_cursor,json=endCursor,proto3" json:"end_cursor,omitempty"`
+	HasNextPage   bool                   `protobuf:"varint,3,opt,name=has_next_page,json=hasNextPage,proto3" json:"has_next_page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentsResponse) Reset() {
+	*x = GetCommentsResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentsResponse) ProtoMessage() {}
+
+func (x *GetCommentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentsResponse.ProtoReflect.Descriptor instead.
+func (*GetCommentsResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetCommentsResponse) GetComments() []*Comment {
+	if x != nil {
+		return x.Comments
 	}
 	return nil
 }
 
-func (x *GetPostsRequest) GetFirst() int32 {
+func (x *GetCommentsResponse) GetEndCursor() string {
+	if x != nil {
+		return x.EndCursor
+	}
+	return ""
+}
+
+func (x *GetCommentsResponse) GetHasNextPage() bool {
+	if x != nil {
+		return x.HasNextPage
+	}
+	return false
+}
+
+// Комментарии новее курсора after, от старых к новым; без parent_id — все уровни дерева.
+type GetCommentsSinceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PostId        string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	ParentId      string                 `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	First         int32                  `protobuf:"varint,3,opt,name=first,proto3" json:"first,omitempty"`
+	After         string                 `protobuf:"bytes,4,opt,name=after,proto3" json:"after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentsSinceRequest) Reset() {
+	*x = GetCommentsSinceRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentsSinceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentsSinceRequest) ProtoMessage() {}
+
+func (x *GetCommentsSinceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentsSinceRequest.ProtoReflect.Descriptor instead.
+func (*GetCommentsSinceRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetCommentsSinceRequest) GetPostId() string {
+	if x != nil {
+		return x.PostId
+	}
+	return ""
+}
+
+func (x *GetCommentsSinceRequest) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *GetCommentsSinceRequest) GetFirst() int32 {
 	if x != nil {
 		return x.First
 	}
 	return 0
 }
 
-func (x *GetPostsRequest) GetAfter() string {
+func (x *GetCommentsSinceRequest) GetAfter() string {
 	if x != nil {
 		return x.After
 	}
 	return ""
 }
 
-type GetPostsResponse struct {
+type GetCommentsSinceResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Posts         []*Post                `protobuf:"bytes,1,rep,name=posts,proto3" json:"posts,omitempty"`
+	Comments      []*Comment             `protobuf:"bytes,1,rep,name=comments,proto3" json:"comments,omitempty"`
 	EndCursor     string                 `protobuf:"bytes,2,opt,name=end_cursor,json=endCursor,proto3" json:"end_cursor,omitempty"`
 	HasNextPage   bool                   `protobuf:"varint,3,opt,name=has_next_page,json=hasNextPage,proto3" json:"has_next_page,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPostsResponse) Reset() {
-	*x = GetPostsResponse{}
-	mi := &file_service_v1_service_proto_msgTypes[13]
+func (x *GetCommentsSinceResponse) Reset() {
+	*x = GetCommentsSinceResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPostsResponse) String() string {
+func (x *GetCommentsSinceResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPostsResponse) ProtoMessage() {}
+func (*GetCommentsSinceResponse) ProtoMessage() {}
 
-func (x *GetPostsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[13]
+func (x *GetCommentsSinceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -748,57 +2020,98 @@ func (x *GetPostsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPostsResponse.ProtoReflect.Descriptor instead.
-func (*GetPostsResponse) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use GetCommentsSinceResponse.ProtoReflect.Descriptor instead.
+func (*GetCommentsSinceResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *GetPostsResponse) GetPosts() []*Post {
+func (x *GetCommentsSinceResponse) GetComments() []*Comment {
 	if x != nil {
-		return x.Posts
+		return x.Comments
 	}
 	return nil
 }
 
-func (x *GetPostsResponse) GetEndCursor() string {
+func (x *GetCommentsSinceResponse) GetEndCursor() string {
 	if x != nil {
 		return x.EndCursor
 	}
 	return ""
 }
 
-func (x *GetPostsResponse) GetHasNextPage() bool {
+func (x *GetCommentsSinceResponse) GetHasNextPage() bool {
+	if x != nil {
+		return x.HasNextPage
+	}
+	return false
+}
+
+type GetCommentsByIDsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentsByIDsRequest) Reset() {
+	*x = GetCommentsByIDsRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentsByIDsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentsByIDsRequest) ProtoMessage() {}
+
+func (x *GetCommentsByIDsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentsByIDsRequest.ProtoReflect.Descriptor instead.
+func (*GetCommentsByIDsRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetCommentsByIDsRequest) GetIds() []string {
 	if x != nil {
-		return x.HasNextPage
+		return x.Ids
 	}
-	return false
+	return nil
 }
 
-type CreateCommentRequest struct {
+type GetCommentsByIDsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PostId        string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
-	AuthorId      string                 `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
-	ParentId      string                 `protobuf:"bytes,3,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"` // "" => root
-	Text          string                 `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
+	Comments      []*Comment             `protobuf:"bytes,1,rep,name=comments,proto3" json:"comments,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateCommentRequest) Reset() {
-	*x = CreateCommentRequest{}
-	mi := &file_service_v1_service_proto_msgTypes[14]
+func (x *GetCommentsByIDsResponse) Reset() {
+	*x = GetCommentsByIDsResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateCommentRequest) String() string {
+func (x *GetCommentsByIDsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateCommentRequest) ProtoMessage() {}
+func (*GetCommentsByIDsResponse) ProtoMessage() {}
 
-func (x *CreateCommentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[14]
+func (x *GetCommentsByIDsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -809,66 +2122,42 @@ func (x *CreateCommentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateCommentRequest.ProtoReflect.Descriptor instead.
-func (*CreateCommentRequest) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{14}
-}
-
-func (x *CreateCommentRequest) GetPostId() string {
-	if x != nil {
-		return x.PostId
-	}
-	return ""
-}
-
-func (x *CreateCommentRequest) GetAuthorId() string {
-	if x != nil {
-		return x.AuthorId
-	}
-	return ""
-}
-
-func (x *CreateCommentRequest) GetParentId() string {
-	if x != nil {
-		return x.ParentId
-	}
-	return ""
+// Deprecated: Use GetCommentsByIDsResponse.ProtoReflect.Descriptor instead.
+func (*GetCommentsByIDsResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *CreateCommentRequest) GetText() string {
+func (x *GetCommentsByIDsResponse) GetComments() []*Comment {
 	if x != nil {
-		return x.Text
+		return x.Comments
 	}
-	return ""
+	return nil
 }
 
-type Comment struct {
+type EditCommentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	PostId        string                 `protobuf:"bytes,2,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
-	AuthorId      string                 `protobuf:"bytes,3,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
-	ParentId      string                 `protobuf:"bytes,4,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
-	Text          string                 `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	AuthorId      string                 `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"` // необязателен: автор берётся из токена, при расхождении PERMISSION_DENIED
+	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Comment) Reset() {
-	*x = Comment{}
-	mi := &file_service_v1_service_proto_msgTypes[15]
+func (x *EditCommentRequest) Reset() {
+	*x = EditCommentRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Comment) String() string {
+func (x *EditCommentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Comment) ProtoMessage() {}
+func (*EditCommentRequest) ProtoMessage() {}
 
-func (x *Comment) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[15]
+func (x *EditCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -879,75 +2168,54 @@ func (x *Comment) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Comment.ProtoReflect.Descriptor instead.
-func (*Comment) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use EditCommentRequest.ProtoReflect.Descriptor instead.
+func (*EditCommentRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *Comment) GetId() string {
+func (x *EditCommentRequest) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-func (x *Comment) GetPostId() string {
-	if x != nil {
-		return x.PostId
-	}
-	return ""
-}
-
-func (x *Comment) GetAuthorId() string {
+func (x *EditCommentRequest) GetAuthorId() string {
 	if x != nil {
 		return x.AuthorId
 	}
 	return ""
 }
 
-func (x *Comment) GetParentId() string {
-	if x != nil {
-		return x.ParentId
-	}
-	return ""
-}
-
-func (x *Comment) GetText() string {
+func (x *EditCommentRequest) GetText() string {
 	if x != nil {
 		return x.Text
 	}
 	return ""
 }
 
-func (x *Comment) GetCreatedAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.CreatedAt
-	}
-	return nil
-}
-
-type CreateCommentResponse struct {
+type EditCommentResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Comment       *Comment               `protobuf:"bytes,1,opt,name=comment,proto3" json:"comment,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateCommentResponse) Reset() {
-	*x = CreateCommentResponse{}
-	mi := &file_service_v1_service_proto_msgTypes[16]
+func (x *EditCommentResponse) Reset() {
+	*x = EditCommentResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateCommentResponse) String() string {
+func (x *EditCommentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateCommentResponse) ProtoMessage() {}
+func (*EditCommentResponse) ProtoMessage() {}
 
-func (x *CreateCommentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[16]
+func (x *EditCommentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -958,43 +2226,41 @@ func (x *CreateCommentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateCommentResponse.ProtoReflect.Descriptor instead.
-func (*CreateCommentResponse) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use EditCommentResponse.ProtoReflect.Descriptor instead.
+func (*EditCommentResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *CreateCommentResponse) GetComment() *Comment {
+func (x *EditCommentResponse) GetComment() *Comment {
 	if x != nil {
 		return x.Comment
 	}
 	return nil
 }
 
-type GetCommentsRequest struct {
+type DeleteCommentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PostId        string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
-	ParentId      string                 `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
-	First         int32                  `protobuf:"varint,3,opt,name=first,proto3" json:"first,omitempty"`
-	After         string                 `protobuf:"bytes,4,opt,name=after,proto3" json:"after,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AuthorId      string                 `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"` // необязателен: автор берётся из токена, при расхождении PERMISSION_DENIED
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCommentsRequest) Reset() {
-	*x = GetCommentsRequest{}
-	mi := &file_service_v1_service_proto_msgTypes[17]
+func (x *DeleteCommentRequest) Reset() {
+	*x = DeleteCommentRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCommentsRequest) String() string {
+func (x *DeleteCommentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCommentsRequest) ProtoMessage() {}
+func (*DeleteCommentRequest) ProtoMessage() {}
 
-func (x *GetCommentsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[17]
+func (x *DeleteCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1005,63 +2271,47 @@ func (x *GetCommentsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCommentsRequest.ProtoReflect.Descriptor instead.
-func (*GetCommentsRequest) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{17}
-}
-
-func (x *GetCommentsRequest) GetPostId() string {
-	if x != nil {
-		return x.PostId
-	}
-	return ""
+// Deprecated: Use DeleteCommentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCommentRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *GetCommentsRequest) GetParentId() string {
+func (x *DeleteCommentRequest) GetId() string {
 	if x != nil {
-		return x.ParentId
+		return x.Id
 	}
 	return ""
 }
 
-func (x *GetCommentsRequest) GetFirst() int32 {
-	if x != nil {
-		return x.First
-	}
-	return 0
-}
-
-func (x *GetCommentsRequest) GetAfter() string {
+func (x *DeleteCommentRequest) GetAuthorId() string {
 	if x != nil {
-		return x.After
+		return x.AuthorId
 	}
 	return ""
 }
 
-type GetCommentsResponse struct {
+type DeleteCommentResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Comments      []*Comment             `protobuf:"bytes,1,rep,name=comments,proto3" json:"comments,omitempty"`
-	EndCursor     string                 `protobuf:"bytes,2,opt,name=end_cursor,json=endCursor,proto3" json:"end_cursor,omitempty"`
-	HasNextPage   bool                   `protobuf:"varint,3,opt,name=has_next_page,json=hasNextPage,proto3" json:"has_next_page,omitempty"`
+	Comment       *Comment               `protobuf:"bytes,1,opt,name=comment,proto3" json:"comment,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCommentsResponse) Reset() {
-	*x = GetCommentsResponse{}
-	mi := &file_service_v1_service_proto_msgTypes[18]
+func (x *DeleteCommentResponse) Reset() {
+	*x = DeleteCommentResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCommentsResponse) String() string {
+func (x *DeleteCommentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCommentsResponse) ProtoMessage() {}
+func (*DeleteCommentResponse) ProtoMessage() {}
 
-func (x *GetCommentsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[18]
+func (x *DeleteCommentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1072,54 +2322,45 @@ func (x *GetCommentsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCommentsResponse.ProtoReflect.Descriptor instead.
-func (*GetCommentsResponse) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use DeleteCommentResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCommentResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *GetCommentsResponse) GetComments() []*Comment {
+func (x *DeleteCommentResponse) GetComment() *Comment {
 	if x != nil {
-		return x.Comments
+		return x.Comment
 	}
 	return nil
 }
 
-func (x *GetCommentsResponse) GetEndCursor() string {
-	if x != nil {
-		return x.EndCursor
-	}
-	return ""
-}
-
-func (x *GetCommentsResponse) GetHasNextPage() bool {
-	if x != nil {
-		return x.HasNextPage
-	}
-	return false
-}
-
-type GetCommentsByIDsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+// Поток событий о комментариях поста; с parent_id — только ответы на этот комментарий.
+type WatchCommentsRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	PostId   string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	ParentId string                 `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	// курсор последнего полученного комментария: сначала придут все более новые
+	After         string             `protobuf:"bytes,3,opt,name=after,proto3" json:"after,omitempty"`
+	Types         []CommentEventType `protobuf:"varint,4,rep,packed,name=types,proto3,enum=service.v1.CommentEventType" json:"types,omitempty"` // пусто => только COMMENT_EVENT_TYPE_CREATED
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCommentsByIDsRequest) Reset() {
-	*x = GetCommentsByIDsRequest{}
-	mi := &file_service_v1_service_proto_msgTypes[19]
+func (x *WatchCommentsRequest) Reset() {
+	*x = WatchCommentsRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCommentsByIDsRequest) String() string {
+func (x *WatchCommentsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCommentsByIDsRequest) ProtoMessage() {}
+func (*WatchCommentsRequest) ProtoMessage() {}
 
-func (x *GetCommentsByIDsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[19]
+func (x *WatchCommentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1130,40 +2371,64 @@ func (x *GetCommentsByIDsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCommentsByIDsRequest.ProtoReflect.Descriptor instead.
-func (*GetCommentsByIDsRequest) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use WatchCommentsRequest.ProtoReflect.Descriptor instead.
+func (*WatchCommentsRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *GetCommentsByIDsRequest) GetIds() []string {
+func (x *WatchCommentsRequest) GetPostId() string {
 	if x != nil {
-		return x.Ids
+		return x.PostId
+	}
+	return ""
+}
+
+func (x *WatchCommentsRequest) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *WatchCommentsRequest) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+func (x *WatchCommentsRequest) GetTypes() []CommentEventType {
+	if x != nil {
+		return x.Types
 	}
 	return nil
 }
 
-type GetCommentsByIDsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Comments      []*Comment             `protobuf:"bytes,1,rep,name=comments,proto3" json:"comments,omitempty"`
+type WatchCommentsResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Comment *Comment               `protobuf:"bytes,1,opt,name=comment,proto3" json:"comment,omitempty"`
+	// сколько событий перед этим потеряно, потому что клиент не успевал читать
+	Dropped       int32            `protobuf:"varint,2,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	Type          CommentEventType `protobuf:"varint,3,opt,name=type,proto3,enum=service.v1.CommentEventType" json:"type,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCommentsByIDsResponse) Reset() {
-	*x = GetCommentsByIDsResponse{}
-	mi := &file_service_v1_service_proto_msgTypes[20]
+func (x *WatchCommentsResponse) Reset() {
+	*x = WatchCommentsResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCommentsByIDsResponse) String() string {
+func (x *WatchCommentsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCommentsByIDsResponse) ProtoMessage() {}
+func (*WatchCommentsResponse) ProtoMessage() {}
 
-func (x *GetCommentsByIDsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[20]
+func (x *WatchCommentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1174,18 +2439,32 @@ func (x *GetCommentsByIDsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCommentsByIDsResponse.ProtoReflect.Descriptor instead.
-func (*GetCommentsByIDsResponse) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use WatchCommentsResponse.ProtoReflect.Descriptor instead.
+func (*WatchCommentsResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *GetCommentsByIDsResponse) GetComments() []*Comment {
+func (x *WatchCommentsResponse) GetComment() *Comment {
 	if x != nil {
-		return x.Comments
+		return x.Comment
 	}
 	return nil
 }
 
+func (x *WatchCommentsResponse) GetDropped() int32 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+func (x *WatchCommentsResponse) GetType() CommentEventType {
+	if x != nil {
+		return x.Type
+	}
+	return CommentEventType_COMMENT_EVENT_TYPE_UNSPECIFIED
+}
+
 var File_service_v1_service_proto protoreflect.FileDescriptor
 
 const file_service_v1_service_proto_rawDesc = "" +
@@ -1194,9 +2473,35 @@ const file_service_v1_service_proto_rawDesc = "" +
 	"service.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"@\n" +
 	"\fLoginRequest\x12\x14\n" +
 	"\x05login\x18\x01 \x01(\tR\x05login\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"%\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"J\n" +
 	"\rLoginResponse\x12\x14\n" +
-	"\x05token\x18\x01 \x01(\tR\x05token\"s\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\":\n" +
+	"\x13RefreshTokenRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"Q\n" +
+	"\x14RefreshTokenResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\"4\n" +
+	"\rLogoutRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"\x10\n" +
+	"\x0eLogoutResponse\"4\n" +
+	"\x13CheckSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\".\n" +
+	"\x14CheckSessionResponse\x12\x16\n" +
+	"\x06active\x18\x01 \x01(\bR\x06active\"\x89\x01\n" +
+	"\x03JWK\x12\x10\n" +
+	"\x03kid\x18\x01 \x01(\tR\x03kid\x12\x10\n" +
+	"\x03kty\x18\x02 \x01(\tR\x03kty\x12\x10\n" +
+	"\x03alg\x18\x03 \x01(\tR\x03alg\x12\x10\n" +
+	"\x03use\x18\x04 \x01(\tR\x03use\x12\x10\n" +
+	"\x03crv\x18\x05 \x01(\tR\x03crv\x12\f\n" +
+	"\x01x\x18\x06 \x01(\tR\x01x\x12\f\n" +
+	"\x01n\x18\a \x01(\tR\x01n\x12\f\n" +
+	"\x01e\x18\b \x01(\tR\x01e\"\x10\n" +
+	"\x0eGetJWKSRequest\"6\n" +
+	"\x0fGetJWKSResponse\x12#\n" +
+	"\x04keys\x18\x01 \x03(\v2\x0f.service.v1.JWKR\x04keys\"s\n" +
 	"\x11CreateUserRequest\x12\x14\n" +
 	"\x05login\x18\x01 \x01(\tR\x05login\x12\x1a\n" +
 	"\bpassword\x18\x02 \x01(\tR\bpassword\x12\x12\n" +
@@ -1234,17 +2539,36 @@ const file_service_v1_service_proto_rawDesc = "" +
 	"\x0fGetPostsRequest\x12\x10\n" +
 	"\x03ids\x18\x01 \x03(\tR\x03ids\x12\x14\n" +
 	"\x05first\x18\x02 \x01(\x05R\x05first\x12\x14\n" +
-	"\x05after\x18\x03 \x01(\tR\x05after\"}\n" +
+	"\x05after\x18\x03 \x01(\tR\x05after\"\x9e\x01\n" +
 	"\x10GetPostsResponse\x12&\n" +
 	"\x05posts\x18\x01 \x03(\v2\x10.service.v1.PostR\x05posts\x12\x1d\n" +
 	"\n" +
 	"end_cursor\x18\x02 \x01(\tR\tendCursor\x12\"\n" +
-	"\rhas_next_page\x18\x03 \x01(\bR\vhasNextPage\"}\n" +
+	"\rhas_next_page\x18\x03 \x01(\bR\vhasNextPage\x12\x1f\n" +
+	"\vmissing_ids\x18\x04 \x03(\tR\n" +
+	"missingIds\"\xa4\x01\n" +
+	"\x11UpdatePostRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tauthor_id\x18\x02 \x01(\tR\bauthorId\x12\x17\n" +
+	"\x04text\x18\x03 \x01(\tH\x00R\x04text\x88\x01\x01\x12,\n" +
+	"\x0fwithout_comment\x18\x04 \x01(\bH\x01R\x0ewithoutComment\x88\x01\x01B\a\n" +
+	"\x05_textB\x12\n" +
+	"\x10_without_comment\":\n" +
+	"\x12UpdatePostResponse\x12$\n" +
+	"\x04post\x18\x01 \x01(\v2\x10.service.v1.PostR\x04post\"@\n" +
+	"\x11DeletePostRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tauthor_id\x18\x02 \x01(\tR\bauthorId\"\x14\n" +
+	"\x12DeletePostResponse\"\x13\n" +
+	"\x11WatchPostsRequest\"T\n" +
+	"\x12WatchPostsResponse\x12$\n" +
+	"\x04post\x18\x01 \x01(\v2\x10.service.v1.PostR\x04post\x12\x18\n" +
+	"\adropped\x18\x02 \x01(\x05R\adropped\"}\n" +
 	"\x14CreateCommentRequest\x12\x17\n" +
 	"\apost_id\x18\x01 \x01(\tR\x06postId\x12\x1b\n" +
 	"\tauthor_id\x18\x02 \x01(\tR\bauthorId\x12\x1b\n" +
 	"\tparent_id\x18\x03 \x01(\tR\bparentId\x12\x12\n" +
-	"\x04text\x18\x04 \x01(\tR\x04text\"\xbb\x01\n" +
+	"\x04text\x18\x04 \x01(\tR\x04text\"\x8e\x02\n" +
 	"\aComment\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\apost_id\x18\x02 \x01(\tR\x06postId\x12\x1b\n" +
@@ -1252,7 +2576,9 @@ const file_service_v1_service_proto_rawDesc = "" +
 	"\tparent_id\x18\x04 \x01(\tR\bparentId\x12\x12\n" +
 	"\x04text\x18\x05 \x01(\tR\x04text\x129\n" +
 	"\n" +
-	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"F\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x127\n" +
+	"\tedited_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\beditedAt\x12\x18\n" +
+	"\adeleted\x18\b \x01(\bR\adeleted\"F\n" +
 	"\x15CreateCommentResponse\x12-\n" +
 	"\acomment\x18\x01 \x01(\v2\x13.service.v1.CommentR\acomment\"v\n" +
 	"\x12GetCommentsRequest\x12\x17\n" +
@@ -1264,26 +2590,75 @@ const file_service_v1_service_proto_rawDesc = "" +
 	"\bcomments\x18\x01 \x03(\v2\x13.service.v1.CommentR\bcomments\x12\x1d\n" +
 	"\n" +
 	"end_cursor\x18\x02 \x01(\tR\tendCursor\x12\"\n" +
+	"\rhas_next_page\x18\x03 \x01(\bR\vhasNextPage\"{\n" +
+	"\x17GetCommentsSinceRequest\x12\x17\n" +
+	"\apost_id\x18\x01 \x01(\tR\x06postId\x12\x1b\n" +
+	"\tparent_id\x18\x02 \x01(\tR\bparentId\x12\x14\n" +
+	"\x05first\x18\x03 \x01(\x05R\x05first\x12\x14\n" +
+	"\x05after\x18\x04 \x01(\tR\x05after\"\x8e\x01\n" +
+	"\x18GetCommentsSinceResponse\x12/\n" +
+	"\bcomments\x18\x01 \x03(\v2\x13.service.v1.CommentR\bcomments\x12\x1d\n" +
+	"\n" +
+	"end_cursor\x18\x02 \x01(\tR\tendCursor\x12\"\n" +
 	"\rhas_next_page\x18\x03 \x01(\bR\vhasNextPage\"+\n" +
 	"\x17GetCommentsByIDsRequest\x12\x10\n" +
 	"\x03ids\x18\x01 \x03(\tR\x03ids\"K\n" +
 	"\x18GetCommentsByIDsResponse\x12/\n" +
-	"\bcomments\x18\x01 \x03(\v2\x13.service.v1.CommentR\bcomments2M\n" +
+	"\bcomments\x18\x01 \x03(\v2\x13.service.v1.CommentR\bcomments\"U\n" +
+	"\x12EditCommentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tauthor_id\x18\x02 \x01(\tR\bauthorId\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\"D\n" +
+	"\x13EditCommentResponse\x12-\n" +
+	"\acomment\x18\x01 \x01(\v2\x13.service.v1.CommentR\acomment\"C\n" +
+	"\x14DeleteCommentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tauthor_id\x18\x02 \x01(\tR\bauthorId\"F\n" +
+	"\x15DeleteCommentResponse\x12-\n" +
+	"\acomment\x18\x01 \x01(\v2\x13.service.v1.CommentR\acomment\"\x96\x01\n" +
+	"\x14WatchCommentsRequest\x12\x17\n" +
+	"\apost_id\x18\x01 \x01(\tR\x06postId\x12\x1b\n" +
+	"\tparent_id\x18\x02 \x01(\tR\bparentId\x12\x14\n" +
+	"\x05after\x18\x03 \x01(\tR\x05after\x122\n" +
+	"\x05types\x18\x04 \x03(\x0e2\x1c.service.v1.CommentEventTypeR\x05types\"\x92\x01\n" +
+	"\x15WatchCommentsResponse\x12-\n" +
+	"\acomment\x18\x01 \x01(\v2\x13.service.v1.CommentR\acomment\x12\x18\n" +
+	"\adropped\x18\x02 \x01(\x05R\adropped\x120\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x1c.service.v1.CommentEventTypeR\x04type*\x95\x01\n" +
+	"\x10CommentEventType\x12\"\n" +
+	"\x1eCOMMENT_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aCOMMENT_EVENT_TYPE_CREATED\x10\x01\x12\x1d\n" +
+	"\x19COMMENT_EVENT_TYPE_EDITED\x10\x02\x12\x1e\n" +
+	"\x1aCOMMENT_EVENT_TYPE_DELETED\x10\x032\x80\x03\n" +
 	"\vAuthService\x12>\n" +
-	"\x05Login\x12\x18.service.v1.LoginRequest\x1a\x19.service.v1.LoginResponse\"\x002\xa5\x01\n" +
+	"\x05Login\x12\x18.service.v1.LoginRequest\x1a\x19.service.v1.LoginResponse\"\x00\x12S\n" +
+	"\fRefreshToken\x12\x1f.service.v1.RefreshTokenRequest\x1a .service.v1.RefreshTokenResponse\"\x00\x12A\n" +
+	"\x06Logout\x12\x19.service.v1.LogoutRequest\x1a\x1a.service.v1.LogoutResponse\"\x00\x12S\n" +
+	"\fCheckSession\x12\x1f.service.v1.CheckSessionRequest\x1a .service.v1.CheckSessionResponse\"\x00\x12D\n" +
+	"\aGetJWKS\x12\x1a.service.v1.GetJWKSRequest\x1a\x1b.service.v1.GetJWKSResponse\"\x002\xa5\x01\n" +
 	"\vUserService\x12M\n" +
 	"\n" +
 	"CreateUser\x12\x1d.service.v1.CreateUserRequest\x1a\x1e.service.v1.CreateUserResponse\"\x00\x12G\n" +
-	"\bGetUsers\x12\x1b.service.v1.GetUsersRequest\x1a\x1c.service.v1.GetUsersResponse\"\x002\xeb\x01\n" +
+	"\bGetUsers\x12\x1b.service.v1.GetUsersRequest\x1a\x1c.service.v1.GetUsersResponse\"\x002\xda\x03\n" +
 	"\vPostService\x12M\n" +
 	"\n" +
 	"CreatePost\x12\x1d.service.v1.CreatePostRequest\x1a\x1e.service.v1.CreatePostResponse\"\x00\x12G\n" +
 	"\bGetPosts\x12\x1b.service.v1.GetPostsRequest\x1a\x1c.service.v1.GetPostsResponse\"\x00\x12D\n" +
-	"\aGetPost\x12\x1a.service.v1.GetPostRequest\x1a\x1b.service.v1.GetPostResponse\"\x002\x9b\x02\n" +
+	"\aGetPost\x12\x1a.service.v1.GetPostRequest\x1a\x1b.service.v1.GetPostResponse\"\x00\x12M\n" +
+	"\n" +
+	"UpdatePost\x12\x1d.service.v1.UpdatePostRequest\x1a\x1e.service.v1.UpdatePostResponse\"\x00\x12M\n" +
+	"\n" +
+	"DeletePost\x12\x1d.service.v1.DeletePostRequest\x1a\x1e.service.v1.DeletePostResponse\"\x00\x12O\n" +
+	"\n" +
+	"WatchPosts\x12\x1d.service.v1.WatchPostsRequest\x1a\x1e.service.v1.WatchPostsResponse\"\x000\x012\x80\x05\n" +
 	"\x0eCommentService\x12V\n" +
 	"\rCreateComment\x12 .service.v1.CreateCommentRequest\x1a!.service.v1.CreateCommentResponse\"\x00\x12P\n" +
 	"\vGetComments\x12\x1e.service.v1.GetCommentsRequest\x1a\x1f.service.v1.GetCommentsResponse\"\x00\x12_\n" +
-	"\x10GetCommentsByIDs\x12#.service.v1.GetCommentsByIDsRequest\x1a$.service.v1.GetCommentsByIDsResponse\"\x00BCZAgithub.com/Parnishkaspb/ozon_posts_proto/gen/service/v1;servicepbb\x06proto3"
+	"\x10GetCommentsSince\x12#.service.v1.GetCommentsSinceRequest\x1a$.service.v1.GetCommentsSinceResponse\"\x00\x12_\n" +
+	"\x10GetCommentsByIDs\x12#.service.v1.GetCommentsByIDsRequest\x1a$.service.v1.GetCommentsByIDsResponse\"\x00\x12P\n" +
+	"\vEditComment\x12\x1e.service.v1.EditCommentRequest\x1a\x1f.service.v1.EditCommentResponse\"\x00\x12V\n" +
+	"\rDeleteComment\x12 .service.v1.DeleteCommentRequest\x1a!.service.v1.DeleteCommentResponse\"\x00\x12X\n" +
+	"\rWatchComments\x12 .service.v1.WatchCommentsRequest\x1a!.service.v1.WatchCommentsResponse\"\x000\x01BCZAgithub.com/Parnishkaspb/ozon_posts_proto/gen/service/v1;servicepbb\x06proto3"
 
 var (
 	file_service_v1_service_proto_rawDescOnce sync.Once
@@ -1297,65 +2672,122 @@ func file_service_v1_service_proto_rawDescGZIP() []byte {
 	return file_service_v1_service_proto_rawDescData
 }
 
-var file_service_v1_service_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_service_v1_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_service_v1_service_proto_msgTypes = make([]protoimpl.MessageInfo, 44)
 var file_service_v1_service_proto_goTypes = []any{
-	(*LoginRequest)(nil),             // 0: service.v1.LoginRequest
-	(*LoginResponse)(nil),            // 1: service.v1.LoginResponse
-	(*CreateUserRequest)(nil),        // 2: service.v1.CreateUserRequest
-	(*CreateUserResponse)(nil),       // 3: service.v1.CreateUserResponse
-	(*GetUsersRequest)(nil),          // 4: service.v1.GetUsersRequest
-	(*User)(nil),                     // 5: service.v1.User
-	(*GetUsersResponse)(nil),         // 6: service.v1.GetUsersResponse
-	(*CreatePostRequest)(nil),        // 7: service.v1.CreatePostRequest
-	(*Post)(nil),                     // 8: service.v1.Post
-	(*CreatePostResponse)(nil),       // 9: service.v1.CreatePostResponse
-	(*GetPostRequest)(nil),           // 10: service.v1.GetPostRequest
-	(*GetPostResponse)(nil),          // 11: service.v1.GetPostResponse
-	(*GetPostsRequest)(nil),          // 12: service.v1.GetPostsRequest
-	(*GetPostsResponse)(nil),         // 13: service.v1.GetPostsResponse
-	(*CreateCommentRequest)(nil),     // 14: service.v1.CreateCommentRequest
-	(*Comment)(nil),                  // 15: service.v1.Comment
-	(*CreateCommentResponse)(nil),    // 16: service.v1.CreateCommentResponse
-	(*GetCommentsRequest)(nil),       // 17: service.v1.GetCommentsRequest
-	(*GetCommentsResponse)(nil),      // 18: service.v1.GetCommentsResponse
-	(*GetCommentsByIDsRequest)(nil),  // 19: service.v1.GetCommentsByIDsRequest
-	(*GetCommentsByIDsResponse)(nil), // 20: service.v1.GetCommentsByIDsResponse
-	(*timestamppb.Timestamp)(nil),    // 21: google.protobuf.Timestamp
+	(CommentEventType)(0),            // 0: service.v1.CommentEventType
+	(*LoginRequest)(nil),             // 1: service.v1.LoginRequest
+	(*LoginResponse)(nil),            // 2: service.v1.LoginResponse
+	(*RefreshTokenRequest)(nil),      // 3: service.v1.RefreshTokenRequest
+	(*RefreshTokenResponse)(nil),     // 4: service.v1.RefreshTokenResponse
+	(*LogoutRequest)(nil),            // 5: service.v1.LogoutRequest
+	(*LogoutResponse)(nil),           // 6: service.v1.LogoutResponse
+	(*CheckSessionRequest)(nil),      // 7: service.v1.CheckSessionRequest
+	(*CheckSessionResponse)(nil),     // 8: service.v1.CheckSessionResponse
+	(*JWK)(nil),                      // 9: service.v1.JWK
+	(*GetJWKSRequest)(nil),           // 10: service.v1.GetJWKSRequest
+	(*GetJWKSResponse)(nil),          // 11: service.v1.GetJWKSResponse
+	(*CreateUserRequest)(nil),        // 12: service.v1.CreateUserRequest
+	(*CreateUserResponse)(nil),       // 13: service.v1.CreateUserResponse
+	(*GetUsersRequest)(nil),          // 14: service.v1.GetUsersRequest
+	(*User)(nil),                     // 15: service.v1.User
+	(*GetUsersResponse)(nil),         // 16: service.v1.GetUsersResponse
+	(*CreatePostRequest)(nil),        // 17: service.v1.CreatePostRequest
+	(*Post)(nil),                     // 18: service.v1.Post
+	(*CreatePostResponse)(nil),       // 19: service.v1.CreatePostResponse
+	(*GetPostRequest)(nil),           // 20: service.v1.GetPostRequest
+	(*GetPostResponse)(nil),          // 21: service.v1.GetPostResponse
+	(*GetPostsRequest)(nil),          // 22: service.v1.GetPostsRequest
+	(*GetPostsResponse)(nil),         // 23: service.v1.GetPostsResponse
+	(*UpdatePostRequest)(nil),        // 24: service.v1.UpdatePostRequest
+	(*UpdatePostResponse)(nil),       // 25: service.v1.UpdatePostResponse
+	(*DeletePostRequest)(nil),        // 26: service.v1.DeletePostRequest
+	(*DeletePostResponse)(nil),       // 27: service.v1.DeletePostResponse
+	(*WatchPostsRequest)(nil),        // 28: service.v1.WatchPostsRequest
+	(*WatchPostsResponse)(nil),       // 29: service.v1.WatchPostsResponse
+	(*CreateCommentRequest)(nil),     // 30: service.v1.CreateCommentRequest
+	(*Comment)(nil),                  // 31: service.v1.Comment
+	(*CreateCommentResponse)(nil),    // 32: service.v1.CreateCommentResponse
+	(*GetCommentsRequest)(nil),       // 33: service.v1.GetCommentsRequest
+	(*GetCommentsResponse)(nil),      // 34: service.v1.GetCommentsResponse
+	(*GetCommentsSinceRequest)(nil),  // 35: service.v1.GetCommentsSinceRequest
+	(*GetCommentsSinceResponse)(nil), // 36: service.v1.GetCommentsSinceResponse
+	(*GetCommentsByIDsRequest)(nil),  // 37: service.v1.GetCommentsByIDsRequest
+	(*GetCommentsByIDsResponse)(nil), // 38: service.v1.GetCommentsByIDsResponse
+	(*EditCommentRequest)(nil),       // 39: service.v1.EditCommentRequest
+	(*EditCommentResponse)(nil),      // 40: service.v1.EditCommentResponse
+	(*DeleteCommentRequest)(nil),     // 41: service.v1.DeleteCommentRequest
+	(*DeleteCommentResponse)(nil),    // 42: service.v1.DeleteCommentResponse
+	(*WatchCommentsRequest)(nil),     // 43: service.v1.WatchCommentsRequest
+	(*WatchCommentsResponse)(nil),    // 44: service.v1.WatchCommentsResponse
+	(*timestamppb.Timestamp)(nil),    // 45: google.protobuf.Timestamp
 }
 var file_service_v1_service_proto_depIdxs = []int32{
-	5,  // 0: service.v1.GetUsersResponse.users:type_name -> service.v1.User
-	21, // 1: service.v1.Post.created_at:type_name -> google.protobuf.Timestamp
-	21, // 2: service.v1.Post.updated_at:type_name -> google.protobuf.Timestamp
-	8,  // 3: service.v1.CreatePostResponse.post:type_name -> service.v1.Post
-	8,  // 4: service.v1.GetPostResponse.post:type_name -> service.v1.Post
-	8,  // 5: service.v1.GetPostsResponse.posts:type_name -> service.v1.Post
-	21, // 6: service.v1.Comment.created_at:type_name -> google.protobuf.Timestamp
-	15, // 7: service.v1.CreateCommentResponse.comment:type_name -> service.v1.Comment
-	15, // 8: service.v1.GetCommentsResponse.comments:type_name -> service.v1.Comment
-	15, // 9: service.v1.GetCommentsByIDsResponse.comments:type_name -> service.v1.Comment
-	0,  // 10: service.v1.AuthService.Login:input_type -> service.v1.LoginRequest
-	2,  // 11: service.v1.UserService.CreateUser:input_type -> service.v1.CreateUserRequest
-	4,  // 12: service.v1.UserService.GetUsers:input_type -> service.v1.GetUsersRequest
-	7,  // 13: service.v1.PostService.CreatePost:input_type -> service.v1.CreatePostRequest
-	12, // 14: service.v1.PostService.GetPosts:input_type -> service.v1.GetPostsRequest
-	10, // 15: service.v1.PostService.GetPost:input_type -> service.v1.GetPostRequest
-	14, // 16: service.v1.CommentService.CreateComment:input_type -> service.v1.CreateCommentRequest
-	17, // 17: service.v1.CommentService.GetComments:input_type -> service.v1.GetCommentsRequest
-	19, // 18: service.v1.CommentService.GetCommentsByIDs:input_type -> service.v1.GetCommentsByIDsRequest
-	1,  // 19: service.v1.AuthService.Login:output_type -> service.v1.LoginResponse
-	3,  // 20: service.v1.UserService.CreateUser:output_type -> service.v1.CreateUserResponse
-	6,  // 21: service.v1.UserService.GetUsers:output_type -> service.v1.GetUsersResponse
-	9,  // 22: service.v1.PostService.CreatePost:output_type -> service.v1.CreatePostResponse
-	13, // 23: service.v1.PostService.GetPosts:output_type -> service.v1.GetPostsResponse
-	11, // 24: service.v1.PostService.GetPost:output_type -> service.v1.GetPostResponse
-	16, // 25: service.v1.CommentService.CreateComment:output_type -> service.v1.CreateCommentResponse
-	18, // 26: service.v1.CommentService.GetComments:output_type -> service.v1.GetCommentsResponse
-	20, // 27: service.v1.CommentService.GetCommentsByIDs:output_type -> service.v1.GetCommentsByIDsResponse
-	19, // [19:28] is the sub-list for method output_type
-	10, // [10:19] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	9,  // 0: service.v1.GetJWKSResponse.keys:type_name -> service.v1.JWK
+	15, // 1: service.v1.GetUsersResponse.users:type_name -> service.v1.User
+	45, // 2: service.v1.Post.created_at:type_name -> google.protobuf.Timestamp
+	45, // 3: service.v1.Post.updated_at:type_name -> google.protobuf.Timestamp
+	18, // 4: service.v1.CreatePostResponse.post:type_name -> service.v1.Post
+	18, // 5: service.v1.GetPostResponse.post:type_name -> service.v1.Post
+	18, // 6: service.v1.GetPostsResponse.posts:type_name -> service.v1.Post
+	18, // 7: service.v1.UpdatePostResponse.post:type_name -> service.v1.Post
+	18, // 8: service.v1.WatchPostsResponse.post:type_name -> service.v1.Post
+	45, // 9: service.v1.Comment.created_at:type_name -> google.protobuf.Timestamp
+	45, // 10: service.v1.Comment.edited_at:type_name -> google.protobuf.Timestamp
+	31, // 11: service.v1.CreateCommentResponse.comment:type_name -> service.v1.Comment
+	31, // 12: service.v1.GetCommentsResponse.comments:type_name -> service.v1.Comment
+	31, // 13: service.v1.GetCommentsSinceResponse.comments:type_name -> service.v1.Comment
+	31, // 14: service.v1.GetCommentsByIDsResponse.comments:type_name -> service.v1.Comment
+	31, // 15: service.v1.EditCommentResponse.comment:type_name -> service.v1.Comment
+	31, // 16: service.v1.DeleteCommentResponse.comment:type_name -> service.v1.Comment
+	0,  // 17: service.v1.WatchCommentsRequest.types:type_name -> service.v1.CommentEventType
+	31, // 18: service.v1.WatchCommentsResponse.comment:type_name -> service.v1.Comment
+	0,  // 19: service.v1.WatchCommentsResponse.type:type_name -> service.v1.CommentEventType
+	1,  // 20: service.v1.AuthService.Login:input_type -> service.v1.LoginRequest
+	3,  // 21: service.v1.AuthService.RefreshToken:input_type -> service.v1.RefreshTokenRequest
+	5,  // 22: service.v1.AuthService.Logout:input_type -> service.v1.LogoutRequest
+	7,  // 23: service.v1.AuthService.CheckSession:input_type -> service.v1.CheckSessionRequest
+	10, // 24: service.v1.AuthService.GetJWKS:input_type -> service.v1.GetJWKSRequest
+	12, // 25: service.v1.UserService.CreateUser:input_type -> service.v1.CreateUserRequest
+	14, // 26: service.v1.UserService.GetUsers:input_type -> service.v1.GetUsersRequest
+	17, // 27: service.v1.PostService.CreatePost:input_type -> service.v1.CreatePostRequest
+	22, // 28: service.v1.PostService.GetPosts:input_type -> service.v1.GetPostsRequest
+	20, // 29: service.v1.PostService.GetPost:input_type -> service.v1.GetPostRequest
+	24, // 30: service.v1.PostService.UpdatePost:input_type -> service.v1.UpdatePostRequest
+	26, // 31: service.v1.PostService.DeletePost:input_type -> service.v1.DeletePostRequest
+	28, // 32: service.v1.PostService.WatchPosts:input_type -> service.v1.WatchPostsRequest
+	30, // 33: service.v1.CommentService.CreateComment:input_type -> service.v1.CreateCommentRequest
+	33, // 34: service.v1.CommentService.GetComments:input_type -> service.v1.GetCommentsRequest
+	35, // 35: service.v1.CommentService.GetCommentsSince:input_type -> service.v1.GetCommentsSinceRequest
+	37, // 36: service.v1.CommentService.GetCommentsByIDs:input_type -> service.v1.GetCommentsByIDsRequest
+	39, // 37: service.v1.CommentService.EditComment:input_type -> service.v1.EditCommentRequest
+	41, // 38: service.v1.CommentService.DeleteComment:input_type -> service.v1.DeleteCommentRequest
+	43, // 39: service.v1.CommentService.WatchComments:input_type -> service.v1.WatchCommentsRequest
+	2,  // 40: service.v1.AuthService.Login:output_type -> service.v1.LoginResponse
+	4,  // 41: service.v1.AuthService.RefreshToken:output_type -> service.v1.RefreshTokenResponse
+	6,  // 42: service.v1.AuthService.Logout:output_type -> service.v1.LogoutResponse
+	8,  // 43: service.v1.AuthService.CheckSession:output_type -> service.v1.CheckSessionResponse
+	11, // 44: service.v1.AuthService.GetJWKS:output_type -> service.v1.GetJWKSResponse
+	13, // 45: service.v1.UserService.CreateUser:output_type -> service.v1.CreateUserResponse
+	16, // 46: service.v1.UserService.GetUsers:output_type -> service.v1.GetUsersResponse
+	19, // 47: service.v1.PostService.CreatePost:output_type -> service.v1.CreatePostResponse
+	23, // 48: service.v1.PostService.GetPosts:output_type -> service.v1.GetPostsResponse
+	21, // 49: service.v1.PostService.GetPost:output_type -> service.v1.GetPostResponse
+	25, // 50: service.v1.PostService.UpdatePost:output_type -> service.v1.UpdatePostResponse
+	27, // 51: service.v1.PostService.DeletePost:output_type -> service.v1.DeletePostResponse
+	29, // 52: service.v1.PostService.WatchPosts:output_type -> service.v1.WatchPostsResponse
+	32, // 53: service.v1.CommentService.CreateComment:output_type -> service.v1.CreateCommentResponse
+	34, // 54: service.v1.CommentService.GetComments:output_type -> service.v1.GetCommentsResponse
+	36, // 55: service.v1.CommentService.GetCommentsSince:output_type -> service.v1.GetCommentsSinceResponse
+	38, // 56: service.v1.CommentService.GetCommentsByIDs:output_type -> service.v1.GetCommentsByIDsResponse
+	40, // 57: service.v1.CommentService.EditComment:output_type -> service.v1.EditCommentResponse
+	42, // 58: service.v1.CommentService.DeleteComment:output_type -> service.v1.DeleteCommentResponse
+	44, // 59: service.v1.CommentService.WatchComments:output_type -> service.v1.WatchCommentsResponse
+	40, // [40:60] is the sub-list for method output_type
+	20, // [20:40] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_service_v1_service_proto_init() }
@@ -1363,18 +2795,20 @@ func file_service_v1_service_proto_init() {
 	if File_service_v1_service_proto != nil {
 		return
 	}
+	file_service_v1_service_proto_msgTypes[23].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_service_v1_service_proto_rawDesc), len(file_service_v1_service_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   21,
+			NumEnums:      1,
+			NumMessages:   44,
 			NumExtensions: 0,
 			NumServices:   4,
 		},
 		GoTypes:           file_service_v1_service_proto_goTypes,
 		DependencyIndexes: file_service_v1_service_proto_depIdxs,
+		EnumInfos:         file_service_v1_service_proto_enumTypes,
 		MessageInfos:      file_service_v1_service_proto_msgTypes,
 	}.Build()
 	File_service_v1_service_proto = out.File
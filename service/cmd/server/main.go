@@ -2,10 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"github.com/Parnishkaspb/ozon_posts/internal/auth"
 	grpchandlers "github.com/Parnishkaspb/ozon_posts/internal/transport/grpc"
-	"log"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
@@ -14,50 +18,152 @@ import (
 
 	"github.com/Parnishkaspb/ozon_posts/internal/app"
 	"github.com/Parnishkaspb/ozon_posts/internal/config"
+	healthcheck "github.com/Parnishkaspb/ozon_posts/internal/health"
+	"github.com/Parnishkaspb/ozon_posts/internal/metrics"
+	"github.com/Parnishkaspb/ozon_posts/internal/tracing"
+	"github.com/Parnishkaspb/ozon_posts_pkg/logging"
+	"github.com/Parnishkaspb/ozon_posts_pkg/tlsconfig"
 	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
 func main() {
-	cfg := config.MustLoad("config/config.yaml")
+	cfg, args, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fatal("invalid config", "error", err)
+	}
+
+	logger, err := logging.New(os.Stderr, cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		fatal("invalid config", "error", err)
+	}
+	slog.SetDefault(logger)
+
+	if len(args) > 0 && args[0] == "migrate" {
+		os.Exit(runMigrate(cfg, args[1:]))
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		ServiceName: "ozon-posts-service",
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		fatal("tracing", "error", err)
+	}
 
 	port := strconv.Itoa(cfg.GRPC.Port)
 	lis, err := net.Listen("tcp", ":"+port)
 	if err != nil {
-		log.Fatalf("grpc listen :%s error: %v", port, err)
+		fatal("grpc listen", "port", port, "error", err)
 	}
 
-	jwtService := auth.New(cfg.JWT.Secret, cfg.JWT.TTL)
+	jwtService, err := newTokenService(cfg.JWT)
+	if err != nil {
+		fatal("jwt keys", "error", err)
+	}
 
 	a, err := app.New(context.Background(), cfg, jwtService)
 	if err != nil {
-		log.Fatal(err)
+		fatal("init app", "error", err)
 	}
 	defer a.Close()
 
 	h := grpchandlers.New(a)
-	grpcServer := grpc.NewServer()
+	opts := []grpc.ServerOption{
+		// спаны RPC, кроме проверок здоровья; trace context берётся из metadata
+		grpc.StatsHandler(otelgrpc.NewServerHandler(
+			otelgrpc.WithFilter(filters.Not(filters.ServiceName(healthpb.Health_ServiceDesc.ServiceName))),
+		)),
+		grpc.ChainUnaryInterceptor(
+			grpchandlers.LoggingInterceptor(),
+			metrics.UnaryServerInterceptor(),
+			grpchandlers.AuthInterceptor(a.JWT, a.Auth, grpchandlers.PublicMethods),
+		),
+		grpc.ChainStreamInterceptor(
+			grpchandlers.LoggingStreamInterceptor(),
+			metrics.StreamServerInterceptor(),
+			grpchandlers.AuthStreamInterceptor(a.JWT, a.Auth, grpchandlers.PublicMethods),
+		),
+	}
+	if cfg.GRPC.TLS.Enabled() {
+		creds, err := serverCredentials(cfg.GRPC.TLS)
+		if err != nil {
+			fatal("grpc tls", "error", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	} else {
+		slog.Warn("grpc: TLS is disabled, serving plaintext")
+	}
+	grpcServer := grpc.NewServer(opts...)
 	servicepb.RegisterAuthServiceServer(grpcServer, h)
 	servicepb.RegisterUserServiceServer(grpcServer, h)
 	servicepb.RegisterPostServiceServer(grpcServer, h)
 	servicepb.RegisterCommentServiceServer(grpcServer, h)
 
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
 	reflection.Register(grpcServer)
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	monitor := healthcheck.NewMonitor(healthServer, a, cfg.GRPC.HealthCheckInterval,
+		servicepb.AuthService_ServiceDesc.ServiceName,
+		servicepb.UserService_ServiceDesc.ServiceName,
+		servicepb.PostService_ServiceDesc.ServiceName,
+		servicepb.CommentService_ServiceDesc.ServiceName,
+	)
+	go monitor.Run(ctx)
+
+	var metricsServer *http.Server
+	if cfg.Metrics.Addr != "" {
+		if a.Pool != nil {
+			if err := metrics.RegisterPool(a.Pool); err != nil {
+				fatal("register pool metrics", "error", err)
+			}
+		}
+		metricsServer = &http.Server{Addr: cfg.Metrics.Addr, Handler: metrics.Handler()}
+		go func() {
+			slog.Info("metrics listening", "addr", cfg.Metrics.Addr)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				fatal("metrics serve", "error", err)
+			}
+		}()
+	}
+
 	go func() {
-		log.Printf("gRPC server listening on :%s", port)
+		slog.Info("gRPC server listening", "port", port)
 		if err := grpcServer.Serve(lis); err != nil {
-			log.Fatalf("grpc serve error: %v", err)
+			fatal("grpc serve", "error", err)
 		}
 	}()
 
 	<-ctx.Done()
-	log.Println("shutdown: stopping gRPC server...")
+
+	// сначала NOT_SERVING, чтобы балансировщик успел увести трафик, и только потом GracefulStop
+	healthServer.Shutdown()
+	if cfg.GRPC.DrainDelay > 0 {
+		slog.Info("shutdown: health is NOT_SERVING, draining", "delay", cfg.GRPC.DrainDelay)
+		time.Sleep(cfg.GRPC.DrainDelay)
+	}
+	// потоки WatchComments живут, пока клиент не отключится; закрываем их сами,
+	// иначе GracefulStop при любом подписчике ждёт до таймаута
+	a.Events.Close()
+	slog.Info("shutdown: stopping gRPC server")
 
 	stopped := make(chan struct{})
 	go func() {
@@ -67,10 +173,75 @@ func main() {
 
 	select {
 	case <-stopped:
-		log.Println("shutdown: gRPC stopped gracefully")
+		slog.Info("shutdown: gRPC stopped gracefully")
 	case <-time.After(10 * time.Second):
-		log.Println("shutdown: timeout -> force stop")
+		slog.Warn("shutdown: timeout, forcing stop")
 		grpcServer.Stop()
 	}
+	// метрики отдаём до конца, чтобы последний scrape увидел остановку
+	if metricsServer != nil {
+		_ = metricsServer.Close()
+	}
+
+	tracingCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(tracingCtx); err != nil {
+		slog.Warn("shutdown: flush spans", "error", err)
+	}
 	_ = servicepb.File_service_v1_service_proto
 }
+
+// fatal пишет ошибку и завершает процесс, как log.Fatal.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+func serverCredentials(cfg config.TLS) (credentials.TransportCredentials, error) {
+	reloader, err := tlsconfig.NewReloader(tlsconfig.Files{
+		CertFile: cfg.CertFile,
+		KeyFile:  cfg.KeyFile,
+		CAFile:   cfg.ClientCAFile,
+	}, cfg.ReloadInterval)
+	if err != nil {
+		return nil, fmt.Errorf("grpc tls: %w", err)
+	}
+	if cfg.ClientCAFile != "" {
+		slog.Info("grpc: mTLS enabled, client certificates are required")
+	}
+	return credentials.NewTLS(reloader.ServerConfig()), nil
+}
+
+func newTokenService(cfg config.Token) (*auth.Token, error) {
+	if len(cfg.Keys) == 0 {
+		// config.Validate пускает сюда только memory или явный jwt.ephemeral_key
+		slog.Warn("jwt: no signing keys configured, generating ephemeral key; tokens will not survive restart")
+		key, err := auth.GenerateSigningKey("ephemeral")
+		if err != nil {
+			return nil, err
+		}
+		return auth.New(cfg.TTL, key), nil
+	}
+
+	var (
+		active  auth.SigningKey
+		found   bool
+		retired []auth.SigningKey
+	)
+	for _, k := range cfg.Keys {
+		key, err := auth.LoadSigningKey(k.ID, k.PrivateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		if k.ID == cfg.ActiveKey {
+			active, found = key, true
+			continue
+		}
+		retired = append(retired, key)
+	}
+	if !found {
+		return nil, fmt.Errorf("jwt: active_key %q not found in keys", cfg.ActiveKey)
+	}
+
+	return auth.New(cfg.TTL, active, retired...), nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Parnishkaspb/ozon_posts/internal/app"
+	"github.com/Parnishkaspb/ozon_posts/internal/config"
+	"github.com/Parnishkaspb/ozon_posts/internal/database/postgresql"
+)
+
+const migrateUsage = `usage: server migrate <command>
+
+commands:
+  up            применить все новые миграции
+  down          откатить последнюю миграцию
+  status        показать применённые и ожидающие миграции
+  to VERSION    привести схему к VERSION (0 — откатить всё)`
+
+// runMigrate выполняет `server migrate ...` и возвращает код выхода.
+func runMigrate(cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	ctx := context.Background()
+	pool, err := postgresql.Connect(ctx, cfg.PostgresDSN(), app.PostgresOptions(cfg.PostgreSQL))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer pool.Close()
+
+	migrator, err := postgresql.NewMigrator(pool)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up(ctx)
+	case "down":
+		err = migrator.Down(ctx)
+	case "to":
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+		version, perr := strconv.ParseInt(args[1], 10, 64)
+		if perr != nil {
+			fmt.Fprintf(os.Stderr, "bad version %q\n", args[1])
+			return 2
+		}
+		err = migrator.To(ctx, version)
+	case "status":
+		err = printStatus(ctx, migrator)
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if args[0] != "status" {
+		version, err := migrator.Version(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("schema version: %d\n", version)
+	}
+	return 0
+}
+
+func printStatus(ctx context.Context, migrator *postgresql.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	version, err := migrator.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, st := range statuses {
+		applied := "pending"
+		if st.AppliedAt != nil {
+			applied = st.AppliedAt.Local().Format(time.DateTime)
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", st.Version, st.Name, applied)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("schema version: %d, latest known: %d\n", version, migrator.Latest())
+	if version > migrator.Latest() {
+		return postgresql.ErrSchemaTooNew
+	}
+	return nil
+}
@@ -7,13 +7,16 @@ import (
 	"github.com/Parnishkaspb/ozon_posts/internal/auth"
 	"github.com/Parnishkaspb/ozon_posts/internal/config"
 	"github.com/Parnishkaspb/ozon_posts/internal/database/postgresql"
+	"github.com/Parnishkaspb/ozon_posts/internal/events"
 	commentrepo "github.com/Parnishkaspb/ozon_posts/internal/repositories/comments"
 	"github.com/Parnishkaspb/ozon_posts/internal/repositories/memory"
 	postrepo "github.com/Parnishkaspb/ozon_posts/internal/repositories/posts"
+	sessionrepo "github.com/Parnishkaspb/ozon_posts/internal/repositories/sessions"
 	userrepo "github.com/Parnishkaspb/ozon_posts/internal/repositories/users"
 	commentsrv "github.com/Parnishkaspb/ozon_posts/internal/services/comments"
 	postsrv "github.com/Parnishkaspb/ozon_posts/internal/services/posts"
 	usersrv "github.com/Parnishkaspb/ozon_posts/internal/services/users"
+	"github.com/Parnishkaspb/ozon_posts/internal/tracing"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -23,6 +26,10 @@ type App struct {
 	CommentSRV *commentsrv.CommentService
 	UserSRV    *usersrv.UserService
 	Auth       *auth.Auth
+	JWT        *auth.Token
+	Events     *events.Bus
+
+	stop context.CancelFunc
 }
 
 var ErrUnknownStorageDriver = errors.New("unknown storage driver")
@@ -43,11 +50,18 @@ func New(ctx context.Context, cfg *config.Config, jwtService *auth.Token) (*App,
 		userRepo    userRepository
 		postRepo    postsrv.PostRepo
 		commentRepo commentsrv.CommentRepo
+		sessionRepo auth.SessionRepo
+		publisher   events.Publisher
+		listener    *events.Listener
 	)
 
+	bus := events.NewBus()
+
 	switch driver {
 	case "postgres":
-		db, err := postgresql.New(ctx, cfg.PostgresDSN())
+		opts := PostgresOptions(cfg.PostgreSQL)
+		opts.Tracer = tracing.NewQueryTracer()
+		db, err := postgresql.New(ctx, cfg.PostgresDSN(), opts)
 		if err != nil {
 			return nil, fmt.Errorf("postgresql.New: %w", err)
 		}
@@ -55,19 +69,33 @@ func New(ctx context.Context, cfg *config.Config, jwtService *auth.Token) (*App,
 		userRepo = userrepo.New(pool)
 		postRepo = postrepo.New(pool)
 		commentRepo = commentrepo.New(pool)
+		sessionRepo = sessionrepo.New(pool)
+		publisher = events.NewPostgres(pool)
+		// через NOTIFY шину наполняют и события с других реплик
+		listener = events.NewListener(pool, commentRepo, postRepo, bus)
 	case "memory":
 		store := memory.NewStore()
 		userRepo = memory.NewUserRepo(store)
 		postRepo = memory.NewPostRepo(store)
 		commentRepo = memory.NewCommentRepo(store)
+		sessionRepo = memory.NewSessionRepo(store)
+		publisher = bus
 	default:
 		return nil, fmt.Errorf("%w: %s", ErrUnknownStorageDriver, driver)
 	}
 
-	authService := auth.NewAuth(jwtService, userRepo)
-	postService := postsrv.New(postRepo)
+	authService := auth.NewAuth(jwtService, userRepo, sessionRepo, cfg.JWT.RefreshTTL)
+	postService := postsrv.New(postRepo, publisher)
 	userService := usersrv.NewUserService(userRepo)
-	commentService := commentsrv.New(commentRepo, postRepo)
+	commentService := commentsrv.New(commentRepo, postRepo, publisher)
+
+	runCtx, stop := context.WithCancel(context.Background())
+	if listener != nil {
+		go listener.Run(runCtx)
+	}
+	if pool != nil && cfg.PostgreSQL.StatsInterval > 0 {
+		go postgresql.LogStats(runCtx, pool, cfg.PostgreSQL.StatsInterval)
+	}
 
 	return &App{
 		Pool:       pool,
@@ -75,11 +103,41 @@ func New(ctx context.Context, cfg *config.Config, jwtService *auth.Token) (*App,
 		CommentSRV: commentService,
 		UserSRV:    userService,
 		Auth:       authService,
+		JWT:        jwtService,
+		Events:     bus,
+		stop:       stop,
 	}, nil
 }
 
+// PostgresOptions переводит секцию postgresql конфига в настройки пула.
+func PostgresOptions(c config.PostgreSQLConfig) postgresql.Options {
+	return postgresql.Options{
+		MaxConns:          c.Pool.MaxConns,
+		MinConns:          c.Pool.MinConns,
+		MaxConnLifetime:   c.Pool.MaxConnLifetime,
+		MaxConnIdleTime:   c.Pool.MaxConnIdleTime,
+		HealthCheckPeriod: c.Pool.HealthCheckPeriod,
+		StatementTimeout:  c.StatementTimeout,
+		ConnectTimeout:    c.Connect.Timeout,
+		ConnectAttempts:   c.Connect.Attempts,
+		ConnectBackoff:    c.Connect.InitialBackoff,
+		ConnectMaxBackoff: c.Connect.MaxBackoff,
+	}
+}
+
 func (a *App) Close() {
+	if a.stop != nil {
+		a.stop()
+	}
 	if a.Pool != nil {
 		a.Pool.Close()
 	}
 }
+
+// Ping проверяет доступность хранилища; у memory-хранилища проверять нечего.
+func (a *App) Ping(ctx context.Context) error {
+	if a.Pool == nil {
+		return nil
+	}
+	return a.Pool.Ping(ctx)
+}
@@ -21,7 +21,7 @@ func TestNew(t *testing.T) {
 			name: "memory driver",
 			cfg: &config.Config{
 				Storage: config.StorageConfig{Driver: "memory"},
-				JWT:     config.Token{Secret: "secret", TTL: time.Minute},
+				JWT:     config.Token{TTL: time.Minute},
 			},
 			wantPoolNil: true,
 		},
@@ -32,9 +32,14 @@ func TestNew(t *testing.T) {
 		},
 	}
 
+	key, err := auth.GenerateSigningKey("test")
+	if err != nil {
+		t.Fatalf("generate signing key: %v", err)
+	}
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a, err := New(context.Background(), tt.cfg, auth.New("secret", time.Minute))
+			a, err := New(context.Background(), tt.cfg, auth.New(time.Minute, key))
 			if tt.wantErr != nil {
 				if !errors.Is(err, tt.wantErr) {
 					t.Fatalf("expected %v, got %v", tt.wantErr, err)
@@ -44,7 +49,7 @@ func TestNew(t *testing.T) {
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
-			if a == nil || a.PostSRV == nil || a.CommentSRV == nil || a.UserSRV == nil || a.Auth == nil {
+			if a == nil || a.PostSRV == nil || a.CommentSRV == nil || a.UserSRV == nil || a.Auth == nil || a.JWT == nil {
 				t.Fatalf("app not initialized")
 			}
 			if (a.Pool == nil) != tt.wantPoolNil {
@@ -3,8 +3,10 @@ package auth
 import (
 	"context"
 	"errors"
+	"time"
+
 	"github.com/Parnishkaspb/ozon_posts/internal/models"
-	"github.com/Parnishkaspb/ozon_posts/internal/repositories/users"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
 	"github.com/google/uuid"
 )
 
@@ -18,34 +20,45 @@ var (
 )
 
 type JWT interface {
-	GenerateToken(userID uuid.UUID, login, name, surname string) (string, error)
+	GenerateToken(userID, sessionID uuid.UUID, login, name, surname string) (string, error)
 }
 
 type UserRepo interface {
-	GetUserByLoginPassword(ctx context.Context, login, password string) (*models.User, error)
+	GetUserByLogin(ctx context.Context, login string) (*models.User, error)
+	GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error)
 }
 
 type Auth struct {
-	jwtService JWT
-	userRepo   UserRepo
+	jwtService  JWT
+	userRepo    UserRepo
+	sessionRepo SessionRepo
+	refreshTTL  time.Duration
 }
 
-func NewAuth(jwt JWT, userRepo UserRepo) *Auth {
-	return &Auth{jwtService: jwt, userRepo: userRepo}
+func NewAuth(jwt JWT, userRepo UserRepo, sessionRepo SessionRepo, refreshTTL time.Duration) *Auth {
+	if refreshTTL <= 0 {
+		refreshTTL = DefaultRefreshTTL
+	}
+	return &Auth{jwtService: jwt, userRepo: userRepo, sessionRepo: sessionRepo, refreshTTL: refreshTTL}
 }
 
-func (a *Auth) Authenticate(ctx context.Context, login, password string) (string, error) {
+func (a *Auth) Authenticate(ctx context.Context, login, password string) (*Tokens, error) {
 	if login == "" || password == "" {
-		return "", ErrEmpty
+		return nil, ErrEmpty
 	}
 
-	user, err := a.userRepo.GetUserByLoginPassword(ctx, login, password)
+	user, err := a.userRepo.GetUserByLogin(ctx, login)
 	if err != nil {
-		if errors.Is(err, users.ErrUserNotFound) {
-			return "", ErrIncorrect
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			CheckPassword(dummyHash, password)
+			return nil, ErrIncorrect
 		}
-		return "", err
+		return nil, err
+	}
+
+	if !CheckPassword(user.Password, password) {
+		return nil, ErrIncorrect
 	}
 
-	return a.jwtService.GenerateToken(user.ID, login, user.Name, user.Surname)
+	return a.startSession(ctx, user)
 }
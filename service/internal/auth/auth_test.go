@@ -3,24 +3,29 @@ package auth
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Parnishkaspb/ozon_posts/internal/models"
-	userrepo "github.com/Parnishkaspb/ozon_posts/internal/repositories/users"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
 	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type mockJWT struct {
-	token      string
-	err        error
-	gotUserID  uuid.UUID
-	gotLogin   string
-	gotName    string
-	gotSurname string
+	token        string
+	err          error
+	gotUserID    uuid.UUID
+	gotSessionID uuid.UUID
+	gotLogin     string
+	gotName      string
+	gotSurname   string
 }
 
-func (m *mockJWT) GenerateToken(userID uuid.UUID, login, name, surname string) (string, error) {
+func (m *mockJWT) GenerateToken(userID, sessionID uuid.UUID, login, name, surname string) (string, error) {
 	m.gotUserID = userID
+	m.gotSessionID = sessionID
 	m.gotLogin = login
 	m.gotName = name
 	m.gotSurname = surname
@@ -32,15 +37,72 @@ type mockUserRepo struct {
 	err  error
 }
 
-func (m *mockUserRepo) GetUserByLoginPassword(ctx context.Context, login, password string) (*models.User, error) {
+func (m *mockUserRepo) GetUserByLogin(ctx context.Context, login string) (*models.User, error) {
 	return m.user, m.err
 }
 
+func (m *mockUserRepo) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	return m.user, m.err
+}
+
+type mockSessionRepo struct {
+	sessions map[string]*models.Session
+	err      error
+}
+
+func newMockSessionRepo() *mockSessionRepo {
+	return &mockSessionRepo{sessions: make(map[string]*models.Session)}
+}
+
+func (m *mockSessionRepo) CreateSession(ctx context.Context, userID uuid.UUID, refreshHash string, expiresAt time.Time) (*models.Session, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	s := &models.Session{ID: uuid.New(), UserID: userID, RefreshHash: refreshHash, ExpiresAt: expiresAt}
+	m.sessions[refreshHash] = s
+	return s, nil
+}
+
+func (m *mockSessionRepo) GetSessionByID(ctx context.Context, sessionID uuid.UUID) (*models.Session, error) {
+	for _, s := range m.sessions {
+		if s.ID == sessionID {
+			return s, nil
+		}
+	}
+	return nil, repositories.ErrSessionNotFound
+}
+
+func (m *mockSessionRepo) RotateSession(ctx context.Context, oldHash, newHash string, expiresAt time.Time) (*models.Session, error) {
+	s, ok := m.sessions[oldHash]
+	if !ok || s.RevokedAt != nil || !s.ExpiresAt.After(time.Now()) {
+		return nil, repositories.ErrSessionNotFound
+	}
+	delete(m.sessions, oldHash)
+	s.RefreshHash = newHash
+	s.ExpiresAt = expiresAt
+	m.sessions[newHash] = s
+	return s, nil
+}
+
+func (m *mockSessionRepo) RevokeSession(ctx context.Context, refreshHash string) (*models.Session, error) {
+	s, ok := m.sessions[refreshHash]
+	if !ok {
+		return nil, repositories.ErrSessionNotFound
+	}
+	now := time.Now()
+	s.RevokedAt = &now
+	return s, nil
+}
+
 func TestAuth_Authenticate(t *testing.T) {
 	ctx := context.Background()
 	repoErr := errors.New("db down")
 	jwtErr := errors.New("sign failed")
-	u := &models.User{ID: uuid.New(), Name: "Ivan", Surname: "Grozniy"}
+	hash, err := HashPassword("password")
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	u := &models.User{ID: uuid.New(), Login: "login", Name: "Ivan", Surname: "Grozniy", Password: hash}
 
 	tests := []struct {
 		name        string
@@ -62,7 +124,15 @@ func TestAuth_Authenticate(t *testing.T) {
 			name:     "user not found mapped to incorrect",
 			login:    "login",
 			password: "password",
-			repo:     &mockUserRepo{err: userrepo.ErrUserNotFound},
+			repo:     &mockUserRepo{err: repositories.ErrUserNotFound},
+			jwt:      &mockJWT{},
+			wantErr:  ErrIncorrect,
+		},
+		{
+			name:     "wrong password mapped to incorrect",
+			login:    "login",
+			password: "wrong-password",
+			repo:     &mockUserRepo{user: u},
 			jwt:      &mockJWT{},
 			wantErr:  ErrIncorrect,
 		},
@@ -96,7 +166,7 @@ func TestAuth_Authenticate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a := NewAuth(tt.jwt, tt.repo)
+			a := NewAuth(tt.jwt, tt.repo, newMockSessionRepo(), time.Hour)
 			tok, err := a.Authenticate(ctx, tt.login, tt.password)
 
 			if tt.wantErr != nil {
@@ -108,14 +178,116 @@ func TestAuth_Authenticate(t *testing.T) {
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
-			if tok != tt.wantToken {
-				t.Fatalf("expected token %q, got %q", tt.wantToken, tok)
+			if tok.Access != tt.wantToken || tok.Refresh == "" {
+				t.Fatalf("expected token %q with refresh token, got %+v", tt.wantToken, tok)
 			}
 			if tt.checkJWTArg {
-				if tt.jwt.gotUserID != u.ID || tt.jwt.gotLogin != tt.login || tt.jwt.gotName != u.Name || tt.jwt.gotSurname != u.Surname {
+				if tt.jwt.gotUserID != u.ID || tt.jwt.gotSessionID == uuid.Nil || tt.jwt.gotLogin != tt.login || tt.jwt.gotName != u.Name || tt.jwt.gotSurname != u.Surname {
 					t.Fatalf("unexpected jwt args")
 				}
 			}
 		})
 	}
 }
+
+func TestAuth_RefreshAndLogout(t *testing.T) {
+	ctx := context.Background()
+	hash, err := HashPassword("password")
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	u := &models.User{ID: uuid.New(), Login: "login", Name: "Ivan", Surname: "Grozniy", Password: hash}
+	jwt := &mockJWT{token: "token"}
+	a := NewAuth(jwt, &mockUserRepo{user: u}, newMockSessionRepo(), time.Hour)
+
+	first, err := a.Authenticate(ctx, "login", "password")
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	sessionID := jwt.gotSessionID
+
+	second, err := a.Refresh(ctx, first.Refresh)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if second.Refresh == first.Refresh || jwt.gotSessionID != sessionID {
+		t.Fatalf("refresh must rotate token within the same session")
+	}
+	if _, err := a.Refresh(ctx, first.Refresh); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("reused refresh token: expected %v, got %v", ErrInvalidRefreshToken, err)
+	}
+
+	if active, err := a.SessionActive(ctx, sessionID); err != nil || !active {
+		t.Fatalf("expected active session, got %v (%v)", active, err)
+	}
+	if err := a.Logout(ctx, second.Refresh); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+	if active, err := a.SessionActive(ctx, sessionID); err != nil || active {
+		t.Fatalf("expected revoked session, got %v (%v)", active, err)
+	}
+	if _, err := a.Refresh(ctx, second.Refresh); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("refresh after logout: expected %v, got %v", ErrInvalidRefreshToken, err)
+	}
+	if err := a.Logout(ctx, "unknown"); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("logout unknown: expected %v, got %v", ErrInvalidRefreshToken, err)
+	}
+}
+
+func TestAuth_RefreshDeletedUser(t *testing.T) {
+	ctx := context.Background()
+	hash, err := HashPassword("password")
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	users := &mockUserRepo{user: &models.User{ID: uuid.New(), Login: "login", Password: hash}}
+	a := NewAuth(&mockJWT{token: "token"}, users, newMockSessionRepo(), time.Hour)
+
+	tokens, err := a.Authenticate(ctx, "login", "password")
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+
+	users.user, users.err = nil, repositories.ErrUserNotFound
+	if _, err := a.Refresh(ctx, tokens.Refresh); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("refresh for deleted user: expected %v, got %v", ErrInvalidRefreshToken, err)
+	}
+}
+
+func TestValidatePassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		login    string
+		password string
+		wantErr  error
+	}{
+		{name: "too short", login: "Ivan", password: "Moscow", wantErr: ErrPasswordTooShort},
+		{name: "too long", login: "Ivan", password: strings.Repeat("я", 37), wantErr: ErrPasswordTooLong},
+		{name: "surrounding spaces", login: "Ivan", password: " MoscowNeverSleep", wantErr: ErrPasswordBlank},
+		{name: "same as login", login: "IvanGrozny", password: "ivangrozny", wantErr: ErrPasswordIsLogin},
+		{name: "ok", login: "Ivan", password: "MoscowNeverSleep"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePassword(tt.login, tt.password)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDummyHash(t *testing.T) {
+	// неизвестный логин должен стоить столько же, сколько неверный пароль
+	cost, err := bcrypt.Cost([]byte(dummyHash))
+	if err != nil {
+		t.Fatalf("dummy hash is not a bcrypt hash: %v", err)
+	}
+	if cost != bcrypt.DefaultCost {
+		t.Fatalf("dummy hash cost = %d, want %d as in HashPassword", cost, bcrypt.DefaultCost)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(dummyHash), []byte("password")); !errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		t.Fatalf("compare with dummy hash: %v", err)
+	}
+}
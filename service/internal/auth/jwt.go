@@ -1,57 +1,94 @@
 package auth
 
 import (
+	"errors"
+	"fmt"
 	"github.com/Parnishkaspb/ozon_posts/internal/models"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"time"
 )
 
+var ErrUnknownKeyID = errors.New("unknown signing key id")
+
+// Token подписывает access-токены активным ключом. Остальные ключи
+// продолжают публиковаться в JWKS и принимаются при проверке,
+// пока не истекут выписанные ими токены.
 type Token struct {
-	Secret string
 	TTL    time.Duration
+	active SigningKey
+	keys   map[string]SigningKey
 }
 
 type Claims struct {
-	UserID  uuid.UUID `json:"user_id"`
-	Login   string    `json:"login"`
-	Name    string    `json:"name"`
-	Surname string    `json:"surname"`
+	UserID    uuid.UUID `json:"user_id"`
+	SessionID uuid.UUID `json:"sid"`
+	Login     string    `json:"login"`
+	Name      string    `json:"name"`
+	Surname   string    `json:"surname"`
 	jwt.RegisteredClaims
 }
 
-func New(secret string, ttl time.Duration) *Token {
+func New(ttl time.Duration, active SigningKey, retired ...SigningKey) *Token {
+	keys := make(map[string]SigningKey, len(retired)+1)
+	for _, k := range retired {
+		keys[k.ID] = k
+	}
+	keys[active.ID] = active
+
 	return &Token{
-		Secret: secret,
 		TTL:    ttl,
+		active: active,
+		keys:   keys,
 	}
 }
 
-func (t *Token) GenerateToken(userID uuid.UUID, login, name, surname string) (string, error) {
+func (t *Token) GenerateToken(userID, sessionID uuid.UUID, login, name, surname string) (string, error) {
 	claims := Claims{
-		UserID:  userID,
-		Login:   login,
-		Name:    name,
-		Surname: surname,
+		UserID:    userID,
+		SessionID: sessionID,
+		Login:     login,
+		Name:      name,
+		Surname:   surname,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(t.TTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(t.Secret))
+	token := jwt.NewWithClaims(t.active.method, claims)
+	token.Header["kid"] = t.active.ID
+	return token.SignedString(t.active.private)
 }
 
 func (t *Token) ParseToken(tokenString string) (*models.User, error) {
+	claims, err := t.ParseClaims(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.User{
+		ID:      claims.UserID,
+		Login:   claims.Login,
+		Name:    claims.Name,
+		Surname: claims.Surname,
+	}, nil
+}
+
+func (t *Token) ParseClaims(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(
 		tokenString,
 		&Claims{},
 		func(token *jwt.Token) (interface{}, error) {
-			if token.Method != jwt.SigningMethodHS256 {
+			kid, _ := token.Header["kid"].(string)
+			key, ok := t.keys[kid]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q", ErrUnknownKeyID, kid)
+			}
+			if token.Method != key.method {
 				return nil, jwt.ErrTokenSignatureInvalid
 			}
-			return []byte(t.Secret), nil
+			return key.private.Public(), nil
 		},
 	)
 	if err != nil {
@@ -63,10 +100,17 @@ func (t *Token) ParseToken(tokenString string) (*models.User, error) {
 		return nil, jwt.ErrTokenInvalidClaims
 	}
 
-	return &models.User{
-		ID:      claims.UserID,
-		Login:   claims.Login,
-		Name:    claims.Name,
-		Surname: claims.Surname,
-	}, nil
+	return claims, nil
+}
+
+// JWKS возвращает публичные части всех ключей, начиная с активного.
+func (t *Token) JWKS() []JWK {
+	out := make([]JWK, 0, len(t.keys))
+	out = append(out, t.active.JWK())
+	for id, k := range t.keys {
+		if id != t.active.ID {
+			out = append(out, k.JWK())
+		}
+	}
+	return out
 }
@@ -1,78 +1,171 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+func mustSigningKey(t *testing.T, id string) SigningKey {
+	t.Helper()
+	key, err := GenerateSigningKey(id)
+	if err != nil {
+		t.Fatalf("generate signing key: %v", err)
+	}
+	return key
+}
+
+func mustRSAKey(t *testing.T, id string) SigningKey {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	key, err := NewSigningKey(id, private)
+	if err != nil {
+		t.Fatalf("new signing key: %v", err)
+	}
+	return key
+}
+
 func TestToken_GenerateAndParse(t *testing.T) {
 	tests := []struct {
 		name    string
-		secret  string
-		login   string
-		nameVal string
-		surname string
+		key     SigningKey
+		wantAlg string
+		wantKty string
 	}{
-		{
-			name:    "ascii payload",
-			secret:  "secret",
-			login:   "Ivan",
-			nameVal: "Иван",
-			surname: "Грозный",
-		},
-		{
-			name:    "unicode payload",
-			secret:  "secret",
-			login:   "Ivan",
-			nameVal: "Иван",
-			surname: "Грозный",
-		},
+		{name: "ed25519", key: mustSigningKey(t, "ed"), wantAlg: "EdDSA", wantKty: "OKP"},
+		{name: "rsa", key: mustRSAKey(t, "rsa"), wantAlg: "RS256", wantKty: "RSA"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			svc := New(tt.secret, time.Minute)
+			svc := New(time.Minute, tt.key)
 			userID := uuid.New()
+			sessionID := uuid.New()
 
-			token, err := svc.GenerateToken(userID, tt.login, tt.nameVal, tt.surname)
+			token, err := svc.GenerateToken(userID, sessionID, "Ivan", "Иван", "Грозный")
 			if err != nil {
 				t.Fatalf("generate token: %v", err)
 			}
 
-			u, err := svc.ParseToken(token)
+			claims, err := svc.ParseClaims(token)
 			if err != nil {
 				t.Fatalf("parse token: %v", err)
 			}
-			if u.ID != userID || u.Login != tt.login || u.Name != tt.nameVal || u.Surname != tt.surname {
-				t.Fatalf("unexpected parsed user: %+v", u)
+			if claims.UserID != userID || claims.SessionID != sessionID || claims.Login != "Ivan" || claims.Name != "Иван" || claims.Surname != "Грозный" {
+				t.Fatalf("unexpected parsed claims: %+v", claims)
+			}
+
+			jwk := tt.key.JWK()
+			if jwk.KID != tt.key.ID || jwk.ALG != tt.wantAlg || jwk.KTY != tt.wantKty || jwk.Use != "sig" {
+				t.Fatalf("unexpected jwk: %+v", jwk)
 			}
 		})
 	}
 }
 
 func TestToken_ParseTokenErrors(t *testing.T) {
-	svc := New("secret", time.Minute)
-	good, err := svc.GenerateToken(uuid.New(), "Ivan", "Иван", "Грозный")
+	svc := New(time.Minute, mustSigningKey(t, "k1"))
+	good, err := svc.GenerateToken(uuid.New(), uuid.New(), "Ivan", "Иван", "Грозный")
 	if err != nil {
 		t.Fatalf("generate token: %v", err)
 	}
 
 	tests := []struct {
-		name  string
-		svc   *Token
-		token string
+		name    string
+		svc     *Token
+		token   string
+		wantErr error
 	}{
 		{name: "malformed token", svc: svc, token: "not-a-jwt"},
-		{name: "wrong secret", svc: New("another-secret", time.Minute), token: good},
+		{name: "unknown kid", svc: New(time.Minute, mustSigningKey(t, "k2")), token: good, wantErr: ErrUnknownKeyID},
+		{name: "same kid, other key", svc: New(time.Minute, mustSigningKey(t, "k1")), token: good},
+		{name: "same kid, other algorithm", svc: New(time.Minute, mustRSAKey(t, "k1")), token: good},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if _, err := tt.svc.ParseToken(tt.token); err == nil {
+			_, err := tt.svc.ParseToken(tt.token)
+			if err == nil {
 				t.Fatalf("expected parse error")
 			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestToken_KeyRotation(t *testing.T) {
+	oldKey := mustSigningKey(t, "2026-09")
+	newKey := mustSigningKey(t, "2026-10")
+
+	oldToken, err := New(time.Minute, oldKey).GenerateToken(uuid.New(), uuid.New(), "Ivan", "Иван", "Грозный")
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+
+	rotated := New(time.Minute, newKey, oldKey)
+	if _, err := rotated.ParseToken(oldToken); err != nil {
+		t.Fatalf("token of retired key must still verify: %v", err)
+	}
+
+	jwks := rotated.JWKS()
+	if len(jwks) != 2 || jwks[0].KID != newKey.ID || jwks[1].KID != oldKey.ID {
+		t.Fatalf("unexpected jwks: %+v", jwks)
+	}
+
+	if _, err := New(time.Minute, newKey).ParseToken(oldToken); !errors.Is(err, ErrUnknownKeyID) {
+		t.Fatalf("expected %v after retiring key, got %v", ErrUnknownKeyID, err)
+	}
+}
+
+func TestLoadSigningKey(t *testing.T) {
+	dir := t.TempDir()
+	private := mustSigningKey(t, "tmp").private
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	good := filepath.Join(dir, "good.pem")
+	if err := os.WriteFile(good, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	garbage := filepath.Join(dir, "garbage.pem")
+	if err := os.WriteFile(garbage, []byte("not a key"), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "pkcs8 ed25519", path: good},
+		{name: "not pem", path: garbage, wantErr: true},
+		{name: "missing file", path: filepath.Join(dir, "missing.pem"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := LoadSigningKey("k1", tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wantErr=%v, got %v", tt.wantErr, err)
+			}
+			if err == nil && key.JWK().X == "" {
+				t.Fatalf("expected ed25519 public key in jwk")
+			}
 		})
 	}
 }
@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrUnsupportedKey = errors.New("unsupported signing key: expected PKCS#8 Ed25519 or RSA private key")
+
+// SigningKey — приватный ключ с идентификатором, который попадает в заголовок kid.
+type SigningKey struct {
+	ID      string
+	private crypto.Signer
+	method  jwt.SigningMethod
+}
+
+// JWK — публичная часть ключа в формате RFC 7517.
+type JWK struct {
+	KID string
+	KTY string
+	ALG string
+	Use string
+	CRV string
+	X   string
+	N   string
+	E   string
+}
+
+func NewSigningKey(id string, private crypto.Signer) (SigningKey, error) {
+	switch private.(type) {
+	case ed25519.PrivateKey:
+		return SigningKey{ID: id, private: private, method: jwt.SigningMethodEdDSA}, nil
+	case *rsa.PrivateKey:
+		return SigningKey{ID: id, private: private, method: jwt.SigningMethodRS256}, nil
+	default:
+		return SigningKey{}, ErrUnsupportedKey
+	}
+}
+
+// LoadSigningKey читает PEM-файл вида `openssl genpkey -algorithm ed25519`.
+func LoadSigningKey(id, path string) (SigningKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("read signing key %s: %w", id, err)
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return SigningKey{}, fmt.Errorf("signing key %s: no PEM block", id)
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("parse signing key %s: %w", id, err)
+	}
+
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return SigningKey{}, ErrUnsupportedKey
+	}
+	return NewSigningKey(id, signer)
+}
+
+// GenerateSigningKey создаёт эфемерный Ed25519-ключ, живущий до рестарта процесса.
+func GenerateSigningKey(id string) (SigningKey, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("generate signing key: %w", err)
+	}
+	return NewSigningKey(id, private)
+}
+
+func (k SigningKey) JWK() JWK {
+	jwk := JWK{KID: k.ID, ALG: k.method.Alg(), Use: "sig"}
+
+	switch pub := k.private.Public().(type) {
+	case ed25519.PublicKey:
+		jwk.KTY = "OKP"
+		jwk.CRV = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	case *rsa.PublicKey:
+		jwk.KTY = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	}
+
+	return jwk
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	minPasswordLength = 8
+	// bcrypt молча обрезает всё, что длиннее 72 байт
+	maxPasswordBytes = 72
+)
+
+var (
+	ErrPasswordTooShort = errors.New("password must be at least 8 characters")
+	ErrPasswordTooLong  = errors.New("password must be at most 72 bytes")
+	ErrPasswordIsLogin  = errors.New("password must not match login")
+	ErrPasswordBlank    = errors.New("password must not start or end with spaces")
+)
+
+// ValidatePassword проверяет пароль на соответствие политике регистрации.
+func ValidatePassword(login, password string) error {
+	if utf8.RuneCountInString(password) < minPasswordLength {
+		return ErrPasswordTooShort
+	}
+	if len(password) > maxPasswordBytes {
+		return ErrPasswordTooLong
+	}
+	if strings.TrimSpace(password) != password {
+		return ErrPasswordBlank
+	}
+	if strings.EqualFold(password, login) {
+		return ErrPasswordIsLogin
+	}
+	return nil
+}
+
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// dummyHash — bcrypt-хеш той же стоимости, что и у HashPassword: с ним
+// сверяется пароль, когда логина нет, чтобы время ответа не выдавало,
+// существует ли пользователь.
+const dummyHash = "$2a$10$SuFACik/Yy7F6FD/UXGYmOGR2YWuRzxvlBRHkhPnAyUQZevpj/ug6"
+
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// DefaultRefreshTTL используется, если jwt.refresh_ttl не задан в конфиге.
+const DefaultRefreshTTL = 30 * 24 * time.Hour
+
+var ErrInvalidRefreshToken = errors.New("refresh token is invalid, expired or revoked")
+
+type SessionRepo interface {
+	CreateSession(ctx context.Context, userID uuid.UUID, refreshHash string, expiresAt time.Time) (*models.Session, error)
+	GetSessionByID(ctx context.Context, sessionID uuid.UUID) (*models.Session, error)
+	RotateSession(ctx context.Context, oldHash, newHash string, expiresAt time.Time) (*models.Session, error)
+	RevokeSession(ctx context.Context, refreshHash string) (*models.Session, error)
+}
+
+// Tokens — пара токенов сессии: короткоживущий JWT и непрозрачный refresh-токен.
+type Tokens struct {
+	Access  string
+	Refresh string
+}
+
+// Refresh обменивает refresh-токен на новую пару; предъявленный токен
+// после этого становится недействительным.
+func (a *Auth) Refresh(ctx context.Context, refreshToken string) (*Tokens, error) {
+	if refreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	next, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := a.sessionRepo.RotateSession(ctx, hashRefreshToken(refreshToken), hashRefreshToken(next), time.Now().Add(a.refreshTTL))
+	if err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	user, err := a.userRepo.GetUserByID(ctx, s.UserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	access, err := a.jwtService.GenerateToken(user.ID, s.ID, user.Login, user.Name, user.Surname)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tokens{Access: access, Refresh: next}, nil
+}
+
+// Logout отзывает сессию; повторный вызов с тем же токеном не считается ошибкой.
+func (a *Auth) Logout(ctx context.Context, refreshToken string) error {
+	if refreshToken == "" {
+		return ErrInvalidRefreshToken
+	}
+
+	if _, err := a.sessionRepo.RevokeSession(ctx, hashRefreshToken(refreshToken)); err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			return ErrInvalidRefreshToken
+		}
+		return err
+	}
+	return nil
+}
+
+// SessionActive сообщает, можно ли ещё доверять access-токенам сессии.
+func (a *Auth) SessionActive(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	s, err := a.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return s.RevokedAt == nil && s.ExpiresAt.After(time.Now()), nil
+}
+
+func (a *Auth) startSession(ctx context.Context, user *models.User) (*Tokens, error) {
+	refresh, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := a.sessionRepo.CreateSession(ctx, user.ID, hashRefreshToken(refresh), time.Now().Add(a.refreshTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := a.jwtService.GenerateToken(user.ID, s.ID, user.Login, user.Name, user.Surname)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tokens{Access: access, Refresh: refresh}, nil
+}
+
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate refresh token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// в БД хранится только хэш: утечка таблицы sessions не даёт рабочих токенов
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,11 +1,14 @@
 package config
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"io"
 	"time"
 
-	"gopkg.in/yaml.v3"
+	"github.com/Parnishkaspb/ozon_posts/internal/tracing"
+	"github.com/Parnishkaspb/ozon_posts_pkg/configload"
+	"github.com/Parnishkaspb/ozon_posts_pkg/logging"
 )
 
 type Config struct {
@@ -13,52 +16,210 @@ type Config struct {
 	Storage    StorageConfig    `yaml:"storage"`
 	JWT        Token            `yaml:"jwt"`
 	GRPC       GRPC             `yaml:"grpc"`
+	Log        Log              `yaml:"log"`
+	Metrics    Metrics          `yaml:"metrics"`
+	Tracing    Tracing          `yaml:"tracing"`
+}
+
+// Tracing — экспорт спанов OpenTelemetry.
+type Tracing struct {
+	// Exporter: none (по умолчанию), stdout или otlp.
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER" env-default:"none"`
+	// Endpoint — host:port OTLP/gRPC коллектора.
+	Endpoint    string  `yaml:"endpoint" env:"TRACING_OTLP_ENDPOINT"`
+	Insecure    bool    `yaml:"insecure" env:"TRACING_OTLP_INSECURE"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+}
+
+// Metrics — HTTP-слушатель для /metrics Prometheus; пустой Addr отключает его.
+type Metrics struct {
+	Addr string `yaml:"addr" env:"METRICS_ADDR"`
+}
+
+type Log struct {
+	// Level: debug, info, warn или error.
+	Level string `yaml:"level" env:"LOG_LEVEL" env-default:"info"`
+	// Format: text или json.
+	Format string `yaml:"format" env:"LOG_FORMAT" env-default:"text"`
 }
 
 type GRPC struct {
-	Port int
+	Port int `yaml:"port" env:"GRPC_PORT" env-default:"9090"`
+	TLS  TLS `yaml:"tls"`
+	// HealthCheckInterval — как часто пинговать хранилище для grpc.health.v1.
+	HealthCheckInterval time.Duration `yaml:"health_check_interval" env:"GRPC_HEALTH_CHECK_INTERVAL" env-default:"5s"`
+	// DrainDelay — сколько держать NOT_SERVING перед GracefulStop, чтобы
+	// балансировщик успел вывести экземпляр.
+	DrainDelay time.Duration `yaml:"drain_delay" env:"GRPC_DRAIN_DELAY"`
 }
 
+// TLS слушателя: включается, если задан CertFile. С ClientCAFile сервер
+// требует клиентский сертификат (mTLS). Файлы перечитываются при изменении.
+type TLS struct {
+	CertFile       string        `yaml:"cert_file" env:"GRPC_TLS_CERT_FILE"`
+	KeyFile        string        `yaml:"key_file" env:"GRPC_TLS_KEY_FILE"`
+	ClientCAFile   string        `yaml:"client_ca_file" env:"GRPC_TLS_CLIENT_CA_FILE"`
+	ReloadInterval time.Duration `yaml:"reload_interval" env:"GRPC_TLS_RELOAD_INTERVAL" env-default:"10s"`
+}
+
+func (t TLS) Enabled() bool { return t.CertFile != "" }
+
 type Token struct {
-	Secret string        `yaml:"secret_key"`
-	TTL    time.Duration `yaml:"ttl"`
+	TTL        time.Duration `yaml:"ttl" env:"JWT_TTL" env-default:"10m"`
+	RefreshTTL time.Duration `yaml:"refresh_ttl" env:"JWT_REFRESH_TTL" env-default:"720h"`
+	// ActiveKey — id ключа, которым подписываются новые токены;
+	// остальные ключи из Keys только проверяют уже выданные.
+	ActiveKey string       `yaml:"active_key" env:"JWT_ACTIVE_KEY"`
+	Keys      []SigningKey `yaml:"keys"`
+	// EphemeralKey разрешает при пустом Keys подписывать токены ключом,
+	// сгенерированным при старте. Только для локальной разработки: у каждой
+	// реплики свой ключ, а рестарт обнуляет все выданные токены.
+	EphemeralKey bool `yaml:"ephemeral_key" env:"JWT_EPHEMERAL_KEY"`
+}
+
+type SigningKey struct {
+	ID             string `yaml:"id"`
+	PrivateKeyPath string `yaml:"private_key_path"`
 }
 
 type StorageConfig struct {
-	Driver string `yaml:"driver"`
+	Driver string `yaml:"driver" env:"STORAGE_DRIVER" env-default:"postgres"`
 }
 
 type PostgreSQLConfig struct {
-	User     string `yaml:"user" env-required:"true"`
-	Password string `yaml:"password" env-required:"true"`
-	Host     string `yaml:"host" env-required:"true"`
-	Port     int    `yaml:"port" env-required:"true"`
-	DB       string `yaml:"db"   env-required:"true"`
-	SSLMode  string `yaml:"sslmode"  env-default:"disable"`
+	// URL — готовый DSN; если задан, отдельные поля подключения не нужны.
+	URL      string `yaml:"url" env:"DATABASE_URL"`
+	User     string `yaml:"user" env:"POSTGRES_USER" env-required:"true"`
+	Password string `yaml:"password" env:"POSTGRES_PASSWORD" env-required:"true"`
+	Host     string `yaml:"host" env:"POSTGRES_HOST" env-required:"true"`
+	Port     int    `yaml:"port" env:"POSTGRES_PORT" env-default:"5432"`
+	DB       string `yaml:"db" env:"POSTGRES_DB" env-required:"true"`
+	SSLMode  string `yaml:"sslmode" env:"POSTGRES_SSLMODE" env-default:"disable"`
+
+	Pool PoolConfig `yaml:"pool"`
+	// StatementTimeout — лимит на один запрос; 0 — без лимита.
+	StatementTimeout time.Duration `yaml:"statement_timeout" env:"POSTGRES_STATEMENT_TIMEOUT"`
+	Connect          ConnectConfig `yaml:"connect"`
+	// StatsInterval — как часто писать статистику пула в лог; 0 — не писать.
+	StatsInterval time.Duration `yaml:"stats_interval" env:"POSTGRES_STATS_INTERVAL"`
+}
+
+// PoolConfig — размер пула и время жизни соединений; нули — умолчания pgxpool.
+type PoolConfig struct {
+	MaxConns          int32         `yaml:"max_conns" env:"POSTGRES_MAX_CONNS"`
+	MinConns          int32         `yaml:"min_conns" env:"POSTGRES_MIN_CONNS"`
+	MaxConnLifetime   time.Duration `yaml:"max_conn_lifetime" env:"POSTGRES_MAX_CONN_LIFETIME"`
+	MaxConnIdleTime   time.Duration `yaml:"max_conn_idle_time" env:"POSTGRES_MAX_CONN_IDLE_TIME"`
+	HealthCheckPeriod time.Duration `yaml:"health_check_period" env:"POSTGRES_HEALTH_CHECK_PERIOD"`
 }
 
-func MustLoad(path string) *Config {
-	b, err := os.ReadFile(path)
+// ConnectConfig — ожидание базы при старте: Attempts попыток по Timeout,
+// пауза между ними растёт от InitialBackoff до MaxBackoff.
+type ConnectConfig struct {
+	Timeout        time.Duration `yaml:"timeout" env:"POSTGRES_CONNECT_TIMEOUT"`
+	Attempts       int           `yaml:"attempts" env:"POSTGRES_CONNECT_ATTEMPTS"`
+	InitialBackoff time.Duration `yaml:"initial_backoff" env:"POSTGRES_CONNECT_INITIAL_BACKOFF"`
+	MaxBackoff     time.Duration `yaml:"max_backoff" env:"POSTGRES_CONNECT_MAX_BACKOFF"`
+}
+
+const defaultPath = "config/config.yaml"
+
+// Load собирает конфиг из YAML, переменных окружения и флагов (в порядке
+// возрастания приоритета) и проверяет его. Путь к YAML — флаг -config или
+// CONFIG_PATH. Возвращает аргументы, оставшиеся после флагов.
+func Load(args []string) (*Config, []string, error) {
+	var cfg Config
+	rest, err := configload.Load(&cfg, "server", defaultPath, args)
 	if err != nil {
-		panic(fmt.Errorf("read config: %w", err))
+		return nil, nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
 	}
+	return &cfg, rest, nil
+}
 
-	var cfg Config
-	if err := yaml.Unmarshal(b, &cfg); err != nil {
-		panic(fmt.Errorf("yaml unmarshal: %w", err))
+// Validate перечисляет все ошибки конфига разом, а не первую попавшуюся.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if _, err := logging.New(io.Discard, c.Log.Level, c.Log.Format); err != nil {
+		errs = append(errs, fmt.Errorf("log: %w", err))
+	}
+	if err := (tracing.Options{Exporter: c.Tracing.Exporter, SampleRatio: c.Tracing.SampleRatio}).Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("tracing: %w", err))
+	}
+
+	if c.GRPC.Port <= 0 || c.GRPC.Port > 65535 {
+		errs = append(errs, fmt.Errorf("grpc.port: must be in 1..65535, got %d", c.GRPC.Port))
+	}
+
+	if (c.GRPC.TLS.CertFile == "") != (c.GRPC.TLS.KeyFile == "") {
+		errs = append(errs, fmt.Errorf("grpc.tls: cert_file and key_file must be set together"))
+	}
+	if c.GRPC.TLS.ClientCAFile != "" && !c.GRPC.TLS.Enabled() {
+		errs = append(errs, fmt.Errorf("grpc.tls.client_ca_file: requires cert_file and key_file"))
+	}
+
+	switch c.Storage.Driver {
+	case "postgres":
+		if c.PostgreSQL.URL == "" {
+			errs = append(errs, configload.Missing(c, "postgresql.")...)
+		}
+		errs = append(errs, c.PostgreSQL.validate()...)
+	case "memory":
+	default:
+		errs = append(errs, fmt.Errorf("storage.driver: unknown %q, want postgres or memory", c.Storage.Driver))
+	}
+
+	if c.JWT.TTL <= 0 {
+		errs = append(errs, fmt.Errorf("jwt.ttl: must be positive"))
+	}
+	if c.JWT.RefreshTTL <= 0 {
+		errs = append(errs, fmt.Errorf("jwt.refresh_ttl: must be positive"))
+	}
+	if len(c.JWT.Keys) > 0 && c.JWT.ActiveKey == "" {
+		errs = append(errs, fmt.Errorf("jwt.active_key: is required when jwt.keys are set"))
+	}
+	if len(c.JWT.Keys) == 0 && !c.JWT.EphemeralKey && c.Storage.Driver == "postgres" {
+		errs = append(errs, fmt.Errorf("jwt.keys: are required with storage.driver postgres (jwt.ephemeral_key allows a generated key for local runs)"))
 	}
 
-	return &cfg
+	return errors.Join(errs...)
+}
+
+func (c PostgreSQLConfig) validate() []error {
+	var errs []error
+	if c.Pool.MaxConns < 0 || c.Pool.MinConns < 0 {
+		errs = append(errs, fmt.Errorf("postgresql.pool: connection counts must not be negative"))
+	}
+	if c.Pool.MaxConns > 0 && c.Pool.MinConns > c.Pool.MaxConns {
+		errs = append(errs, fmt.Errorf("postgresql.pool.min_conns: %d exceeds max_conns %d", c.Pool.MinConns, c.Pool.MaxConns))
+	}
+	if c.StatementTimeout < 0 {
+		errs = append(errs, fmt.Errorf("postgresql.statement_timeout: must not be negative"))
+	}
+	if c.Connect.Attempts < 0 {
+		errs = append(errs, fmt.Errorf("postgresql.connect.attempts: must not be negative"))
+	}
+	return errs
 }
 
 func (c *Config) PostgresDSN() string {
+	return c.PostgreSQL.DSN()
+}
+
+func (c PostgreSQLConfig) DSN() string {
+	if c.URL != "" {
+		return c.URL
+	}
 	return fmt.Sprintf(
 		"postgres://%s:%s@%s:%d/%s?sslmode=%s",
-		c.PostgreSQL.User,
-		c.PostgreSQL.Password,
-		c.PostgreSQL.Host,
-		c.PostgreSQL.Port,
-		c.PostgreSQL.DB,
-		c.PostgreSQL.SSLMode,
+		c.User,
+		c.Password,
+		c.Host,
+		c.Port,
+		c.DB,
+		c.SSLMode,
 	)
 }
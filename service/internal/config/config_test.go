@@ -0,0 +1,164 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testYAML = `
+grpc:
+  port: 9090
+storage:
+  driver: "postgres"
+postgresql:
+  user: "user"
+  password: "password"
+  host: "localhost"
+  db: "posts"
+  pool:
+    max_conns: 10
+jwt:
+  ttl: 5m
+  ephemeral_key: true
+`
+
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_Priority(t *testing.T) {
+	path := writeConfig(t, testYAML)
+	t.Setenv("GRPC_PORT", "9191")
+	t.Setenv("POSTGRES_HOST", "env-host")
+	t.Setenv("POSTGRES_MAX_CONNS", "30")
+
+	cfg, rest, err := Load([]string{"-config", path, "-grpc.port", "9292", "migrate", "up"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.GRPC.Port != 9292 {
+		t.Fatalf("flag must win over env: port=%d", cfg.GRPC.Port)
+	}
+	if cfg.PostgreSQL.Host != "env-host" || cfg.PostgreSQL.Pool.MaxConns != 30 {
+		t.Fatalf("env must win over yaml: host=%q max_conns=%d", cfg.PostgreSQL.Host, cfg.PostgreSQL.Pool.MaxConns)
+	}
+	if cfg.JWT.TTL != 5*time.Minute {
+		t.Fatalf("yaml value lost: ttl=%s", cfg.JWT.TTL)
+	}
+	if cfg.JWT.RefreshTTL != 720*time.Hour || cfg.PostgreSQL.Port != 5432 || cfg.PostgreSQL.SSLMode != "disable" {
+		t.Fatalf("env-default not applied: %+v", cfg)
+	}
+	if strings.Join(rest, " ") != "migrate up" {
+		t.Fatalf("rest args = %v", rest)
+	}
+}
+
+func TestLoad_EnvOnly(t *testing.T) {
+	// без файла по умолчанию конфиг целиком берётся из окружения
+	t.Chdir(t.TempDir())
+	t.Setenv("DATABASE_URL", "postgres://u:p@db:5432/posts?sslmode=disable")
+	t.Setenv("JWT_EPHEMERAL_KEY", "true")
+
+	cfg, _, err := Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.PostgresDSN() != "postgres://u:p@db:5432/posts?sslmode=disable" {
+		t.Fatalf("DATABASE_URL must be used as DSN, got %q", cfg.PostgresDSN())
+	}
+	if cfg.GRPC.Port != 9090 || cfg.Storage.Driver != "postgres" {
+		t.Fatalf("defaults not applied: %+v", cfg)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		env     map[string]string
+		args    []string
+		wantErr []string
+	}{
+		{
+			name:    "missing postgres fields",
+			yaml:    "storage:\n  driver: postgres\n",
+			wantErr: []string{"postgresql.user: is required", "postgresql.host: is required", "env POSTGRES_HOST"},
+		},
+		{
+			name: "memory driver needs no postgres",
+			yaml: "storage:\n  driver: memory\n",
+		},
+		{
+			name:    "bad env value",
+			yaml:    testYAML,
+			env:     map[string]string{"GRPC_PORT": "ninety"},
+			wantErr: []string{"env GRPC_PORT", `bad integer "ninety"`},
+		},
+		{
+			name:    "bad flag value",
+			yaml:    testYAML,
+			args:    []string{"-jwt.ttl", "soon"},
+			wantErr: []string{"-jwt.ttl", `bad duration "soon"`},
+		},
+		{
+			name:    "several validation errors at once",
+			yaml:    testYAML,
+			args:    []string{"-grpc.port", "70000", "-storage.driver", "mongo"},
+			wantErr: []string{"grpc.port: must be in 1..65535", `storage.driver: unknown "mongo"`},
+		},
+		{
+			name:    "min above max",
+			yaml:    testYAML,
+			env:     map[string]string{"POSTGRES_MIN_CONNS": "20"},
+			wantErr: []string{"postgresql.pool.min_conns: 20 exceeds max_conns 10"},
+		},
+		{
+			name:    "postgres needs signing keys",
+			yaml:    testYAML,
+			env:     map[string]string{"JWT_EPHEMERAL_KEY": "false"},
+			wantErr: []string{"jwt.keys: are required"},
+		},
+		{
+			name:    "explicit config path must exist",
+			args:    []string{"-config", "/nonexistent/config.yaml"},
+			wantErr: []string{"read config"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			args := tt.args
+			if tt.yaml != "" {
+				args = append([]string{"-config", writeConfig(t, tt.yaml)}, args...)
+			}
+
+			_, _, err := Load(args)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Fatalf("error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -1,19 +0,0 @@
-package postgresql
-
-import (
-	"context"
-	_ "embed"
-	"fmt"
-
-	"github.com/jackc/pgx/v5/pgxpool"
-)
-
-//go:embed init_001.sql
-var initSQL string
-
-func runMigrations(ctx context.Context, pool *pgxpool.Pool) error {
-	if _, err := pool.Exec(ctx, initSQL); err != nil {
-		return fmt.Errorf("run init migration: %w", err)
-	}
-	return nil
-}
@@ -0,0 +1,2 @@
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS edited_at timestamptz NULL;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS deleted_at timestamptz NULL;
@@ -0,0 +1,2 @@
+-- bcrypt-хэши не обратить: откат оставляет пароли захэшированными
+SELECT 1;
@@ -0,0 +1,4 @@
+-- пароли хранятся в виде bcrypt-хэшей; pgcrypto даёт совместимый с Go формат $2a$
+UPDATE users
+SET password = crypt(password, gen_salt('bf', 10))
+WHERE password NOT LIKE '$2_$%';
@@ -0,0 +1,334 @@
+package postgresql
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// migrationLockID — ключ pg_advisory_lock: одновременно мигрирует только одна реплика.
+const migrationLockID int64 = 0x6f7a6f6e5f706f73
+
+var (
+	ErrSchemaTooNew     = errors.New("database schema is newer than this binary")
+	ErrUnknownVersion   = errors.New("unknown migration version")
+	ErrBadMigrationName = errors.New("bad migration file name")
+	ErrMissingDown      = errors.New("migration has no down file")
+)
+
+var migrationName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration — пара файлов NNNN_name.up.sql / NNNN_name.down.sql.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus — миграция и время её применения; nil, если ещё не применена.
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+func NewMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationsFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+// Latest — версия последней миграции, известной этому бинарнику.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up применяет все ещё не применённые миграции.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.To(ctx, m.Latest())
+}
+
+// Down откатывает последнюю применённую миграцию.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		current, err := currentVersion(ctx, conn)
+		if err != nil || current == 0 {
+			return err
+		}
+
+		idx := m.index(current)
+		if idx < 0 {
+			return fmt.Errorf("%w: %d", ErrSchemaTooNew, current)
+		}
+		return m.down(ctx, conn, m.migrations[idx])
+	})
+}
+
+// To приводит схему к version: применяет миграции вверх или откатывает вниз.
+// version 0 откатывает всё.
+func (m *Migrator) To(ctx context.Context, version int64) error {
+	if version != 0 && m.index(version) < 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		current, err := currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if current > m.Latest() {
+			return fmt.Errorf("%w: database at %d, latest known %d", ErrSchemaTooNew, current, m.Latest())
+		}
+
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mg := range m.migrations {
+			if mg.Version > version {
+				break
+			}
+			if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+			if err := m.up(ctx, conn, mg); err != nil {
+				return err
+			}
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mg := m.migrations[i]
+			if mg.Version <= version {
+				break
+			}
+			if _, ok := applied[mg.Version]; !ok {
+				continue
+			}
+			if err := m.down(ctx, conn, mg); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Status возвращает все известные миграции с отметкой о применении.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire: %w", err)
+	}
+	defer conn.Release()
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mg := range m.migrations {
+		st := MigrationStatus{Migration: mg}
+		if at, ok := applied[mg.Version]; ok {
+			st.AppliedAt = &at
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+// Version — последняя применённая версия в базе, 0 для пустой схемы.
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("acquire: %w", err)
+	}
+	defer conn.Release()
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return 0, err
+	}
+	return currentVersion(ctx, conn)
+}
+
+func (m *Migrator) index(version int64) int {
+	for i, mg := range m.migrations {
+		if mg.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("advisory lock: %w", err)
+	}
+	// ctx может быть уже отменён, а блокировку снять нужно в любом случае
+	defer func() {
+		_, _ = conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+	}()
+
+	// миграции (например, построение индексов) не должны упираться в statement_timeout пула
+	if _, err := conn.Exec(ctx, "SET statement_timeout = 0"); err != nil {
+		return fmt.Errorf("disable statement_timeout: %w", err)
+	}
+	defer func() {
+		_, _ = conn.Exec(context.Background(), "RESET statement_timeout")
+	}()
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func (m *Migrator) up(ctx context.Context, conn *pgxpool.Conn, mg Migration) error {
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, mg.Up); err != nil {
+			return fmt.Errorf("migration %04d_%s up: %w", mg.Version, mg.Name, err)
+		}
+		_, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", mg.Version, mg.Name)
+		return err
+	})
+}
+
+func (m *Migrator) down(ctx context.Context, conn *pgxpool.Conn, mg Migration) error {
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, mg.Down); err != nil {
+			return fmt.Errorf("migration %04d_%s down: %w", mg.Version, mg.Name, err)
+		}
+		_, err := tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", mg.Version)
+		return err
+	})
+}
+
+func ensureMigrationsTable(ctx context.Context, conn *pgxpool.Conn) error {
+	const query = `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version bigint PRIMARY KEY,
+			name text NOT NULL,
+			applied_at timestamptz NOT NULL DEFAULT now()
+		);
+	`
+	if _, err := conn.Exec(ctx, query); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+func currentVersion(ctx context.Context, conn *pgxpool.Conn) (int64, error) {
+	var v int64
+	err := conn.QueryRow(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&v)
+	if err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	return v, nil
+}
+
+func appliedVersions(ctx context.Context, conn *pgxpool.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.Query(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[int64]time.Time)
+	for rows.Next() {
+		var v int64
+		var at time.Time
+		if err := rows.Scan(&v, &at); err != nil {
+			return nil, err
+		}
+		out[v] = at
+	}
+	return out, rows.Err()
+}
+
+// loadMigrations читает пары up/down из dir и сортирует их по версии.
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		parts := migrationName.FindStringSubmatch(e.Name())
+		if parts == nil {
+			return nil, fmt.Errorf("%w: %s", ErrBadMigrationName, e.Name())
+		}
+
+		version, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrBadMigrationName, e.Name())
+		}
+		body, err := fs.ReadFile(fsys, dir+"/"+e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", e.Name(), err)
+		}
+
+		mg := byVersion[version]
+		if mg == nil {
+			mg = &Migration{Version: version, Name: parts[2]}
+			byVersion[version] = mg
+		}
+		if mg.Name != parts[2] {
+			return nil, fmt.Errorf("%w: version %d has names %q and %q", ErrBadMigrationName, version, mg.Name, parts[2])
+		}
+		if parts[3] == "up" {
+			mg.Up = string(body)
+		} else {
+			mg.Down = string(body)
+		}
+	}
+
+	out := make([]Migration, 0, len(byVersion))
+	for _, mg := range byVersion {
+		if mg.Up == "" {
+			return nil, fmt.Errorf("%w: %04d_%s has no up file", ErrBadMigrationName, mg.Version, mg.Name)
+		}
+		if mg.Down == "" {
+			return nil, fmt.Errorf("%w: %04d_%s", ErrMissingDown, mg.Version, mg.Name)
+		}
+		out = append(out, *mg)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+
+	return out, nil
+}
@@ -0,0 +1,91 @@
+package postgresql
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	migrations, err := loadMigrations(migrationsFS, "migrations")
+	if err != nil {
+		t.Fatalf("load embedded migrations: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatalf("no embedded migrations")
+	}
+
+	for i, mg := range migrations {
+		if mg.Version != int64(i+1) {
+			t.Fatalf("migration %d has version %d: versions must go without gaps", i, mg.Version)
+		}
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	file := func(body string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(body)} }
+
+	tests := []struct {
+		name    string
+		fs      fstest.MapFS
+		want    []int64
+		wantErr error
+	}{
+		{
+			name: "sorted by version",
+			fs: fstest.MapFS{
+				"m/0010_later.up.sql":   file("SELECT 10;"),
+				"m/0010_later.down.sql": file("SELECT -10;"),
+				"m/0002_first.up.sql":   file("SELECT 2;"),
+				"m/0002_first.down.sql": file("SELECT -2;"),
+			},
+			want: []int64{2, 10},
+		},
+		{
+			name:    "missing down",
+			fs:      fstest.MapFS{"m/0001_init.up.sql": file("SELECT 1;")},
+			wantErr: ErrMissingDown,
+		},
+		{
+			name:    "missing up",
+			fs:      fstest.MapFS{"m/0001_init.down.sql": file("SELECT 1;")},
+			wantErr: ErrBadMigrationName,
+		},
+		{
+			name:    "bad name",
+			fs:      fstest.MapFS{"m/init.sql": file("SELECT 1;")},
+			wantErr: ErrBadMigrationName,
+		},
+		{
+			name: "names differ",
+			fs: fstest.MapFS{
+				"m/0001_init.up.sql":   file("SELECT 1;"),
+				"m/0001_boot.down.sql": file("SELECT 1;"),
+			},
+			wantErr: ErrBadMigrationName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadMigrations(tt.fs, "m")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d migrations, got %d", len(tt.want), len(got))
+			}
+			for i, v := range tt.want {
+				if got[i].Version != v {
+					t.Fatalf("migration %d: expected version %d, got %d", i, v, got[i].Version)
+				}
+			}
+		})
+	}
+}
@@ -3,26 +3,157 @@ package postgresql
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const (
+	defaultConnectAttempts   = 10
+	defaultConnectBackoff    = 500 * time.Millisecond
+	defaultConnectMaxBackoff = 10 * time.Second
+	defaultConnectTimeout    = 5 * time.Second
+)
+
+// Options — настройки пула; нулевые значения оставляют умолчания pgxpool.
+type Options struct {
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+
+	// StatementTimeout ограничивает каждый запрос на стороне Postgres.
+	StatementTimeout time.Duration
+
+	// ConnectTimeout — на одну попытку подключения при старте;
+	// неудачные попытки повторяются ConnectAttempts раз с удвоением паузы.
+	ConnectTimeout    time.Duration
+	ConnectAttempts   int
+	ConnectBackoff    time.Duration
+	ConnectMaxBackoff time.Duration
+
+	// Tracer получает каждый запрос пула, например для спанов OpenTelemetry.
+	Tracer pgx.QueryTracer
+}
+
 type DB struct {
 	Pool *pgxpool.Pool
 }
 
-func New(ctx context.Context, dsn string) (*DB, error) {
-	pool, err := pgxpool.New(ctx, dsn)
+// New подключается к базе и применяет недостающие миграции. Если схема
+// новее, чем знает этот бинарник, сервис не стартует (ErrSchemaTooNew).
+func New(ctx context.Context, dsn string, opts Options) (*DB, error) {
+	pool, err := Connect(ctx, dsn, opts)
 	if err != nil {
-		return nil, fmt.Errorf("pgxpool.New: %w", err)
+		return nil, err
 	}
-	if err := runMigrations(ctx, pool); err != nil {
+
+	migrator, err := NewMigrator(pool)
+	if err != nil {
 		pool.Close()
 		return nil, err
 	}
+	if err := migrator.Up(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
 	return &DB{Pool: pool}, nil
 }
 
+// Connect создаёт пул и ждёт, пока Postgres начнёт принимать соединения.
+func Connect(ctx context.Context, dsn string, opts Options) (*pgxpool.Pool, error) {
+	cfg, err := poolConfig(dsn, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("pgxpool.New: %w", err)
+	}
+
+	if err := ping(ctx, pool, opts); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return pool, nil
+}
+
+func poolConfig(dsn string, opts Options) (*pgxpool.Config, error) {
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("parse dsn: %w", err)
+	}
+
+	if opts.MaxConns > 0 {
+		cfg.MaxConns = opts.MaxConns
+	}
+	if opts.MinConns > 0 {
+		cfg.MinConns = opts.MinConns
+	}
+	if opts.MaxConnLifetime > 0 {
+		cfg.MaxConnLifetime = opts.MaxConnLifetime
+	}
+	if opts.MaxConnIdleTime > 0 {
+		cfg.MaxConnIdleTime = opts.MaxConnIdleTime
+	}
+	if opts.HealthCheckPeriod > 0 {
+		cfg.HealthCheckPeriod = opts.HealthCheckPeriod
+	}
+	if opts.StatementTimeout > 0 {
+		cfg.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(opts.StatementTimeout.Milliseconds(), 10)
+	}
+	if opts.Tracer != nil {
+		cfg.ConnConfig.Tracer = opts.Tracer
+	}
+
+	return cfg, nil
+}
+
+func ping(ctx context.Context, pool *pgxpool.Pool, opts Options) error {
+	attempts := opts.ConnectAttempts
+	if attempts <= 0 {
+		attempts = defaultConnectAttempts
+	}
+	backoff := opts.ConnectBackoff
+	if backoff <= 0 {
+		backoff = defaultConnectBackoff
+	}
+	maxBackoff := opts.ConnectMaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultConnectMaxBackoff
+	}
+	timeout := opts.ConnectTimeout
+	if timeout <= 0 {
+		timeout = defaultConnectTimeout
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		pingCtx, cancel := context.WithTimeout(ctx, timeout)
+		err = pool.Ping(pingCtx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if attempt >= attempts {
+			return fmt.Errorf("postgres is unavailable after %d attempts: %w", attempts, err)
+		}
+
+		slog.Warn("postgres: connect failed, retrying", "attempt", attempt, "attempts", attempts, "error", err, "delay", backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
 func (d *DB) Close() { d.Pool.Close() }
 
 func (d *DB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
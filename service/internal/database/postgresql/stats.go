@@ -0,0 +1,71 @@
+package postgresql
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PoolStats — снимок состояния пула для мониторинга.
+type PoolStats struct {
+	MaxConns          int32         `json:"max_conns"`
+	TotalConns        int32         `json:"total_conns"`
+	IdleConns         int32         `json:"idle_conns"`
+	AcquiredConns     int32         `json:"acquired_conns"`
+	ConstructingConns int32         `json:"constructing_conns"`
+	AcquireCount      int64         `json:"acquire_count"`
+	AcquireDuration   time.Duration `json:"acquire_duration"`
+	// EmptyAcquireCount — сколько раз пришлось ждать свободное соединение:
+	// растёт быстро — пул мал для нагрузки.
+	EmptyAcquireCount       int64 `json:"empty_acquire_count"`
+	CanceledAcquireCount    int64 `json:"canceled_acquire_count"`
+	NewConnsCount           int64 `json:"new_conns_count"`
+	MaxLifetimeDestroyCount int64 `json:"max_lifetime_destroy_count"`
+	MaxIdleDestroyCount     int64 `json:"max_idle_destroy_count"`
+}
+
+func Stats(pool *pgxpool.Pool) PoolStats {
+	s := pool.Stat()
+	return PoolStats{
+		MaxConns:                s.MaxConns(),
+		TotalConns:              s.TotalConns(),
+		IdleConns:               s.IdleConns(),
+		AcquiredConns:           s.AcquiredConns(),
+		ConstructingConns:       s.ConstructingConns(),
+		AcquireCount:            s.AcquireCount(),
+		AcquireDuration:         s.AcquireDuration(),
+		EmptyAcquireCount:       s.EmptyAcquireCount(),
+		CanceledAcquireCount:    s.CanceledAcquireCount(),
+		NewConnsCount:           s.NewConnsCount(),
+		MaxLifetimeDestroyCount: s.MaxLifetimeDestroyCount(),
+		MaxIdleDestroyCount:     s.MaxIdleDestroyCount(),
+	}
+}
+
+func (d *DB) Stats() PoolStats { return Stats(d.Pool) }
+
+// LogStats пишет состояние пула в лог раз в interval до отмены ctx.
+func LogStats(ctx context.Context, pool *pgxpool.Pool, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s := Stats(pool)
+			slog.Info("postgres pool",
+				"total", s.TotalConns,
+				"max", s.MaxConns,
+				"idle", s.IdleConns,
+				"acquired", s.AcquiredConns,
+				"constructing", s.ConstructingConns,
+				"empty_acquires", s.EmptyAcquireCount,
+				"canceled_acquires", s.CanceledAcquireCount,
+			)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package events
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/google/uuid"
+)
+
+const subscriberBuffer = 64
+
+// Filter выбирает события. Kinds пусто — только KindCommentCreated.
+// Для комментариев учитываются PostID и ParentID (ParentID != nil — только
+// ответы на него); события о постах идут всем, кто подписан на KindPostCreated.
+type Filter struct {
+	PostID   uuid.UUID
+	ParentID *uuid.UUID
+	Kinds    []Kind
+}
+
+func (f Filter) wants(k Kind) bool {
+	if len(f.Kinds) == 0 {
+		return k == KindCommentCreated
+	}
+	return slices.Contains(f.Kinds, k)
+}
+
+func (f Filter) match(ev Event) bool {
+	if !f.wants(ev.Kind) {
+		return false
+	}
+	if ev.Kind == KindPostCreated {
+		return true
+	}
+	return f.matchComment(ev.Comment.PostID, ev.Comment.ParentCommentID)
+}
+
+func (f Filter) matchComment(postID uuid.UUID, parentID *uuid.UUID) bool {
+	if postID != f.PostID {
+		return false
+	}
+	if f.ParentID == nil {
+		return true
+	}
+	return parentID != nil && *parentID == *f.ParentID
+}
+
+// Event — событие для подписчика и число событий, потерянных перед ним.
+// Для событий о комментариях задан Comment, для KindPostCreated — Post.
+type Event struct {
+	Kind    Kind
+	Comment *models.Comment
+	Post    *models.Post
+	Dropped int
+}
+
+type subscriber struct {
+	ch      chan Event
+	filter  Filter
+	dropped atomic.Int64
+}
+
+// Bus раздаёт события о комментариях и постах подписчикам внутри процесса
+// сервиса. Медленный подписчик теряет события, а не тормозит остальных;
+// сколько потеряно, он узнаёт из Event.Dropped следующего доставленного
+// ему события. Потери считаются только среди событий, прошедших его фильтр.
+type Bus struct {
+	mu     sync.RWMutex
+	subs   map[*subscriber]struct{}
+	closed bool
+}
+
+var _ Publisher = (*Bus)(nil)
+
+func NewBus() *Bus {
+	return &Bus{subs: make(map[*subscriber]struct{})}
+}
+
+// Subscribe возвращает канал, который закрывается после отмены ctx или Close.
+func (b *Bus) Subscribe(ctx context.Context, f Filter) <-chan Event {
+	sub := &subscriber{ch: make(chan Event, subscriberBuffer), filter: f}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		close(sub.ch)
+		return sub.ch
+	}
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	context.AfterFunc(ctx, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(sub)
+	})
+
+	return sub.ch
+}
+
+// Close закрывает каналы всех подписчиков и не принимает новых: потоки
+// WatchComments завершаются, и GracefulStop не ждёт отключения клиентов.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for sub := range b.subs {
+		b.remove(sub)
+	}
+}
+
+// remove вызывается под b.mu; канал закрывается ровно один раз.
+func (b *Bus) remove(sub *subscriber) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+	delete(b.subs, sub)
+	close(sub.ch)
+}
+
+// HasSubscribers сообщает, ждёт ли кто-нибудь событие kind о комментарии
+// поста postID с родителем parentID (для KindPostCreated они не важны). Так
+// listener не загружает то, что никому не нужно.
+func (b *Bus) HasSubscribers(kind Kind, postID uuid.UUID, parentID *uuid.UUID) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub := range b.subs {
+		if !sub.filter.wants(kind) {
+			continue
+		}
+		if kind == KindPostCreated || sub.filter.matchComment(postID, parentID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Bus) CommentCreated(ctx context.Context, c *models.Comment) error {
+	b.publish(Event{Kind: KindCommentCreated, Comment: c})
+	return nil
+}
+
+func (b *Bus) CommentEdited(ctx context.Context, c *models.Comment) error {
+	b.publish(Event{Kind: KindCommentEdited, Comment: c})
+	return nil
+}
+
+func (b *Bus) CommentDeleted(ctx context.Context, c *models.Comment) error {
+	b.publish(Event{Kind: KindCommentDeleted, Comment: c})
+	return nil
+}
+
+func (b *Bus) PostCreated(ctx context.Context, p *models.Post) error {
+	b.publish(Event{Kind: KindPostCreated, Post: p})
+	return nil
+}
+
+func (b *Bus) publish(ev Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subs {
+		if !sub.filter.match(ev) {
+			continue
+		}
+
+		dropped := sub.dropped.Swap(0)
+		ev.Dropped = int(dropped)
+		select {
+		case sub.ch <- ev:
+		default:
+			sub.dropped.Add(dropped + 1)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package events
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestBus_FiltersByPostAndParent(t *testing.T) {
+	postID := uuid.New()
+	parentID := uuid.New()
+
+	tests := []struct {
+		name    string
+		filter  Filter
+		comment *models.Comment
+		want    bool
+	}{
+		{
+			name:    "same post",
+			filter:  Filter{PostID: postID},
+			comment: &models.Comment{ID: uuid.New(), PostID: postID},
+			want:    true,
+		},
+		{
+			name:    "reply matches post filter",
+			filter:  Filter{PostID: postID},
+			comment: &models.Comment{ID: uuid.New(), PostID: postID, ParentCommentID: &parentID},
+			want:    true,
+		},
+		{
+			name:    "other post",
+			filter:  Filter{PostID: postID},
+			comment: &models.Comment{ID: uuid.New(), PostID: uuid.New()},
+			want:    false,
+		},
+		{
+			name:    "reply to parent",
+			filter:  Filter{PostID: postID, ParentID: &parentID},
+			comment: &models.Comment{ID: uuid.New(), PostID: postID, ParentCommentID: &parentID},
+			want:    true,
+		},
+		{
+			name:    "top-level comment with parent filter",
+			filter:  Filter{PostID: postID, ParentID: &parentID},
+			comment: &models.Comment{ID: uuid.New(), PostID: postID},
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := NewBus()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ch := bus.Subscribe(ctx, tt.filter)
+			if err := bus.CommentCreated(ctx, tt.comment); err != nil {
+				t.Fatalf("CommentCreated: %v", err)
+			}
+
+			select {
+			case got := <-ch:
+				if !tt.want {
+					t.Fatalf("unexpected comment %s", got.Comment.ID)
+				}
+				if got.Comment.ID != tt.comment.ID {
+					t.Fatalf("got comment %s, want %s", got.Comment.ID, tt.comment.ID)
+				}
+			default:
+				if tt.want {
+					t.Fatalf("comment was not delivered")
+				}
+			}
+		})
+	}
+}
+
+func TestBus_ReportsDroppedEvents(t *testing.T) {
+	bus := NewBus()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	postID := uuid.New()
+	ch := bus.Subscribe(ctx, Filter{PostID: postID})
+
+	const overflow = 3
+	for i := 0; i < subscriberBuffer+overflow; i++ {
+		_ = bus.CommentCreated(ctx, &models.Comment{ID: uuid.New(), PostID: postID})
+	}
+	for i := 0; i < subscriberBuffer; i++ {
+		if ev := <-ch; ev.Dropped != 0 {
+			t.Fatalf("event %d: unexpected dropped=%d", i, ev.Dropped)
+		}
+	}
+
+	_ = bus.CommentCreated(ctx, &models.Comment{ID: uuid.New(), PostID: postID})
+	if ev := <-ch; ev.Dropped != overflow {
+		t.Fatalf("expected dropped=%d, got %d", overflow, ev.Dropped)
+	}
+
+	_ = bus.CommentCreated(ctx, &models.Comment{ID: uuid.New(), PostID: postID})
+	if ev := <-ch; ev.Dropped != 0 {
+		t.Fatalf("dropped counter was not reset: %d", ev.Dropped)
+	}
+}
+
+func TestBus_ClosesChannelOnCancel(t *testing.T) {
+	bus := NewBus()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch := bus.Subscribe(ctx, Filter{PostID: uuid.New()})
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Fatalf("expected closed channel")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("channel was not closed after cancel")
+	}
+}
+
+func TestBus_Close(t *testing.T) {
+	bus := NewBus()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := bus.Subscribe(ctx, Filter{PostID: uuid.New()})
+	bus.Close()
+	if _, ok := <-ch; ok {
+		t.Fatalf("expected closed channel after Close")
+	}
+
+	// отмена после Close не закрывает канал повторно
+	cancel()
+
+	late := bus.Subscribe(context.Background(), Filter{PostID: uuid.New()})
+	if _, ok := <-late; ok {
+		t.Fatalf("subscription after Close must be closed")
+	}
+}
+
+func TestBus_HasSubscribers(t *testing.T) {
+	bus := NewBus()
+	ctx, cancel := context.WithCancel(context.Background())
+	postID := uuid.New()
+	parentID := uuid.New()
+
+	if bus.HasSubscribers(KindCommentCreated, postID, nil) {
+		t.Fatalf("empty bus has no subscribers")
+	}
+
+	bus.Subscribe(ctx, Filter{PostID: postID, ParentID: &parentID})
+	if bus.HasSubscribers(KindCommentCreated, postID, nil) {
+		t.Fatalf("top-level comment must not match a replies subscription")
+	}
+	if !bus.HasSubscribers(KindCommentCreated, postID, &parentID) {
+		t.Fatalf("reply must match a replies subscription")
+	}
+	if bus.HasSubscribers(KindCommentCreated, uuid.New(), &parentID) {
+		t.Fatalf("other post must not match")
+	}
+
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for bus.HasSubscribers(KindCommentCreated, postID, &parentID) {
+		if time.Now().After(deadline) {
+			t.Fatalf("subscriber was not removed after cancel")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBus_FiltersByKind(t *testing.T) {
+	postID := uuid.New()
+	comment := &models.Comment{ID: uuid.New(), PostID: postID}
+	post := &models.Post{ID: uuid.New()}
+
+	publish := map[Kind]func(ctx context.Context, b *Bus) error{
+		KindCommentCreated: func(ctx context.Context, b *Bus) error { return b.CommentCreated(ctx, comment) },
+		KindCommentEdited:  func(ctx context.Context, b *Bus) error { return b.CommentEdited(ctx, comment) },
+		KindCommentDeleted: func(ctx context.Context, b *Bus) error { return b.CommentDeleted(ctx, comment) },
+		KindPostCreated:    func(ctx context.Context, b *Bus) error { return b.PostCreated(ctx, post) },
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []Kind
+	}{
+		{name: "default is created only", filter: Filter{PostID: postID}, want: []Kind{KindCommentCreated}},
+		{
+			name:   "edited and deleted",
+			filter: Filter{PostID: postID, Kinds: []Kind{KindCommentEdited, KindCommentDeleted}},
+			want:   []Kind{KindCommentEdited, KindCommentDeleted},
+		},
+		{name: "posts", filter: Filter{Kinds: []Kind{KindPostCreated}}, want: []Kind{KindPostCreated}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := NewBus()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ch := bus.Subscribe(ctx, tt.filter)
+			for _, kind := range []Kind{KindCommentCreated, KindCommentEdited, KindCommentDeleted, KindPostCreated} {
+				if err := publish[kind](ctx, bus); err != nil {
+					t.Fatalf("publish %s: %v", kind, err)
+				}
+			}
+
+			var got []Kind
+			for len(ch) > 0 {
+				got = append(got, (<-ch).Kind)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("got kinds %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBus_DroppedCountsOnlyMatchingEvents(t *testing.T) {
+	bus := NewBus()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	postID := uuid.New()
+	ch := bus.Subscribe(ctx, Filter{PostID: postID, Kinds: []Kind{KindCommentEdited}})
+
+	c := &models.Comment{ID: uuid.New(), PostID: postID}
+	for i := 0; i < subscriberBuffer+2; i++ {
+		_ = bus.CommentEdited(ctx, c)
+	}
+	for i := 0; i < subscriberBuffer; i++ {
+		<-ch
+	}
+
+	// чужие события не доставляются и не сбрасывают счётчик потерь
+	_ = bus.CommentCreated(ctx, c)
+	_ = bus.PostCreated(ctx, &models.Post{ID: uuid.New()})
+	_ = bus.CommentEdited(ctx, c)
+
+	ev := <-ch
+	if ev.Kind != KindCommentEdited || ev.Dropped != 2 {
+		t.Fatalf("got %s dropped=%d, want %s dropped=2", ev.Kind, ev.Dropped, KindCommentEdited)
+	}
+}
+
+func TestBus_HasSubscribersByKind(t *testing.T) {
+	bus := NewBus()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	postID := uuid.New()
+	bus.Subscribe(ctx, Filter{PostID: postID, Kinds: []Kind{KindCommentDeleted}})
+
+	if bus.HasSubscribers(KindCommentCreated, postID, nil) {
+		t.Fatalf("created must not match a deleted-only subscription")
+	}
+	if !bus.HasSubscribers(KindCommentDeleted, postID, nil) {
+		t.Fatalf("deleted must match")
+	}
+	if bus.HasSubscribers(KindPostCreated, uuid.Nil, nil) {
+		t.Fatalf("no post subscribers yet")
+	}
+
+	bus.Subscribe(ctx, Filter{Kinds: []Kind{KindPostCreated}})
+	if !bus.HasSubscribers(KindPostCreated, uuid.Nil, nil) {
+		t.Fatalf("post subscription must match")
+	}
+}
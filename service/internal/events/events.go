@@ -0,0 +1,91 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CommentsChannel — канал LISTEN/NOTIFY, в который уходят события о
+// комментариях и постах.
+const CommentsChannel = "comment_events"
+
+// Kind — тип события.
+type Kind string
+
+const (
+	KindCommentCreated Kind = "comment_created"
+	KindCommentEdited  Kind = "comment_edited"
+	KindCommentDeleted Kind = "comment_deleted"
+	KindPostCreated    Kind = "post_created"
+)
+
+// Notification — полезная нагрузка NOTIFY. Текст не передаётся: NOTIFY
+// ограничен 8000 байтами, подписчик дочитывает комментарий или пост по id.
+// Пустой Kind означает KindCommentCreated: так писали прежние версии.
+type Notification struct {
+	Kind     Kind       `json:"kind,omitempty"`
+	ID       uuid.UUID  `json:"id"`
+	PostID   uuid.UUID  `json:"post_id"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+}
+
+type Publisher interface {
+	CommentCreated(ctx context.Context, c *models.Comment) error
+	CommentEdited(ctx context.Context, c *models.Comment) error
+	CommentDeleted(ctx context.Context, c *models.Comment) error
+	PostCreated(ctx context.Context, p *models.Post) error
+}
+
+// Nop ничего не публикует; нужен тестам сервисов, которым события не важны.
+// Хранилище memory публикует в Bus, см. app.New.
+type Nop struct{}
+
+func (Nop) CommentCreated(ctx context.Context, c *models.Comment) error { return nil }
+func (Nop) CommentEdited(ctx context.Context, c *models.Comment) error  { return nil }
+func (Nop) CommentDeleted(ctx context.Context, c *models.Comment) error { return nil }
+func (Nop) PostCreated(ctx context.Context, p *models.Post) error       { return nil }
+
+type Postgres struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgres(pool *pgxpool.Pool) *Postgres {
+	return &Postgres{pool: pool}
+}
+
+func (p *Postgres) CommentCreated(ctx context.Context, c *models.Comment) error {
+	return p.notify(ctx, commentNotification(KindCommentCreated, c))
+}
+
+func (p *Postgres) CommentEdited(ctx context.Context, c *models.Comment) error {
+	return p.notify(ctx, commentNotification(KindCommentEdited, c))
+}
+
+func (p *Postgres) CommentDeleted(ctx context.Context, c *models.Comment) error {
+	return p.notify(ctx, commentNotification(KindCommentDeleted, c))
+}
+
+func (p *Postgres) PostCreated(ctx context.Context, post *models.Post) error {
+	return p.notify(ctx, Notification{Kind: KindPostCreated, ID: post.ID, PostID: post.ID})
+}
+
+func commentNotification(kind Kind, c *models.Comment) Notification {
+	return Notification{Kind: kind, ID: c.ID, PostID: c.PostID, ParentID: c.ParentCommentID}
+}
+
+func (p *Postgres) notify(ctx context.Context, n Notification) error {
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("marshal %s event: %w", n.Kind, err)
+	}
+
+	if _, err := p.pool.Exec(ctx, `SELECT pg_notify($1, $2)`, CommentsChannel, string(payload)); err != nil {
+		return fmt.Errorf("pg_notify: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const reconnectDelay = 2 * time.Second
+
+type CommentLoader interface {
+	GetCommentByID(ctx context.Context, commentID uuid.UUID) (*models.Comment, error)
+}
+
+type PostLoader interface {
+	GetPostsByID(ctx context.Context, id string) (*models.Post, error)
+}
+
+// Listener переносит события из LISTEN в Bus, чтобы WatchComments и
+// WatchPosts на любой реплике сервиса видели изменения, сделанные на других.
+type Listener struct {
+	pool     *pgxpool.Pool
+	comments CommentLoader
+	posts    PostLoader
+	bus      *Bus
+}
+
+func NewListener(pool *pgxpool.Pool, comments CommentLoader, posts PostLoader, bus *Bus) *Listener {
+	return &Listener{pool: pool, comments: comments, posts: posts, bus: bus}
+}
+
+// Run слушает канал до отмены ctx, переподключаясь при обрыве соединения.
+func (l *Listener) Run(ctx context.Context) {
+	for {
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Warn("events: listen failed, reconnecting", "channel", CommentsChannel, "error", err, "delay", reconnectDelay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (l *Listener) listen(ctx context.Context) error {
+	pooled, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire: %w", err)
+	}
+	// соединение после LISTEN не возвращаем в пул: на нём остаётся подписка
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+CommentsChannel); err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var ev Notification
+		if err := json.Unmarshal([]byte(n.Payload), &ev); err != nil {
+			slog.Error("events: bad payload", "payload", n.Payload, "error", err)
+			continue
+		}
+		if ev.Kind == "" {
+			ev.Kind = KindCommentCreated
+		}
+
+		// NOTIFY получают все реплики, а загружать данные стоит только там, где их ждут
+		if !l.bus.HasSubscribers(ev.Kind, ev.PostID, ev.ParentID) {
+			continue
+		}
+		l.dispatch(ctx, ev)
+	}
+}
+
+func (l *Listener) dispatch(ctx context.Context, ev Notification) {
+	if ev.Kind == KindPostCreated {
+		p, err := l.posts.GetPostsByID(ctx, ev.ID.String())
+		if err != nil {
+			slog.Error("events: load post", "post_id", ev.ID, "error", err)
+			return
+		}
+		_ = l.bus.PostCreated(ctx, p)
+		return
+	}
+
+	c, err := l.comments.GetCommentByID(ctx, ev.ID)
+	if err != nil {
+		slog.Error("events: load comment", "comment_id", ev.ID, "error", err)
+		return
+	}
+	switch ev.Kind {
+	case KindCommentCreated:
+		_ = l.bus.CommentCreated(ctx, c)
+	case KindCommentEdited:
+		_ = l.bus.CommentEdited(ctx, c)
+	case KindCommentDeleted:
+		_ = l.bus.CommentDeleted(ctx, c)
+	default:
+		slog.Warn("events: unknown kind", "kind", ev.Kind)
+	}
+}
@@ -0,0 +1,85 @@
+// Package health держит статус стандартного grpc.health.v1 в соответствии
+// с доступностью хранилища.
+package health
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	defaultInterval = 5 * time.Second
+	defaultTimeout  = 2 * time.Second
+)
+
+// Pinger проверяет хранилище; для memory всегда nil.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Monitor периодически пингует хранилище и переключает SERVING/NOT_SERVING
+// для сервера в целом ("") и для каждого из services.
+type Monitor struct {
+	srv      *health.Server
+	pinger   Pinger
+	services []string
+	interval time.Duration
+	timeout  time.Duration
+}
+
+func NewMonitor(srv *health.Server, pinger Pinger, interval time.Duration, services ...string) *Monitor {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return &Monitor{
+		srv:      srv,
+		pinger:   pinger,
+		services: append([]string{""}, services...),
+		interval: interval,
+		timeout:  min(defaultTimeout, interval),
+	}
+}
+
+// Run проверяет хранилище сразу и затем раз в interval до отмены ctx.
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	healthy := m.Check(ctx, true)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			healthy = m.Check(ctx, healthy)
+		}
+	}
+}
+
+// Check пингует хранилище и выставляет статус. prev — результат прошлой
+// проверки: смена состояния пишется в лог.
+func (m *Monitor) Check(ctx context.Context, prev bool) bool {
+	pingCtx, cancel := context.WithTimeout(ctx, m.timeout)
+	err := m.pinger.Ping(pingCtx)
+	cancel()
+
+	st := healthpb.HealthCheckResponse_SERVING
+	if err != nil {
+		st = healthpb.HealthCheckResponse_NOT_SERVING
+		if prev {
+			slog.Error("health: storage is unavailable", "error", err)
+		}
+	} else if !prev {
+		slog.Info("health: storage is available again")
+	}
+
+	// после srv.Shutdown() статусы не меняются: сервер уже сливает трафик
+	for _, name := range m.services {
+		m.srv.SetServingStatus(name, st)
+	}
+	return err == nil
+}
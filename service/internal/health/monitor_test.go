@@ -0,0 +1,69 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type fakePinger struct{ err error }
+
+func (p *fakePinger) Ping(context.Context) error { return p.err }
+
+func status(t *testing.T, srv *health.Server, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := srv.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("check %q: %v", service, err)
+	}
+	return resp.GetStatus()
+}
+
+func TestMonitor_Check(t *testing.T) {
+	srv := health.NewServer()
+	pinger := &fakePinger{}
+	m := NewMonitor(srv, pinger, time.Second, "svc.Posts")
+
+	if !m.Check(context.Background(), true) {
+		t.Fatalf("healthy storage reported as down")
+	}
+	for _, name := range []string{"", "svc.Posts"} {
+		if got := status(t, srv, name); got != healthpb.HealthCheckResponse_SERVING {
+			t.Fatalf("%q status = %s, want SERVING", name, got)
+		}
+	}
+
+	pinger.err = errors.New("connection refused")
+	if m.Check(context.Background(), true) {
+		t.Fatalf("failed ping reported as healthy")
+	}
+	for _, name := range []string{"", "svc.Posts"} {
+		if got := status(t, srv, name); got != healthpb.HealthCheckResponse_NOT_SERVING {
+			t.Fatalf("%q status = %s, want NOT_SERVING", name, got)
+		}
+	}
+
+	pinger.err = nil
+	m.Check(context.Background(), false)
+	if got := status(t, srv, ""); got != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("status after recovery = %s, want SERVING", got)
+	}
+}
+
+func TestMonitor_ShutdownWins(t *testing.T) {
+	srv := health.NewServer()
+	m := NewMonitor(srv, &fakePinger{}, time.Second)
+	m.Check(context.Background(), true)
+
+	// во время остановки удачный пинг не должен вернуть SERVING
+	srv.Shutdown()
+	m.Check(context.Background(), true)
+
+	if got := status(t, srv, ""); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("status after shutdown = %s, want NOT_SERVING", got)
+	}
+}
@@ -0,0 +1,60 @@
+// Package metrics — метрики Prometheus сервиса: RPC и пул Postgres.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	rpcHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "Завершённые RPC по методу и коду статуса.",
+	}, []string{"method", "code"})
+
+	rpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "Длительность RPC; для потоков — время жизни потока.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "code"})
+)
+
+// UnaryServerInterceptor считает RPC и их длительность.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observe(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor — то же для потоковых RPC.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observe(info.FullMethod, start, err)
+		return err
+	}
+}
+
+func observe(method string, start time.Time, err error) {
+	code := status.Code(err).String()
+	rpcHandled.WithLabelValues(method, code).Inc()
+	rpcDuration.WithLabelValues(method, code).Observe(time.Since(start).Seconds())
+}
+
+// Handler отдаёт метрики реестра по умолчанию (включая Go и process).
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	return mux
+}
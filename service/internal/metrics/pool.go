@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"github.com/Parnishkaspb/ozon_posts/internal/database/postgresql"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolCollector снимает postgresql.Stats в момент опроса, а не по таймеру.
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	maxConns, totalConns, idleConns, acquiredConns, constructingConns *prometheus.Desc
+
+	acquires, acquireSeconds, emptyAcquires, canceledAcquires *prometheus.Desc
+	newConns, lifetimeDestroys, idleDestroys                  *prometheus.Desc
+}
+
+// RegisterPool регистрирует метрики пула pgx_pool_*.
+func RegisterPool(pool *pgxpool.Pool) error {
+	return prometheus.Register(newPoolCollector(pool))
+}
+
+func newPoolCollector(pool *pgxpool.Pool) *poolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc("pgx_pool_"+name, help, nil, nil)
+	}
+	return &poolCollector{
+		pool:              pool,
+		maxConns:          desc("max_conns", "Максимальный размер пула."),
+		totalConns:        desc("total_conns", "Все соединения пула."),
+		idleConns:         desc("idle_conns", "Свободные соединения."),
+		acquiredConns:     desc("acquired_conns", "Занятые соединения."),
+		constructingConns: desc("constructing_conns", "Соединения в процессе открытия."),
+		acquires:          desc("acquires_total", "Успешные получения соединения."),
+		acquireSeconds:    desc("acquire_seconds_total", "Суммарное время получения соединения."),
+		emptyAcquires:     desc("empty_acquires_total", "Получения, которым пришлось ждать свободное соединение."),
+		canceledAcquires:  desc("canceled_acquires_total", "Получения, отменённые контекстом."),
+		newConns:          desc("new_conns_total", "Открытые соединения."),
+		lifetimeDestroys:  desc("max_lifetime_destroys_total", "Соединения, закрытые по max_conn_lifetime."),
+		idleDestroys:      desc("max_idle_destroys_total", "Соединения, закрытые по max_conn_idle_time."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := postgresql.Stats(c.pool)
+
+	gauge := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v)
+	}
+	counter := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, v)
+	}
+
+	gauge(c.maxConns, float64(s.MaxConns))
+	gauge(c.totalConns, float64(s.TotalConns))
+	gauge(c.idleConns, float64(s.IdleConns))
+	gauge(c.acquiredConns, float64(s.AcquiredConns))
+	gauge(c.constructingConns, float64(s.ConstructingConns))
+	counter(c.acquires, float64(s.AcquireCount))
+	counter(c.acquireSeconds, s.AcquireDuration.Seconds())
+	counter(c.emptyAcquires, float64(s.EmptyAcquireCount))
+	counter(c.canceledAcquires, float64(s.CanceledAcquireCount))
+	counter(c.newConns, float64(s.NewConnsCount))
+	counter(c.lifetimeDestroys, float64(s.MaxLifetimeDestroyCount))
+	counter(c.idleDestroys, float64(s.MaxIdleDestroyCount))
+}
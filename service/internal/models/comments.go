@@ -12,4 +12,6 @@ type Comment struct {
 	ParentCommentID *uuid.UUID
 	Text            string
 	CreatedAt       time.Time
+	EditedAt        *time.Time
+	DeletedAt       *time.Time
 }
@@ -2,12 +2,19 @@ package comments
 
 import (
 	"context"
+	"errors"
 	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"time"
 )
 
+// foreignKeyViolation — SQLSTATE нарушения внешнего ключа.
+const foreignKeyViolation = "23503"
+
 type Repo struct {
 	pool *pgxpool.Pool
 }
@@ -20,7 +27,7 @@ func (r *Repo) CreateComment(ctx context.Context, text string, authorID, postID
 	const query = `
 		INSERT INTO comments (post_id, author_id, text)
 		VALUES ($1, $2, $3)
-		RETURNING id, post_id, author_id, parent_id, text, created_at;
+		RETURNING id, post_id, author_id, parent_id, text, created_at, edited_at, deleted_at;
 	`
 
 	return r.exec(ctx, query, postID, authorID, text)
@@ -30,12 +37,62 @@ func (r *Repo) AnswerComment(ctx context.Context, text string, authorID, postID,
 	const query = `
 		INSERT INTO comments (post_id, author_id, parent_id, text)
 		VALUES ($1, $2, $3, $4)
-		RETURNING id, post_id, author_id, parent_id, text, created_at;
+		RETURNING id, post_id, author_id, parent_id, text, created_at, edited_at, deleted_at;
 	`
 
 	return r.exec(ctx, query, postID, authorID, commentID, text)
 }
 
+func (r *Repo) GetCommentByID(ctx context.Context, commentID uuid.UUID) (*models.Comment, error) {
+	const query = `
+		SELECT id, post_id, author_id, parent_id, text, created_at, edited_at, deleted_at
+		FROM comments
+		WHERE id = $1;
+	`
+
+	return r.exec(ctx, query, commentID)
+}
+
+func (r *Repo) GetCommentsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Comment, error) {
+	const query = `
+		SELECT id, post_id, author_id, parent_id, text, created_at, edited_at, deleted_at
+		FROM comments
+		WHERE id = ANY($1);
+	`
+
+	rows, err := r.pool.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.collectRows(rows, len(ids))
+}
+
+func (r *Repo) UpdateCommentText(ctx context.Context, commentID uuid.UUID, text string) (*models.Comment, error) {
+	const query = `
+		UPDATE comments
+		SET text = $2, edited_at = now()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id, post_id, author_id, parent_id, text, created_at, edited_at, deleted_at;
+	`
+
+	return r.exec(ctx, query, commentID, text)
+}
+
+// SoftDeleteComment помечает комментарий удалённым, не трогая строку:
+// ответы продолжают ссылаться на него через parent_id.
+func (r *Repo) SoftDeleteComment(ctx context.Context, commentID uuid.UUID) (*models.Comment, error) {
+	const query = `
+		UPDATE comments
+		SET text = '', deleted_at = COALESCE(deleted_at, now())
+		WHERE id = $1
+		RETURNING id, post_id, author_id, parent_id, text, created_at, edited_at, deleted_at;
+	`
+
+	return r.exec(ctx, query, commentID)
+}
+
 func (r *Repo) exec(ctx context.Context, query string, args ...any) (*models.Comment, error) {
 	var c models.Comment
 	var parentID *uuid.UUID
@@ -47,8 +104,22 @@ func (r *Repo) exec(ctx context.Context, query string, args ...any) (*models.Com
 		&parentID,
 		&c.Text,
 		&c.CreatedAt,
+		&c.EditedAt,
+		&c.DeletedAt,
 	)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repositories.ErrCommentNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+			switch pgErr.ConstraintName {
+			case "comments_parent_fk":
+				return nil, repositories.ErrParentNotFound
+			case "comments_post_fk":
+				return nil, repositories.ErrPostNotFound
+			}
+		}
 		return nil, err
 	}
 
@@ -58,7 +129,7 @@ func (r *Repo) exec(ctx context.Context, query string, args ...any) (*models.Com
 
 func (r *Repo) GetCommentsPage(ctx context.Context, postID uuid.UUID, parentID *uuid.UUID, limit int, afterCreatedAt *time.Time, afterID *uuid.UUID) ([]*models.Comment, error) {
 	const query = `
-		SELECT id, post_id, author_id, parent_id, text, created_at
+		SELECT id, post_id, author_id, parent_id, text, created_at, edited_at, deleted_at
 		FROM comments
 		WHERE
 			post_id = $1
@@ -82,7 +153,38 @@ func (r *Repo) GetCommentsPage(ctx context.Context, postID uuid.UUID, parentID *
 	}
 	defer rows.Close()
 
-	comments := make([]*models.Comment, 0, limit)
+	return r.collectRows(rows, limit)
+}
+
+// GetCommentsSince возвращает комментарии новее курсора от старых к новым.
+// parentID == nil — все комментарии поста, включая ответы.
+func (r *Repo) GetCommentsSince(ctx context.Context, postID uuid.UUID, parentID *uuid.UUID, limit int, afterCreatedAt *time.Time, afterID *uuid.UUID) ([]*models.Comment, error) {
+	const query = `
+		SELECT id, post_id, author_id, parent_id, text, created_at, edited_at, deleted_at
+		FROM comments
+		WHERE
+			post_id = $1
+			AND ($2::uuid IS NULL OR parent_id = $2::uuid)
+			AND (
+				($3::timestamptz IS NULL AND $4::uuid IS NULL)
+				OR
+				(created_at, id) > ($3::timestamptz, $4::uuid)
+			)
+		ORDER BY created_at, id
+		LIMIT $5;
+	`
+
+	rows, err := r.pool.Query(ctx, query, postID, parentID, afterCreatedAt, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.collectRows(rows, limit)
+}
+
+func (r *Repo) collectRows(rows pgx.Rows, capacity int) ([]*models.Comment, error) {
+	comments := make([]*models.Comment, 0, capacity)
 
 	for rows.Next() {
 		var c models.Comment
@@ -95,6 +197,8 @@ func (r *Repo) GetCommentsPage(ctx context.Context, postID uuid.UUID, parentID *
 			&pID,
 			&c.Text,
 			&c.CreatedAt,
+			&c.EditedAt,
+			&c.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,14 @@
+// Package repositories содержит ошибки, общие для всех хранилищ: сервисы
+// проверяют их, не зная, postgres или memory под ними.
+package repositories
+
+import "errors"
+
+var (
+	ErrUserNotFound    = errors.New("user not found")
+	ErrLoginTaken      = errors.New("login is already taken")
+	ErrPostNotFound    = errors.New("post not found")
+	ErrCommentNotFound = errors.New("comment not found")
+	ErrParentNotFound  = errors.New("parent comment not found")
+	ErrSessionNotFound = errors.New("session not found")
+)
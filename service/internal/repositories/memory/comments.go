@@ -2,16 +2,15 @@ package memory
 
 import (
 	"context"
-	"errors"
+	"slices"
 	"sort"
 	"time"
 
 	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
 	"github.com/google/uuid"
 )
 
-var ErrParentNotFound = errors.New("parent comment not found")
-
 type CommentRepo struct {
 	store *Store
 }
@@ -40,7 +39,7 @@ func (r *CommentRepo) AnswerComment(ctx context.Context, text string, authorID,
 	defer r.store.mu.Unlock()
 
 	if _, ok := r.store.comments[commentID]; !ok {
-		return nil, ErrParentNotFound
+		return nil, repositories.ErrParentNotFound
 	}
 
 	parentID := commentID
@@ -88,6 +87,92 @@ func (r *CommentRepo) GetCommentsPage(ctx context.Context, postID uuid.UUID, par
 	return comments, nil
 }
 
+func (r *CommentRepo) GetCommentsSince(ctx context.Context, postID uuid.UUID, parentID *uuid.UUID, limit int, afterCreatedAt *time.Time, afterID *uuid.UUID) ([]*models.Comment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	comments := make([]*models.Comment, 0, len(r.store.comments))
+	for _, c := range r.store.comments {
+		if c.PostID != postID {
+			continue
+		}
+		if parentID != nil && (c.ParentCommentID == nil || *c.ParentCommentID != *parentID) {
+			continue
+		}
+		if afterCreatedAt != nil && afterID != nil && !commentBefore(*afterCreatedAt, *afterID, &c.CreatedAt, &c.ID) {
+			continue
+		}
+		comments = append(comments, copyComment(c))
+	}
+
+	// от старых к новым — обратный порядок sortComments
+	sortComments(comments)
+	slices.Reverse(comments)
+	if len(comments) > limit {
+		comments = comments[:limit]
+	}
+
+	return comments, nil
+}
+
+func (r *CommentRepo) GetCommentByID(ctx context.Context, commentID uuid.UUID) (*models.Comment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	c, ok := r.store.comments[commentID]
+	if !ok {
+		return nil, repositories.ErrCommentNotFound
+	}
+	return copyComment(c), nil
+}
+
+func (r *CommentRepo) GetCommentsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Comment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	out := make([]*models.Comment, 0, len(ids))
+	for _, id := range ids {
+		c, ok := r.store.comments[id]
+		if !ok {
+			continue
+		}
+		out = append(out, copyComment(c))
+	}
+	return out, nil
+}
+
+func (r *CommentRepo) UpdateCommentText(ctx context.Context, commentID uuid.UUID, text string) (*models.Comment, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	c, ok := r.store.comments[commentID]
+	if !ok || c.DeletedAt != nil {
+		return nil, repositories.ErrCommentNotFound
+	}
+
+	now := time.Now().UTC()
+	c.Text = text
+	c.EditedAt = &now
+	return copyComment(c), nil
+}
+
+func (r *CommentRepo) SoftDeleteComment(ctx context.Context, commentID uuid.UUID) (*models.Comment, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	c, ok := r.store.comments[commentID]
+	if !ok {
+		return nil, repositories.ErrCommentNotFound
+	}
+
+	if c.DeletedAt == nil {
+		now := time.Now().UTC()
+		c.DeletedAt = &now
+	}
+	c.Text = ""
+	return copyComment(c), nil
+}
+
 func sortComments(items []*models.Comment) {
 	sort.Slice(items, func(i, j int) bool {
 		if items[i].CreatedAt.Equal(items[j].CreatedAt) {
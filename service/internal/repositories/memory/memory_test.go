@@ -6,39 +6,31 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Parnishkaspb/ozon_posts/internal/auth"
+	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 )
 
-func TestUserRepo_GetUserByLoginPassword(t *testing.T) {
+func TestUserRepo_GetUserByLogin(t *testing.T) {
 	repo := NewUserRepo(NewStore())
 	ctx := context.Background()
 
 	tests := []struct {
-		name     string
-		login    string
-		password string
-		wantErr  bool
+		name    string
+		login   string
+		wantErr error
 	}{
-		{
-			name:     "valid seeded user",
-			login:    "Ivan",
-			password: "MoscowNeverSleep",
-		},
-		{
-			name:     "wrong password",
-			login:    "Ivan",
-			password: "MoscowEveryNightSleep",
-			wantErr:  true,
-		},
+		{name: "valid seeded user", login: "Ivan"},
+		{name: "unknown login", login: "Petr", wantErr: repositories.ErrUserNotFound},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u, err := repo.GetUserByLoginPassword(ctx, tt.login, tt.password)
-			if tt.wantErr {
-				if err == nil {
-					t.Fatalf("expected error")
+			u, err := repo.GetUserByLogin(ctx, tt.login)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
 				}
 				return
 			}
@@ -48,10 +40,47 @@ func TestUserRepo_GetUserByLoginPassword(t *testing.T) {
 			if u == nil || u.Login != "Ivan" {
 				t.Fatalf("expected seeded user")
 			}
+			if u.Password == "MoscowNeverSleep" || !auth.CheckPassword(u.Password, "MoscowNeverSleep") {
+				t.Fatalf("seeded password must be stored as bcrypt hash")
+			}
 		})
 	}
 }
 
+func TestUserRepo_GetUserByID(t *testing.T) {
+	repo := NewUserRepo(NewStore())
+	ctx := context.Background()
+
+	seeded, err := repo.GetUserByLogin(ctx, "Ivan")
+	if err != nil {
+		t.Fatalf("get seeded user: %v", err)
+	}
+	if u, err := repo.GetUserByID(ctx, seeded.ID); err != nil || u.ID != seeded.ID {
+		t.Fatalf("get by id: %v, %v", u, err)
+	}
+	if _, err := repo.GetUserByID(ctx, uuid.New()); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Fatalf("expected %v, got %v", repositories.ErrUserNotFound, err)
+	}
+}
+
+func TestUserRepo_CreateUser(t *testing.T) {
+	repo := NewUserRepo(NewStore())
+	ctx := context.Background()
+
+	u, err := repo.CreateUser(ctx, "Petr", "hash", "Пётр", "Первый")
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	got, err := repo.GetUserByLogin(ctx, "Petr")
+	if err != nil || got.ID != u.ID {
+		t.Fatalf("created user not found: %v", err)
+	}
+
+	if _, err := repo.CreateUser(ctx, "Ivan", "hash", "Иван", "Второй"); !errors.Is(err, repositories.ErrLoginTaken) {
+		t.Fatalf("expected %v, got %v", repositories.ErrLoginTaken, err)
+	}
+}
+
 func TestPostRepo_CreateAndPage(t *testing.T) {
 	store := NewStore()
 	repo := NewPostRepo(store)
@@ -99,11 +128,75 @@ func TestPostRepo_CreateAndPage(t *testing.T) {
 	}
 }
 
+func TestPostRepo_GetPostsByIDs(t *testing.T) {
+	repo := NewPostRepo(NewStore())
+	ctx := context.Background()
+
+	first, err := repo.CreatePost(ctx, uuid.New(), "first", false)
+	if err != nil {
+		t.Fatalf("create first: %v", err)
+	}
+	second, err := repo.CreatePost(ctx, uuid.New(), "second", false)
+	if err != nil {
+		t.Fatalf("create second: %v", err)
+	}
+
+	got, err := repo.GetPostsByIDs(ctx, []uuid.UUID{second.ID, uuid.New(), first.ID})
+	if err != nil {
+		t.Fatalf("get posts by ids: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != second.ID || got[1].ID != first.ID {
+		t.Fatalf("unexpected posts")
+	}
+}
+
 func TestPostRepo_WithoutCommentNotFound(t *testing.T) {
 	repo := NewPostRepo(NewStore())
 	_, err := repo.WithoutComment(context.Background(), uuid.New())
-	if !errors.Is(err, pgx.ErrNoRows) {
-		t.Fatalf("expected pgx.ErrNoRows, got %v", err)
+	if !errors.Is(err, repositories.ErrPostNotFound) {
+		t.Fatalf("expected %v, got %v", repositories.ErrPostNotFound, err)
+	}
+}
+
+func TestPostRepo_UpdateAndDelete(t *testing.T) {
+	store := NewStore()
+	posts := NewPostRepo(store)
+	comments := NewCommentRepo(store)
+	ctx := context.Background()
+
+	post, err := posts.CreatePost(ctx, uuid.New(), "tpyo", true)
+	if err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+	if _, err := comments.CreateComment(ctx, "comment", uuid.New(), post.ID); err != nil {
+		t.Fatalf("create comment: %v", err)
+	}
+
+	text := "typo"
+	updated, err := posts.UpdatePost(ctx, post.ID, &text, nil)
+	if err != nil {
+		t.Fatalf("update post: %v", err)
+	}
+	if updated.Text != text || !updated.WithoutComment || updated.UpdatedAt.Before(post.UpdatedAt) {
+		t.Fatalf("unexpected updated post: %+v", updated)
+	}
+
+	if err := posts.DeletePost(ctx, post.ID); err != nil {
+		t.Fatalf("delete post: %v", err)
+	}
+	if _, err := posts.GetPostsByID(ctx, post.ID.String()); !errors.Is(err, repositories.ErrPostNotFound) {
+		t.Fatalf("expected deleted post, got %v", err)
+	}
+	items, err := comments.GetCommentsPage(ctx, post.ID, nil, 10, nil, nil)
+	if err != nil || len(items) != 0 {
+		t.Fatalf("expected comments removed with post, got %d (%v)", len(items), err)
+	}
+
+	if err := posts.DeletePost(ctx, post.ID); !errors.Is(err, repositories.ErrPostNotFound) {
+		t.Fatalf("expected %v, got %v", repositories.ErrPostNotFound, err)
+	}
+	if _, err := posts.UpdatePost(ctx, post.ID, &text, nil); !errors.Is(err, repositories.ErrPostNotFound) {
+		t.Fatalf("expected %v, got %v", repositories.ErrPostNotFound, err)
 	}
 }
 
@@ -147,3 +240,156 @@ func TestCommentRepo_CreateAndReplies(t *testing.T) {
 		t.Fatalf("reply parent mismatch")
 	}
 }
+
+func TestCommentRepo_GetCommentsSince(t *testing.T) {
+	repo := NewCommentRepo(NewStore())
+	ctx := context.Background()
+	postID := uuid.New()
+	author := uuid.New()
+
+	root, err := repo.CreateComment(ctx, "root", author, postID)
+	if err != nil {
+		t.Fatalf("create root: %v", err)
+	}
+	reply, err := repo.AnswerComment(ctx, "reply", author, postID, root.ID)
+	if err != nil {
+		t.Fatalf("answer root: %v", err)
+	}
+	second, err := repo.CreateComment(ctx, "second", author, postID)
+	if err != nil {
+		t.Fatalf("create second: %v", err)
+	}
+	if _, err := repo.CreateComment(ctx, "other post", author, uuid.New()); err != nil {
+		t.Fatalf("create other: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		parentID *uuid.UUID
+		after    *models.Comment
+		want     []uuid.UUID
+	}{
+		{name: "whole post", want: []uuid.UUID{root.ID, reply.ID, second.ID}},
+		{name: "after root", after: root, want: []uuid.UUID{reply.ID, second.ID}},
+		{name: "replies after root", parentID: &root.ID, after: root, want: []uuid.UUID{reply.ID}},
+		{name: "after last", after: second, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var afterCreatedAt *time.Time
+			var afterID *uuid.UUID
+			if tt.after != nil {
+				afterCreatedAt, afterID = &tt.after.CreatedAt, &tt.after.ID
+			}
+
+			items, err := repo.GetCommentsSince(ctx, postID, tt.parentID, 10, afterCreatedAt, afterID)
+			if err != nil {
+				t.Fatalf("get comments since: %v", err)
+			}
+			if len(items) != len(tt.want) {
+				t.Fatalf("expected %d comments, got %d", len(tt.want), len(items))
+			}
+			for i, id := range tt.want {
+				if items[i].ID != id {
+					t.Fatalf("item %d: expected %s, got %s", i, id, items[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestCommentRepo_EditAndSoftDelete(t *testing.T) {
+	repo := NewCommentRepo(NewStore())
+	ctx := context.Background()
+	postID := uuid.New()
+	author := uuid.New()
+
+	root, err := repo.CreateComment(ctx, "root", author, postID)
+	if err != nil {
+		t.Fatalf("create root: %v", err)
+	}
+	reply, err := repo.AnswerComment(ctx, "reply", author, postID, root.ID)
+	if err != nil {
+		t.Fatalf("answer root: %v", err)
+	}
+
+	edited, err := repo.UpdateCommentText(ctx, root.ID, "root v2")
+	if err != nil {
+		t.Fatalf("edit root: %v", err)
+	}
+	if edited.Text != "root v2" || edited.EditedAt == nil {
+		t.Fatalf("unexpected edited comment: %+v", edited)
+	}
+
+	deleted, err := repo.SoftDeleteComment(ctx, root.ID)
+	if err != nil {
+		t.Fatalf("delete root: %v", err)
+	}
+	if deleted.DeletedAt == nil || deleted.Text != "" {
+		t.Fatalf("unexpected tombstone: %+v", deleted)
+	}
+
+	if _, err := repo.UpdateCommentText(ctx, root.ID, "again"); !errors.Is(err, repositories.ErrCommentNotFound) {
+		t.Fatalf("expected %v, got %v", repositories.ErrCommentNotFound, err)
+	}
+
+	roots, err := repo.GetCommentsPage(ctx, postID, nil, 10, nil, nil)
+	if err != nil || len(roots) != 1 || roots[0].DeletedAt == nil {
+		t.Fatalf("tombstone must stay in the thread: %v", err)
+	}
+	replies, err := repo.GetCommentsPage(ctx, postID, &root.ID, 10, nil, nil)
+	if err != nil || len(replies) != 1 || replies[0].ID != reply.ID {
+		t.Fatalf("replies must stay reachable: %v", err)
+	}
+}
+
+func TestSessionRepo_RotateAndRevoke(t *testing.T) {
+	repo := NewSessionRepo(NewStore())
+	ctx := context.Background()
+	userID := uuid.New()
+
+	s, err := repo.CreateSession(ctx, userID, "h1", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	rotated, err := repo.RotateSession(ctx, "h1", "h2", time.Now().Add(time.Hour))
+	if err != nil || rotated.ID != s.ID || rotated.RefreshHash != "h2" {
+		t.Fatalf("rotate session: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{name: "rotate stale hash", run: func() error {
+			_, err := repo.RotateSession(ctx, "h1", "h3", time.Now().Add(time.Hour))
+			return err
+		}},
+		{name: "revoke unknown hash", run: func() error {
+			_, err := repo.RevokeSession(ctx, "unknown")
+			return err
+		}},
+		{name: "rotate after revoke", run: func() error {
+			if _, err := repo.RevokeSession(ctx, "h2"); err != nil {
+				t.Fatalf("revoke session: %v", err)
+			}
+			_, err := repo.RotateSession(ctx, "h2", "h3", time.Now().Add(time.Hour))
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, repositories.ErrSessionNotFound) {
+				t.Fatalf("expected %v, got %v", repositories.ErrSessionNotFound, err)
+			}
+		})
+	}
+
+	got, err := repo.GetSessionByID(ctx, s.ID)
+	if err != nil || got.RevokedAt == nil {
+		t.Fatalf("expected revoked session: %v", err)
+	}
+}
@@ -6,8 +6,8 @@ import (
 	"time"
 
 	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 )
 
 type PostRepo struct {
@@ -58,18 +58,33 @@ func (r *PostRepo) GetPostsByID(ctx context.Context, id string) (*models.Post, e
 
 	p, ok := r.store.posts[postID]
 	if !ok {
-		return nil, pgx.ErrNoRows
+		return nil, repositories.ErrPostNotFound
 	}
 	return copyPost(p), nil
 }
 
+func (r *PostRepo) GetPostsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Post, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	out := make([]*models.Post, 0, len(ids))
+	for _, id := range ids {
+		p, ok := r.store.posts[id]
+		if !ok {
+			continue
+		}
+		out = append(out, copyPost(p))
+	}
+	return out, nil
+}
+
 func (r *PostRepo) WithoutComment(ctx context.Context, postID uuid.UUID) (bool, error) {
 	r.store.mu.RLock()
 	defer r.store.mu.RUnlock()
 
 	p, ok := r.store.posts[postID]
 	if !ok {
-		return false, pgx.ErrNoRows
+		return false, repositories.ErrPostNotFound
 	}
 	return p.WithoutComment, nil
 }
@@ -95,6 +110,44 @@ func (r *PostRepo) GetPostsPage(ctx context.Context, first int, afterCreatedAt *
 	return posts, hasNext, nil
 }
 
+func (r *PostRepo) UpdatePost(ctx context.Context, postID uuid.UUID, text *string, withoutComment *bool) (*models.Post, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	p, ok := r.store.posts[postID]
+	if !ok {
+		return nil, repositories.ErrPostNotFound
+	}
+	if text != nil {
+		p.Text = *text
+	}
+	if withoutComment != nil {
+		p.WithoutComment = *withoutComment
+	}
+	p.UpdatedAt = time.Now().UTC()
+
+	return copyPost(p), nil
+}
+
+func (r *PostRepo) DeletePost(ctx context.Context, postID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.posts[postID]; !ok {
+		return repositories.ErrPostNotFound
+	}
+	delete(r.store.posts, postID)
+
+	// как и ON DELETE CASCADE в postgres: комментарии поста удаляются вместе с ним
+	for id, c := range r.store.comments {
+		if c.PostID == postID {
+			delete(r.store.comments, id)
+		}
+	}
+
+	return nil
+}
+
 func sortPosts(posts []*models.Post) {
 	sort.Slice(posts, func(i, j int) bool {
 		if posts[i].CreatedAt.Equal(posts[j].CreatedAt) {
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
+	"github.com/google/uuid"
+)
+
+type SessionRepo struct {
+	store *Store
+}
+
+func NewSessionRepo(store *Store) *SessionRepo {
+	return &SessionRepo{store: store}
+}
+
+func (r *SessionRepo) CreateSession(ctx context.Context, userID uuid.UUID, refreshHash string, expiresAt time.Time) (*models.Session, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := &models.Session{
+		ID:          uuid.New(),
+		UserID:      userID,
+		RefreshHash: refreshHash,
+		CreatedAt:   time.Now().UTC(),
+		ExpiresAt:   expiresAt,
+	}
+	r.store.sessions[s.ID] = copySession(s)
+	return s, nil
+}
+
+func (r *SessionRepo) GetSessionByID(ctx context.Context, sessionID uuid.UUID) (*models.Session, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s, ok := r.store.sessions[sessionID]
+	if !ok {
+		return nil, repositories.ErrSessionNotFound
+	}
+	return copySession(s), nil
+}
+
+func (r *SessionRepo) RotateSession(ctx context.Context, oldHash, newHash string, expiresAt time.Time) (*models.Session, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := r.findByHash(oldHash)
+	if s == nil || s.RevokedAt != nil || !s.ExpiresAt.After(time.Now()) {
+		return nil, repositories.ErrSessionNotFound
+	}
+
+	s.RefreshHash = newHash
+	s.ExpiresAt = expiresAt
+	return copySession(s), nil
+}
+
+func (r *SessionRepo) RevokeSession(ctx context.Context, refreshHash string) (*models.Session, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := r.findByHash(refreshHash)
+	if s == nil {
+		return nil, repositories.ErrSessionNotFound
+	}
+	if s.RevokedAt == nil {
+		now := time.Now().UTC()
+		s.RevokedAt = &now
+	}
+	return copySession(s), nil
+}
+
+// findByHash вызывается под блокировкой store.mu.
+func (r *SessionRepo) findByHash(refreshHash string) *models.Session {
+	for _, s := range r.store.sessions {
+		if s.RefreshHash == refreshHash {
+			return s
+		}
+	}
+	return nil
+}
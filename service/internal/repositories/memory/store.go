@@ -8,11 +8,15 @@ import (
 	"github.com/google/uuid"
 )
 
+// bcrypt-хэш пароля "MoscowNeverSleep" для тестового пользователя
+const seedPasswordHash = "$2a$10$7Ce/ybZLwTY67T68EGikm.bAspRDAx8d9y5vAVyTKvu4QfzkZOPt2"
+
 type Store struct {
 	mu       sync.RWMutex
 	users    map[uuid.UUID]*models.User
 	posts    map[uuid.UUID]*models.Post
 	comments map[uuid.UUID]*models.Comment
+	sessions map[uuid.UUID]*models.Session
 }
 
 func NewStore() *Store {
@@ -20,13 +24,14 @@ func NewStore() *Store {
 		users:    make(map[uuid.UUID]*models.User),
 		posts:    make(map[uuid.UUID]*models.Post),
 		comments: make(map[uuid.UUID]*models.Comment),
+		sessions: make(map[uuid.UUID]*models.Session),
 	}
 
 	seedID := uuid.New()
 	s.users[seedID] = &models.User{
 		ID:       seedID,
 		Login:    "Ivan",
-		Password: "MoscowNeverSleep",
+		Password: seedPasswordHash,
 		Name:     "Иван",
 		Surname:  "Грозный",
 	}
@@ -59,6 +64,26 @@ func copyComment(c *models.Comment) *models.Comment {
 		pid := *c.ParentCommentID
 		cp.ParentCommentID = &pid
 	}
+	if c.EditedAt != nil {
+		t := *c.EditedAt
+		cp.EditedAt = &t
+	}
+	if c.DeletedAt != nil {
+		t := *c.DeletedAt
+		cp.DeletedAt = &t
+	}
+	return &cp
+}
+
+func copySession(s *models.Session) *models.Session {
+	if s == nil {
+		return nil
+	}
+	cp := *s
+	if s.RevokedAt != nil {
+		t := *s.RevokedAt
+		cp.RevokedAt = &t
+	}
 	return &cp
 }
 
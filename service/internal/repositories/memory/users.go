@@ -4,7 +4,7 @@ import (
 	"context"
 
 	"github.com/Parnishkaspb/ozon_posts/internal/models"
-	pgusers "github.com/Parnishkaspb/ozon_posts/internal/repositories/users"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
 	"github.com/google/uuid"
 )
 
@@ -33,7 +33,7 @@ func (r *UserRepo) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.U
 
 	u, ok := r.store.users[userID]
 	if !ok {
-		return nil, nil
+		return nil, repositories.ErrUserNotFound
 	}
 	return copyUser(u), nil
 }
@@ -57,15 +57,36 @@ func (r *UserRepo) GetUsersByIDs(ctx context.Context, ids []string) ([]*models.U
 	return out, nil
 }
 
-func (r *UserRepo) GetUserByLoginPassword(ctx context.Context, login, password string) (*models.User, error) {
+func (r *UserRepo) GetUserByLogin(ctx context.Context, login string) (*models.User, error) {
 	r.store.mu.RLock()
 	defer r.store.mu.RUnlock()
 
 	for _, u := range r.store.users {
-		if u.Login == login && u.Password == password {
+		if u.Login == login {
 			return copyUser(u), nil
 		}
 	}
 
-	return nil, pgusers.ErrUserNotFound
+	return nil, repositories.ErrUserNotFound
+}
+
+func (r *UserRepo) CreateUser(ctx context.Context, login, passwordHash, name, surname string) (*models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, u := range r.store.users {
+		if u.Login == login {
+			return nil, repositories.ErrLoginTaken
+		}
+	}
+
+	u := &models.User{
+		ID:       uuid.New(),
+		Login:    login,
+		Password: passwordHash,
+		Name:     name,
+		Surname:  surname,
+	}
+	r.store.users[u.ID] = copyUser(u)
+	return u, nil
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -57,12 +58,36 @@ func (r *Repo) GetPostsByID(ctx context.Context, id string) (*models.Post, error
 		&post.UpdatedAt,
 	)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repositories.ErrPostNotFound
+		}
 		return nil, fmt.Errorf("Query: %w", err)
 	}
 
 	return &post, nil
 }
 
+func (r *Repo) GetPostsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Post, error) {
+	const query = `
+		SELECT id, author_id, text, without_comment, created_at, updated_at
+		FROM posts WHERE id = ANY($1)
+	`
+
+	rows, err := r.pool.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("Query: %w", err)
+	}
+	defer rows.Close()
+
+	posts, err := r.collectRows(rows)
+
+	if err != nil {
+		return nil, fmt.Errorf("CollectRows: %w", err)
+	}
+
+	return posts, nil
+}
+
 func (r *Repo) GetPostsByUserID(ctx context.Context, authorID uuid.UUID) ([]*models.Post, error) {
 	const query = `
 		SELECT id, author_id, text, without_comment, created_at, updated_at
@@ -174,8 +199,52 @@ func (r *Repo) WithoutComment(ctx context.Context, postID uuid.UUID) (bool, erro
 	err := r.pool.QueryRow(ctx, query, postID).Scan(&withoutComment)
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		return false, pgx.ErrNoRows
+		return false, repositories.ErrPostNotFound
 	}
 
 	return withoutComment, err
 }
+
+func (r *Repo) UpdatePost(ctx context.Context, postID uuid.UUID, text *string, withoutComment *bool) (*models.Post, error) {
+	const query = `
+		UPDATE posts
+		SET
+			text = COALESCE($2, text),
+			without_comment = COALESCE($3, without_comment),
+			updated_at = now()
+		WHERE id = $1
+		RETURNING id, author_id, text, without_comment, created_at, updated_at
+	`
+
+	var post models.Post
+	err := r.pool.QueryRow(ctx, query, postID, text, withoutComment).Scan(
+		&post.ID,
+		&post.AuthorID,
+		&post.Text,
+		&post.WithoutComment,
+		&post.CreatedAt,
+		&post.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repositories.ErrPostNotFound
+		}
+		return nil, fmt.Errorf("QueryRow Scan: %w", err)
+	}
+
+	return &post, nil
+}
+
+func (r *Repo) DeletePost(ctx context.Context, postID uuid.UUID) error {
+	const query = `DELETE FROM posts WHERE id = $1`
+
+	tag, err := r.pool.Exec(ctx, query, postID)
+	if err != nil {
+		return fmt.Errorf("Exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repositories.ErrPostNotFound
+	}
+
+	return nil
+}
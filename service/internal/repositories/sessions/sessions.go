@@ -0,0 +1,86 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repo struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Repo {
+	return &Repo{pool: pool}
+}
+
+func (r *Repo) CreateSession(ctx context.Context, userID uuid.UUID, refreshHash string, expiresAt time.Time) (*models.Session, error) {
+	const query = `
+		INSERT INTO sessions (user_id, refresh_hash, expires_at)
+		VALUES ($1, $2, $3)
+		RETURNING id, user_id, refresh_hash, created_at, expires_at, revoked_at;
+	`
+
+	return r.exec(ctx, query, userID, refreshHash, expiresAt)
+}
+
+func (r *Repo) GetSessionByID(ctx context.Context, sessionID uuid.UUID) (*models.Session, error) {
+	const query = `
+		SELECT id, user_id, refresh_hash, created_at, expires_at, revoked_at
+		FROM sessions
+		WHERE id = $1;
+	`
+
+	return r.exec(ctx, query, sessionID)
+}
+
+// RotateSession атомарно заменяет refresh-токен живой сессии:
+// старый токен после этого больше не принимается.
+func (r *Repo) RotateSession(ctx context.Context, oldHash, newHash string, expiresAt time.Time) (*models.Session, error) {
+	const query = `
+		UPDATE sessions
+		SET refresh_hash = $2, expires_at = $3
+		WHERE refresh_hash = $1 AND revoked_at IS NULL AND expires_at > now()
+		RETURNING id, user_id, refresh_hash, created_at, expires_at, revoked_at;
+	`
+
+	return r.exec(ctx, query, oldHash, newHash, expiresAt)
+}
+
+func (r *Repo) RevokeSession(ctx context.Context, refreshHash string) (*models.Session, error) {
+	const query = `
+		UPDATE sessions
+		SET revoked_at = COALESCE(revoked_at, now())
+		WHERE refresh_hash = $1
+		RETURNING id, user_id, refresh_hash, created_at, expires_at, revoked_at;
+	`
+
+	return r.exec(ctx, query, refreshHash)
+}
+
+func (r *Repo) exec(ctx context.Context, query string, args ...any) (*models.Session, error) {
+	var s models.Session
+
+	err := r.pool.QueryRow(ctx, query, args...).Scan(
+		&s.ID,
+		&s.UserID,
+		&s.RefreshHash,
+		&s.CreatedAt,
+		&s.ExpiresAt,
+		&s.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repositories.ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	return &s, nil
+}
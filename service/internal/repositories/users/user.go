@@ -5,14 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-var (
-	ErrUserNotFound = errors.New("user not found")
-)
+// SQLSTATE unique_violation
+const uniqueViolation = "23505"
 
 type Repo struct {
 	pool *pgxpool.Pool
@@ -43,13 +44,13 @@ func (r *Repo) GetAllUsers(ctx context.Context) ([]*models.User, error) {
 }
 
 func (r *Repo) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
-	const query = `SELECT id, name, surname FROM users WHERE id = $1`
+	const query = `SELECT id, login, name, surname FROM users WHERE id = $1`
 
 	u := new(models.User)
-	err := r.pool.QueryRow(ctx, query, userID).Scan(&u.ID, &u.Name, &u.Surname)
+	err := r.pool.QueryRow(ctx, query, userID).Scan(&u.ID, &u.Login, &u.Name, &u.Surname)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, nil
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repositories.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("QueryRow Scan: %w", err)
 	}
@@ -83,21 +84,43 @@ func (r *Repo) collectRows(rows pgx.Rows) ([]*models.User, error) {
 	return users, err
 }
 
-func (r *Repo) GetUserByLoginPassword(ctx context.Context, login, password string) (*models.User, error) {
+func (r *Repo) GetUserByLogin(ctx context.Context, login string) (*models.User, error) {
 	var user models.User
-	const query = `SELECT id, name, surname FROM users WHERE login = $1 AND password = $2`
+	const query = `SELECT id, login, password, name, surname FROM users WHERE login = $1`
 
-	err := r.pool.QueryRow(ctx, query, login, password).Scan(
+	err := r.pool.QueryRow(ctx, query, login).Scan(
 		&user.ID,
+		&user.Login,
+		&user.Password,
 		&user.Name,
 		&user.Surname,
 	)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrUserNotFound
+			return nil, repositories.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("QueryRow Scan: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (r *Repo) CreateUser(ctx context.Context, login, passwordHash, name, surname string) (*models.User, error) {
+	const query = `
+		INSERT INTO users (login, password, name, surname)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	user := models.User{Login: login, Name: name, Surname: surname}
+	err := r.pool.QueryRow(ctx, query, login, passwordHash, name, surname).Scan(&user.ID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return nil, repositories.ErrLoginTaken
 		}
-		return nil, errors.New("QueryRow Scan")
+		return nil, fmt.Errorf("QueryRow Scan: %w", err)
 	}
 
 	return &user, nil
@@ -5,9 +5,12 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"github.com/Parnishkaspb/ozon_posts/internal/events"
 	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
 	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -24,17 +27,33 @@ var (
 	ErrInvalidCursor     = errors.New("invalid cursor")
 	ErrInvalidParentID   = errors.New("parentID is invalid")
 	ErrBadFirst          = errors.New("first must be > 0")
+	ErrCommentNotFound   = errors.New("comment not found")
+	ErrCommentDeleted    = errors.New("comment is deleted")
+	ErrNotCommentAuthor  = errors.New("only the author can modify the comment")
+	ErrInvalidCommentID  = errors.New("commentID is invalid")
+	ErrTooManyIDs        = errors.New("too many ids")
+	ErrPostNotFound      = errors.New("post not found")
+	ErrParentNotFound    = errors.New("parent comment not found")
 )
 
+// DeletedText подставляется вместо текста удалённого комментария.
+const DeletedText = "[deleted]"
+
 const (
 	defaultPageSize = 20
 	maxPageSize     = 100
+	maxIDsPerBatch  = 200
 )
 
 type CommentRepo interface {
 	CreateComment(ctx context.Context, text string, authorID, postID uuid.UUID) (*models.Comment, error)
 	AnswerComment(ctx context.Context, text string, authorID, postID, commentID uuid.UUID) (*models.Comment, error)
 	GetCommentsPage(ctx context.Context, postID uuid.UUID, parentID *uuid.UUID, limit int, afterCreatedAt *time.Time, afterID *uuid.UUID) ([]*models.Comment, error)
+	GetCommentsSince(ctx context.Context, postID uuid.UUID, parentID *uuid.UUID, limit int, afterCreatedAt *time.Time, afterID *uuid.UUID) ([]*models.Comment, error)
+	GetCommentByID(ctx context.Context, commentID uuid.UUID) (*models.Comment, error)
+	GetCommentsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Comment, error)
+	UpdateCommentText(ctx context.Context, commentID uuid.UUID, text string) (*models.Comment, error)
+	SoftDeleteComment(ctx context.Context, commentID uuid.UUID) (*models.Comment, error)
 }
 
 type PostRepo interface {
@@ -44,12 +63,14 @@ type PostRepo interface {
 type CommentService struct {
 	commentRepo CommentRepo
 	postRepo    PostRepo
+	events      events.Publisher
 }
 
-func New(comment CommentRepo, post PostRepo) *CommentService {
+func New(comment CommentRepo, post PostRepo, publisher events.Publisher) *CommentService {
 	return &CommentService{
 		commentRepo: comment,
 		postRepo:    post,
+		events:      publisher,
 	}
 }
 
@@ -85,6 +106,9 @@ func (s *CommentService) CommentCreate(ctx context.Context, text string, authorI
 
 	ok, err := s.postRepo.WithoutComment(ctx, postID)
 	if err != nil {
+		if errors.Is(err, repositories.ErrPostNotFound) {
+			return &models.Comment{}, ErrPostNotFound
+		}
 		return &models.Comment{}, err
 	}
 
@@ -92,7 +116,16 @@ func (s *CommentService) CommentCreate(ctx context.Context, text string, authorI
 		return &models.Comment{}, ErrCantWriteComment
 	}
 
-	return s.commentRepo.CreateComment(ctx, text, authorID, postID)
+	c, err := s.commentRepo.CreateComment(ctx, text, authorID, postID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrPostNotFound) {
+			return c, ErrPostNotFound
+		}
+		return c, err
+	}
+
+	s.publish(ctx, c, s.events.CommentCreated)
+	return c, nil
 }
 
 func (s *CommentService) CommentAnswer(ctx context.Context, text string, authorID, postID, commentID uuid.UUID) (*models.Comment, error) {
@@ -110,7 +143,100 @@ func (s *CommentService) CommentAnswer(ctx context.Context, text string, authorI
 		return &models.Comment{}, err
 	}
 
-	return s.commentRepo.AnswerComment(ctx, text, authorID, postID, commentID)
+	c, err := s.commentRepo.AnswerComment(ctx, text, authorID, postID, commentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrParentNotFound):
+			return c, ErrParentNotFound
+		case errors.Is(err, repositories.ErrPostNotFound):
+			return c, ErrPostNotFound
+		}
+		return c, err
+	}
+
+	s.publish(ctx, c, s.events.CommentCreated)
+	return c, nil
+}
+
+// publish оповещает подписчиков об изменении комментария. Комментарий уже
+// сохранён, поэтому сбой брокера не превращается в ошибку запроса.
+func (s *CommentService) publish(ctx context.Context, c *models.Comment, notify func(context.Context, *models.Comment) error) {
+	if err := notify(ctx, c); err != nil {
+		slog.ErrorContext(ctx, "publish comment", "comment_id", c.ID, "error", err)
+	}
+}
+
+func (s *CommentService) EditComment(ctx context.Context, commentID, authorID uuid.UUID, text string) (*models.Comment, error) {
+	text, err := s.normalizeAndValidateText(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireUUID(commentID, ErrCommentIDRequired); err != nil {
+		return nil, err
+	}
+	if err := s.requireUUID(authorID, ErrAuthorIDRequired); err != nil {
+		return nil, err
+	}
+
+	c, err := s.getOwnComment(ctx, commentID, authorID)
+	if err != nil {
+		return nil, err
+	}
+	if c.DeletedAt != nil {
+		return nil, ErrCommentDeleted
+	}
+
+	c, err = s.commentRepo.UpdateCommentText(ctx, commentID, text)
+	if err != nil {
+		if errors.Is(err, repositories.ErrCommentNotFound) {
+			return nil, ErrCommentDeleted
+		}
+		return nil, err
+	}
+
+	s.publish(ctx, c, s.events.CommentEdited)
+	return c, nil
+}
+
+// DeleteComment превращает комментарий в "надгробие": текст и автор скрываются,
+// но сам узел остаётся, чтобы ответы на него были доступны через replies.
+// Повторное удаление не является ошибкой.
+func (s *CommentService) DeleteComment(ctx context.Context, commentID, authorID uuid.UUID) (*models.Comment, error) {
+	if err := s.requireUUID(commentID, ErrCommentIDRequired); err != nil {
+		return nil, err
+	}
+	if err := s.requireUUID(authorID, ErrAuthorIDRequired); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getOwnComment(ctx, commentID, authorID); err != nil {
+		return nil, err
+	}
+
+	c, err := s.commentRepo.SoftDeleteComment(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrCommentNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+
+	s.publish(ctx, c, s.events.CommentDeleted)
+	return c, nil
+}
+
+func (s *CommentService) getOwnComment(ctx context.Context, commentID, authorID uuid.UUID) (*models.Comment, error) {
+	c, err := s.commentRepo.GetCommentByID(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrCommentNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	if c.AuthorID != authorID {
+		return nil, ErrNotCommentAuthor
+	}
+	return c, nil
 }
 
 func (s *CommentService) GetComments(ctx context.Context, req *servicepb.GetCommentsRequest) (*servicepb.GetCommentsResponse, error) {
@@ -175,7 +301,7 @@ func (s *CommentService) GetComments(ctx context.Context, req *servicepb.GetComm
 
 	out := make([]*servicepb.Comment, 0, len(items))
 	for _, c := range items {
-		out = append(out, toPB(c))
+		out = append(out, ToPB(c))
 	}
 
 	return &servicepb.GetCommentsResponse{
@@ -185,13 +311,123 @@ func (s *CommentService) GetComments(ctx context.Context, req *servicepb.GetComm
 	}, nil
 }
 
-func toPB(c *models.Comment) *servicepb.Comment {
+// GetCommentsSince отдаёт комментарии новее курсора after от старых к новым,
+// чтобы подписчик мог догнать пропущенное. Без parent_id — все уровни дерева.
+func (s *CommentService) GetCommentsSince(ctx context.Context, req *servicepb.GetCommentsSinceRequest) (*servicepb.GetCommentsSinceResponse, error) {
+	postID, err := uuid.Parse(req.GetPostId())
+	if err != nil {
+		return nil, ErrPostIDRequired
+	}
+
+	var parentID *uuid.UUID
+	if req.GetParentId() != "" {
+		p, err := uuid.Parse(req.GetParentId())
+		if err != nil {
+			return nil, ErrInvalidParentID
+		}
+		parentID = &p
+	}
+
+	first := int(req.GetFirst())
+	if first == 0 {
+		first = defaultPageSize
+	}
+	if first < 0 {
+		return nil, ErrBadFirst
+	}
+	if first > maxPageSize {
+		first = maxPageSize
+	}
+
+	var afterCreatedAt *time.Time
+	var afterID *uuid.UUID
+	if req.GetAfter() != "" {
+		t, id, err := parseCursor(req.GetAfter())
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		afterCreatedAt = &t
+		afterID = &id
+	}
+
+	items, err := s.commentRepo.GetCommentsSince(ctx, postID, parentID, first+1, afterCreatedAt, afterID)
+	if err != nil {
+		return nil, err
+	}
+
+	hasNext := len(items) > first
+	if hasNext {
+		items = items[:first]
+	}
+
+	endCursor := req.GetAfter()
+	if len(items) > 0 {
+		last := items[len(items)-1]
+		endCursor = makeCursor(last.CreatedAt, last.ID)
+	}
+
+	out := make([]*servicepb.Comment, 0, len(items))
+	for _, c := range items {
+		out = append(out, ToPB(c))
+	}
+
+	return &servicepb.GetCommentsSinceResponse{
+		Comments:    out,
+		EndCursor:   endCursor,
+		HasNextPage: hasNext,
+	}, nil
+}
+
+// GetCommentsByIDs возвращает комментарии в порядке запрошенных ids;
+// ненайденные id пропускаются, дубликаты схлопываются.
+func (s *CommentService) GetCommentsByIDs(ctx context.Context, req *servicepb.GetCommentsByIDsRequest) (*servicepb.GetCommentsByIDsResponse, error) {
+	ids := make([]uuid.UUID, 0, len(req.GetIds()))
+	seen := make(map[uuid.UUID]struct{}, len(req.GetIds()))
+	for _, raw := range req.GetIds() {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, ErrInvalidCommentID
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	if len(ids) > maxIDsPerBatch {
+		return nil, ErrTooManyIDs
+	}
+	if len(ids) == 0 {
+		return &servicepb.GetCommentsByIDsResponse{Comments: []*servicepb.Comment{}}, nil
+	}
+
+	items, err := s.commentRepo.GetCommentsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*models.Comment, len(items))
+	for _, c := range items {
+		byID[c.ID] = c
+	}
+
+	out := make([]*servicepb.Comment, 0, len(items))
+	for _, id := range ids {
+		if c, ok := byID[id]; ok {
+			out = append(out, ToPB(c))
+		}
+	}
+
+	return &servicepb.GetCommentsByIDsResponse{Comments: out}, nil
+}
+
+func ToPB(c *models.Comment) *servicepb.Comment {
 	parent := ""
 	if c.ParentCommentID != nil {
 		parent = c.ParentCommentID.String()
 	}
 
-	return &servicepb.Comment{
+	out := &servicepb.Comment{
 		Id:        c.ID.String(),
 		PostId:    c.PostID.String(),
 		AuthorId:  c.AuthorID.String(),
@@ -199,6 +435,16 @@ func toPB(c *models.Comment) *servicepb.Comment {
 		Text:      c.Text,
 		CreatedAt: timestamppb.New(c.CreatedAt),
 	}
+	if c.EditedAt != nil {
+		out.EditedAt = timestamppb.New(*c.EditedAt)
+	}
+	if c.DeletedAt != nil {
+		out.Deleted = true
+		out.Text = DeletedText
+		out.AuthorId = ""
+	}
+
+	return out
 }
 
 func makeCursor(createdAt time.Time, id uuid.UUID) string {
@@ -7,7 +7,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Parnishkaspb/ozon_posts/internal/events"
 	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
+	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
 	"github.com/google/uuid"
 )
 
@@ -16,6 +19,11 @@ type mockCommentRepo struct {
 	answerCalled bool
 	createFn     func(ctx context.Context, text string, authorID, postID uuid.UUID) (*models.Comment, error)
 	answerFn     func(ctx context.Context, text string, authorID, postID, commentID uuid.UUID) (*models.Comment, error)
+	byID         *models.Comment
+	byIDs        []*models.Comment
+	gotIDs       []uuid.UUID
+	updateCalled bool
+	deleteCalled bool
 }
 
 func (m *mockCommentRepo) CreateComment(ctx context.Context, text string, authorID, postID uuid.UUID) (*models.Comment, error) {
@@ -38,6 +46,62 @@ func (m *mockCommentRepo) GetCommentsPage(ctx context.Context, postID uuid.UUID,
 	return nil, nil
 }
 
+func (m *mockCommentRepo) GetCommentsSince(ctx context.Context, postID uuid.UUID, parentID *uuid.UUID, limit int, afterCreatedAt *time.Time, afterID *uuid.UUID) ([]*models.Comment, error) {
+	return nil, nil
+}
+
+func (m *mockCommentRepo) GetCommentByID(ctx context.Context, commentID uuid.UUID) (*models.Comment, error) {
+	if m.byID == nil {
+		return nil, repositories.ErrCommentNotFound
+	}
+	return m.byID, nil
+}
+
+func (m *mockCommentRepo) GetCommentsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Comment, error) {
+	m.gotIDs = ids
+	return m.byIDs, nil
+}
+
+func (m *mockCommentRepo) UpdateCommentText(ctx context.Context, commentID uuid.UUID, text string) (*models.Comment, error) {
+	m.updateCalled = true
+	c := *m.byID
+	c.Text = text
+	now := time.Now()
+	c.EditedAt = &now
+	return &c, nil
+}
+
+func (m *mockCommentRepo) SoftDeleteComment(ctx context.Context, commentID uuid.UUID) (*models.Comment, error) {
+	m.deleteCalled = true
+	c := *m.byID
+	now := time.Now()
+	c.DeletedAt = &now
+	return &c, nil
+}
+
+type mockPublisher struct {
+	events.Nop
+	created []*models.Comment
+	edited  []*models.Comment
+	deleted []*models.Comment
+	err     error
+}
+
+func (m *mockPublisher) CommentCreated(ctx context.Context, c *models.Comment) error {
+	m.created = append(m.created, c)
+	return m.err
+}
+
+func (m *mockPublisher) CommentEdited(ctx context.Context, c *models.Comment) error {
+	m.edited = append(m.edited, c)
+	return m.err
+}
+
+func (m *mockPublisher) CommentDeleted(ctx context.Context, c *models.Comment) error {
+	m.deleted = append(m.deleted, c)
+	return m.err
+}
+
 type mockPostRepo struct {
 	withoutComment bool
 	err            error
@@ -53,7 +117,7 @@ func TestCommentService_CommentCreate(t *testing.T) {
 	validPost := uuid.New()
 
 	t.Run("text required", func(t *testing.T) {
-		svc := New(&mockCommentRepo{}, &mockPostRepo{})
+		svc := New(&mockCommentRepo{}, &mockPostRepo{}, &mockPublisher{})
 		_, err := svc.CommentCreate(ctx, "   ", validAuthor, validPost)
 		if !errors.Is(err, ErrTextRequired) {
 			t.Fatalf("expected %v, got %v", ErrTextRequired, err)
@@ -61,7 +125,7 @@ func TestCommentService_CommentCreate(t *testing.T) {
 	})
 
 	t.Run("text too long", func(t *testing.T) {
-		svc := New(&mockCommentRepo{}, &mockPostRepo{})
+		svc := New(&mockCommentRepo{}, &mockPostRepo{}, &mockPublisher{})
 		_, err := svc.CommentCreate(ctx, strings.Repeat("a", 2001), validAuthor, validPost)
 		if !errors.Is(err, ErrMax2000Symbols) {
 			t.Fatalf("expected %v, got %v", ErrMax2000Symbols, err)
@@ -69,7 +133,7 @@ func TestCommentService_CommentCreate(t *testing.T) {
 	})
 
 	t.Run("author required", func(t *testing.T) {
-		svc := New(&mockCommentRepo{}, &mockPostRepo{})
+		svc := New(&mockCommentRepo{}, &mockPostRepo{}, &mockPublisher{})
 		_, err := svc.CommentCreate(ctx, "ok", uuid.Nil, validPost)
 		if !errors.Is(err, ErrAuthorIDRequired) {
 			t.Fatalf("expected %v, got %v", ErrAuthorIDRequired, err)
@@ -77,7 +141,7 @@ func TestCommentService_CommentCreate(t *testing.T) {
 	})
 
 	t.Run("post required", func(t *testing.T) {
-		svc := New(&mockCommentRepo{}, &mockPostRepo{})
+		svc := New(&mockCommentRepo{}, &mockPostRepo{}, &mockPublisher{})
 		_, err := svc.CommentCreate(ctx, "ok", validAuthor, uuid.Nil)
 		if !errors.Is(err, ErrPostIDRequired) {
 			t.Fatalf("expected %v, got %v", ErrPostIDRequired, err)
@@ -86,7 +150,7 @@ func TestCommentService_CommentCreate(t *testing.T) {
 
 	t.Run("comments disabled", func(t *testing.T) {
 		commentRepo := &mockCommentRepo{}
-		svc := New(commentRepo, &mockPostRepo{withoutComment: false})
+		svc := New(commentRepo, &mockPostRepo{withoutComment: false}, &mockPublisher{})
 		_, err := svc.CommentCreate(ctx, "ok", validAuthor, validPost)
 		if !errors.Is(err, ErrCantWriteComment) {
 			t.Fatalf("expected %v, got %v", ErrCantWriteComment, err)
@@ -98,7 +162,7 @@ func TestCommentService_CommentCreate(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		commentRepo := &mockCommentRepo{}
-		svc := New(commentRepo, &mockPostRepo{withoutComment: true})
+		svc := New(commentRepo, &mockPostRepo{withoutComment: true}, &mockPublisher{})
 		_, err := svc.CommentCreate(ctx, "ok", validAuthor, validPost)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -116,7 +180,7 @@ func TestCommentService_CommentAnswer(t *testing.T) {
 	validParent := uuid.New()
 
 	t.Run("comment id required", func(t *testing.T) {
-		svc := New(&mockCommentRepo{}, &mockPostRepo{})
+		svc := New(&mockCommentRepo{}, &mockPostRepo{}, &mockPublisher{})
 		_, err := svc.CommentAnswer(ctx, "ok", validAuthor, validPost, uuid.Nil)
 		if !errors.Is(err, ErrCommentIDRequired) {
 			t.Fatalf("expected %v, got %v", ErrCommentIDRequired, err)
@@ -125,7 +189,7 @@ func TestCommentService_CommentAnswer(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		commentRepo := &mockCommentRepo{}
-		svc := New(commentRepo, &mockPostRepo{})
+		svc := New(commentRepo, &mockPostRepo{}, &mockPublisher{})
 		_, err := svc.CommentAnswer(ctx, "ok", validAuthor, validPost, validParent)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -135,3 +199,211 @@ func TestCommentService_CommentAnswer(t *testing.T) {
 		}
 	})
 }
+
+func TestCommentService_EditComment(t *testing.T) {
+	ctx := context.Background()
+	author := uuid.New()
+	commentID := uuid.New()
+	deletedAt := time.Now()
+
+	tests := []struct {
+		name       string
+		repo       *mockCommentRepo
+		authorID   uuid.UUID
+		text       string
+		wantErr    error
+		wantUpdate bool
+	}{
+		{name: "text required", repo: &mockCommentRepo{}, authorID: author, text: " ", wantErr: ErrTextRequired},
+		{name: "not found", repo: &mockCommentRepo{}, authorID: author, text: "ok", wantErr: ErrCommentNotFound},
+		{
+			name:     "not author",
+			repo:     &mockCommentRepo{byID: &models.Comment{ID: commentID, AuthorID: uuid.New()}},
+			authorID: author,
+			text:     "ok",
+			wantErr:  ErrNotCommentAuthor,
+		},
+		{
+			name:     "deleted",
+			repo:     &mockCommentRepo{byID: &models.Comment{ID: commentID, AuthorID: author, DeletedAt: &deletedAt}},
+			authorID: author,
+			text:     "ok",
+			wantErr:  ErrCommentDeleted,
+		},
+		{
+			name:       "success",
+			repo:       &mockCommentRepo{byID: &models.Comment{ID: commentID, AuthorID: author, Text: "old"}},
+			authorID:   author,
+			text:       " new ",
+			wantUpdate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub := &mockPublisher{}
+			svc := New(tt.repo, &mockPostRepo{}, pub)
+			c, err := svc.EditComment(ctx, commentID, tt.authorID, tt.text)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				if len(pub.edited) != 0 {
+					t.Fatalf("failed edit must not be published")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.repo.updateCalled != tt.wantUpdate || c.Text != "new" || c.EditedAt == nil {
+				t.Fatalf("unexpected edit result: %+v", c)
+			}
+			if len(pub.edited) != 1 || pub.edited[0] != c {
+				t.Fatalf("expected one edited event, got %d", len(pub.edited))
+			}
+		})
+	}
+}
+
+func TestCommentService_DeleteComment(t *testing.T) {
+	ctx := context.Background()
+	author := uuid.New()
+	commentID := uuid.New()
+	parentID := uuid.New()
+
+	t.Run("not author", func(t *testing.T) {
+		repo := &mockCommentRepo{byID: &models.Comment{ID: commentID, AuthorID: uuid.New()}}
+		_, err := New(repo, &mockPostRepo{}, &mockPublisher{}).DeleteComment(ctx, commentID, author)
+		if !errors.Is(err, ErrNotCommentAuthor) {
+			t.Fatalf("expected %v, got %v", ErrNotCommentAuthor, err)
+		}
+		if repo.deleteCalled {
+			t.Fatalf("delete repo must not be called")
+		}
+	})
+
+	t.Run("tombstone", func(t *testing.T) {
+		repo := &mockCommentRepo{byID: &models.Comment{ID: commentID, AuthorID: author, ParentCommentID: &parentID, Text: "secret"}}
+		pub := &mockPublisher{}
+		c, err := New(repo, &mockPostRepo{}, pub).DeleteComment(ctx, commentID, author)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(pub.deleted) != 1 || pub.deleted[0] != c {
+			t.Fatalf("expected one deleted event, got %d", len(pub.deleted))
+		}
+
+		pb := ToPB(c)
+		if !pb.GetDeleted() || pb.GetText() != DeletedText || pb.GetAuthorId() != "" || pb.GetParentId() != parentID.String() {
+			t.Fatalf("unexpected tombstone: %+v", pb)
+		}
+	})
+}
+
+func TestCommentService_GetCommentsByIDs(t *testing.T) {
+	ctx := context.Background()
+	first := &models.Comment{ID: uuid.New()}
+	second := &models.Comment{ID: uuid.New()}
+	missing := uuid.New()
+
+	t.Run("invalid id", func(t *testing.T) {
+		svc := New(&mockCommentRepo{}, &mockPostRepo{}, &mockPublisher{})
+		_, err := svc.GetCommentsByIDs(ctx, &servicepb.GetCommentsByIDsRequest{Ids: []string{"bad"}})
+		if !errors.Is(err, ErrInvalidCommentID) {
+			t.Fatalf("expected %v, got %v", ErrInvalidCommentID, err)
+		}
+	})
+
+	t.Run("too many ids", func(t *testing.T) {
+		ids := make([]string, maxIDsPerBatch+1)
+		for i := range ids {
+			ids[i] = uuid.NewString()
+		}
+		svc := New(&mockCommentRepo{}, &mockPostRepo{}, &mockPublisher{})
+		_, err := svc.GetCommentsByIDs(ctx, &servicepb.GetCommentsByIDsRequest{Ids: ids})
+		if !errors.Is(err, ErrTooManyIDs) {
+			t.Fatalf("expected %v, got %v", ErrTooManyIDs, err)
+		}
+	})
+
+	t.Run("keeps requested order and skips missing", func(t *testing.T) {
+		// репозиторий отдаёт строки в произвольном порядке
+		repo := &mockCommentRepo{byIDs: []*models.Comment{first, second}}
+		svc := New(repo, &mockPostRepo{}, &mockPublisher{})
+		resp, err := svc.GetCommentsByIDs(ctx, &servicepb.GetCommentsByIDsRequest{
+			Ids: []string{second.ID.String(), missing.String(), first.ID.String(), second.ID.String()},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(repo.gotIDs) != 3 {
+			t.Fatalf("expected deduplicated ids, got %v", repo.gotIDs)
+		}
+		got := resp.GetComments()
+		if len(got) != 2 || got[0].GetId() != second.ID.String() || got[1].GetId() != first.ID.String() {
+			t.Fatalf("unexpected comments order: %v", got)
+		}
+	})
+}
+
+func TestCommentService_PublishesCreatedComments(t *testing.T) {
+	ctx := context.Background()
+	authorID := uuid.New()
+	postID := uuid.New()
+	parentID := uuid.New()
+
+	tests := []struct {
+		name       string
+		publisher  *mockPublisher
+		run        func(svc *CommentService) (*models.Comment, error)
+		wantErr    error
+		wantEvents int
+	}{
+		{
+			name:      "create publishes",
+			publisher: &mockPublisher{},
+			run: func(svc *CommentService) (*models.Comment, error) {
+				return svc.CommentCreate(ctx, "hello", authorID, postID)
+			},
+			wantEvents: 1,
+		},
+		{
+			name:      "answer publishes",
+			publisher: &mockPublisher{},
+			run: func(svc *CommentService) (*models.Comment, error) {
+				return svc.CommentAnswer(ctx, "hello", authorID, postID, parentID)
+			},
+			wantEvents: 1,
+		},
+		{
+			name:      "broker failure does not fail request",
+			publisher: &mockPublisher{err: errors.New("notify failed")},
+			run: func(svc *CommentService) (*models.Comment, error) {
+				return svc.CommentCreate(ctx, "hello", authorID, postID)
+			},
+			wantEvents: 1,
+		},
+		{
+			name:      "rejected comment is not published",
+			publisher: &mockPublisher{},
+			run: func(svc *CommentService) (*models.Comment, error) {
+				return svc.CommentCreate(ctx, "   ", authorID, postID)
+			},
+			wantErr: ErrTextRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := New(&mockCommentRepo{}, &mockPostRepo{withoutComment: true}, tt.publisher)
+			_, err := tt.run(svc)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if len(tt.publisher.created) != tt.wantEvents {
+				t.Fatalf("expected %d events, got %d", tt.wantEvents, len(tt.publisher.created))
+			}
+		})
+	}
+}
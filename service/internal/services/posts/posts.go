@@ -5,9 +5,12 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"github.com/Parnishkaspb/ozon_posts/internal/events"
 	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
 	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -18,24 +21,38 @@ var (
 	ErrAuthorIDRequired = errors.New("authorID is required")
 	ErrTextRequired     = errors.New("text is required")
 	ErrProblemsWithIDs  = errors.New("problems with IDs")
+	ErrPostIDRequired   = errors.New("postID is required")
+	ErrNothingToUpdate  = errors.New("nothing to update")
+	ErrPostNotFound     = errors.New("post not found")
+	ErrNotPostAuthor    = errors.New("only the author can modify the post")
+	ErrTooManyIDs       = errors.New("too many ids")
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrCommentsDisabled = errors.New("can't write a comment to this post")
 )
 
-const defaultPageSize = 20
+const (
+	defaultPageSize = 20
+	maxIDsPerBatch  = 200
+)
 
 type PostRepo interface {
 	CreatePost(ctx context.Context, ownerID uuid.UUID, text string, withoutComment bool) (*models.Post, error)
 	GetAllPosts(ctx context.Context) ([]*models.Post, error)
 	GetPostsByID(ctx context.Context, id string) (*models.Post, error)
+	GetPostsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Post, error)
 	WithoutComment(ctx context.Context, postID uuid.UUID) (bool, error)
 	GetPostsPage(ctx context.Context, first int, afterCreatedAt *time.Time, afterID *uuid.UUID) ([]*models.Post, bool, error)
+	UpdatePost(ctx context.Context, postID uuid.UUID, text *string, withoutComment *bool) (*models.Post, error)
+	DeletePost(ctx context.Context, postID uuid.UUID) error
 }
 
 type PostService struct {
-	repo PostRepo
+	repo   PostRepo
+	events events.Publisher
 }
 
-func New(repo PostRepo) *PostService {
-	return &PostService{repo: repo}
+func New(repo PostRepo, publisher events.Publisher) *PostService {
+	return &PostService{repo: repo, events: publisher}
 }
 
 func (s *PostService) CreatePost(ctx context.Context, authorID uuid.UUID, text string, withoutComment bool) (*models.Post, error) {
@@ -47,26 +64,139 @@ func (s *PostService) CreatePost(ctx context.Context, authorID uuid.UUID, text s
 		return nil, ErrTextRequired
 	}
 
-	return s.repo.CreatePost(ctx, authorID, text, withoutComment)
+	post, err := s.repo.CreatePost(ctx, authorID, text, withoutComment)
+	if err != nil {
+		return nil, err
+	}
+
+	// пост уже сохранён, сбой брокера не превращается в ошибку запроса
+	if err := s.events.PostCreated(ctx, post); err != nil {
+		slog.ErrorContext(ctx, "publish post", "post_id", post.ID, "error", err)
+	}
+	return post, nil
 }
 
-func (s *PostService) GetAllPosts(ctx context.Context, id []string) ([]*models.Post, error) {
-	if len(id) > 1 {
-		return nil, ErrProblemsWithIDs
+func (s *PostService) UpdatePost(ctx context.Context, postID, authorID uuid.UUID, text *string, withoutComment *bool) (*models.Post, error) {
+	if postID == uuid.Nil {
+		return nil, ErrPostIDRequired
+	}
+	if authorID == uuid.Nil {
+		return nil, ErrAuthorIDRequired
+	}
+	if text == nil && withoutComment == nil {
+		return nil, ErrNothingToUpdate
+	}
+	if text != nil && strings.TrimSpace(*text) == "" {
+		return nil, ErrTextRequired
 	}
 
-	if len(id) == 1 && id[0] != "" {
-		post, err := s.repo.GetPostsByID(ctx, id[0])
+	if err := s.checkAuthor(ctx, postID, authorID); err != nil {
+		return nil, err
+	}
+
+	post, err := s.repo.UpdatePost(ctx, postID, text, withoutComment)
+	if err != nil {
+		if errors.Is(err, repositories.ErrPostNotFound) {
+			return nil, ErrPostNotFound
+		}
+		return nil, err
+	}
+
+	return post, nil
+}
+
+func (s *PostService) DeletePost(ctx context.Context, postID, authorID uuid.UUID) error {
+	if postID == uuid.Nil {
+		return ErrPostIDRequired
+	}
+	if authorID == uuid.Nil {
+		return ErrAuthorIDRequired
+	}
+
+	if err := s.checkAuthor(ctx, postID, authorID); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeletePost(ctx, postID); err != nil {
+		if errors.Is(err, repositories.ErrPostNotFound) {
+			return ErrPostNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// checkAuthor проверяет, что пост существует и принадлежит authorID.
+func (s *PostService) checkAuthor(ctx context.Context, postID, authorID uuid.UUID) error {
+	post, err := s.repo.GetPostsByID(ctx, postID.String())
+	if err != nil {
+		if errors.Is(err, repositories.ErrPostNotFound) {
+			return ErrPostNotFound
+		}
+		return err
+	}
+	if post == nil {
+		return ErrPostNotFound
+	}
+	if post.AuthorID != authorID {
+		return ErrNotPostAuthor
+	}
+	return nil
+}
+
+// GetAllPosts без ids возвращает все посты, иначе — найденные посты из ids
+// в порядке запроса (ненайденные пропускаются).
+func (s *PostService) GetAllPosts(ctx context.Context, ids []string) ([]*models.Post, error) {
+	if len(ids) == 0 || (len(ids) == 1 && ids[0] == "") {
+		return s.repo.GetAllPosts(ctx)
+	}
+
+	found, _, err := s.GetPostsByIDs(ctx, ids)
+	return found, err
+}
+
+// GetPostsByIDs достаёт посты одним запросом. found идёт в порядке ids
+// (дубликаты схлопываются), missing — ids, для которых поста нет.
+func (s *PostService) GetPostsByIDs(ctx context.Context, ids []string) ([]*models.Post, []string, error) {
+	uniq := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	for _, raw := range ids {
+		id, err := uuid.Parse(raw)
 		if err != nil {
-			return nil, err
+			return nil, nil, ErrProblemsWithIDs
+		}
+		if _, ok := seen[id]; ok {
+			continue
 		}
-		if post == nil {
-			return []*models.Post{}, nil
+		seen[id] = struct{}{}
+		uniq = append(uniq, id)
+	}
+	if len(uniq) > maxIDsPerBatch {
+		return nil, nil, ErrTooManyIDs
+	}
+
+	posts, err := s.repo.GetPostsByIDs(ctx, uniq)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[uuid.UUID]*models.Post, len(posts))
+	for _, p := range posts {
+		byID[p.ID] = p
+	}
+
+	found := make([]*models.Post, 0, len(posts))
+	missing := make([]string, 0)
+	for _, id := range uniq {
+		if p, ok := byID[id]; ok {
+			found = append(found, p)
+			continue
 		}
-		return []*models.Post{post}, nil
+		missing = append(missing, id.String())
 	}
 
-	return s.repo.GetAllPosts(ctx)
+	return found, missing, nil
 }
 
 func (s *PostService) GetPostsByPage(ctx context.Context, first int, after string) ([]*servicepb.Post, string, bool, error) {
@@ -80,7 +210,7 @@ func (s *PostService) GetPostsByPage(ctx context.Context, first int, after strin
 	if after != "" {
 		t, id, err := parsePostCursor(after)
 		if err != nil {
-			return nil, "", false, fmt.Errorf("bad cursor: %w", err)
+			return nil, "", false, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
 		}
 		afterT = &t
 		afterID = &id
@@ -93,7 +223,7 @@ func (s *PostService) GetPostsByPage(ctx context.Context, first int, after strin
 
 	out := make([]*servicepb.Post, 0, len(posts))
 	for _, p := range posts {
-		out = append(out, toPBPost(p))
+		out = append(out, ToPBPost(p))
 	}
 
 	endCursor := ""
@@ -105,7 +235,7 @@ func (s *PostService) GetPostsByPage(ctx context.Context, first int, after strin
 	return out, endCursor, hasNext, nil
 }
 
-func toPBPost(p *models.Post) *servicepb.Post {
+func ToPBPost(p *models.Post) *servicepb.Post {
 	return &servicepb.Post{
 		Id:             p.ID.String(),
 		AuthorId:       p.AuthorID.String(),
@@ -144,11 +274,14 @@ func parsePostCursor(cur string) (time.Time, uuid.UUID, error) {
 func (s *PostService) CanWriteComment(ctx context.Context, id uuid.UUID) error {
 	ok, err := s.repo.WithoutComment(ctx, id)
 	if err != nil {
+		if errors.Is(err, repositories.ErrPostNotFound) {
+			return ErrPostNotFound
+		}
 		return err
 	}
 
 	if !ok {
-		return errors.New("can't write a comment to this post")
+		return ErrCommentsDisabled
 	}
 
 	return nil
@@ -3,15 +3,24 @@ package posts
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 	"time"
 
+	"github.com/Parnishkaspb/ozon_posts/internal/events"
 	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
 	"github.com/google/uuid"
 )
 
 type mockPostRepo struct {
 	createFn func(ctx context.Context, ownerID uuid.UUID, text string, withoutComment bool) (*models.Post, error)
+	post     *models.Post
+	getErr   error
+	updateFn func(ctx context.Context, postID uuid.UUID, text *string, withoutComment *bool) (*models.Post, error)
+	deleted  []uuid.UUID
+	byID     map[string]*models.Post
+	gotIDs   []uuid.UUID
 }
 
 func (m *mockPostRepo) CreatePost(ctx context.Context, ownerID uuid.UUID, text string, withoutComment bool) (*models.Post, error) {
@@ -26,7 +35,26 @@ func (m *mockPostRepo) GetAllPosts(ctx context.Context) ([]*models.Post, error)
 }
 
 func (m *mockPostRepo) GetPostsByID(ctx context.Context, id string) (*models.Post, error) {
-	return nil, nil
+	if m.byID != nil {
+		p, ok := m.byID[id]
+		if !ok {
+			return nil, repositories.ErrPostNotFound
+		}
+		return p, nil
+	}
+	return m.post, m.getErr
+}
+
+func (m *mockPostRepo) GetPostsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Post, error) {
+	m.gotIDs = ids
+	out := make([]*models.Post, 0, len(ids))
+	// отдаём в обратном порядке, как мог бы сделать postgres без ORDER BY
+	for i := len(ids) - 1; i >= 0; i-- {
+		if p, ok := m.byID[ids[i].String()]; ok {
+			out = append(out, p)
+		}
+	}
+	return out, nil
 }
 
 func (m *mockPostRepo) WithoutComment(ctx context.Context, postID uuid.UUID) (bool, error) {
@@ -37,13 +65,35 @@ func (m *mockPostRepo) GetPostsPage(ctx context.Context, first int, afterCreated
 	return nil, false, nil
 }
 
+func (m *mockPostRepo) UpdatePost(ctx context.Context, postID uuid.UUID, text *string, withoutComment *bool) (*models.Post, error) {
+	if m.updateFn != nil {
+		return m.updateFn(ctx, postID, text, withoutComment)
+	}
+	return nil, nil
+}
+
+func (m *mockPostRepo) DeletePost(ctx context.Context, postID uuid.UUID) error {
+	m.deleted = append(m.deleted, postID)
+	return nil
+}
+
+type mockPublisher struct {
+	events.Nop
+	created []*models.Post
+}
+
+func (m *mockPublisher) PostCreated(ctx context.Context, p *models.Post) error {
+	m.created = append(m.created, p)
+	return nil
+}
+
 func TestPostService_CreatePost(t *testing.T) {
 	ctx := context.Background()
 	authorID := uuid.New()
 	repoErr := errors.New("repo failed")
 
 	t.Run("author id required", func(t *testing.T) {
-		svc := New(&mockPostRepo{})
+		svc := New(&mockPostRepo{}, events.Nop{})
 		_, err := svc.CreatePost(ctx, uuid.Nil, "text", false)
 		if !errors.Is(err, ErrAuthorIDRequired) {
 			t.Fatalf("expected %v, got %v", ErrAuthorIDRequired, err)
@@ -51,7 +101,7 @@ func TestPostService_CreatePost(t *testing.T) {
 	})
 
 	t.Run("text required", func(t *testing.T) {
-		svc := New(&mockPostRepo{})
+		svc := New(&mockPostRepo{}, events.Nop{})
 		_, err := svc.CreatePost(ctx, authorID, "   ", false)
 		if !errors.Is(err, ErrTextRequired) {
 			t.Fatalf("expected %v, got %v", ErrTextRequired, err)
@@ -61,7 +111,7 @@ func TestPostService_CreatePost(t *testing.T) {
 	t.Run("repo error returned", func(t *testing.T) {
 		svc := New(&mockPostRepo{createFn: func(ctx context.Context, ownerID uuid.UUID, text string, withoutComment bool) (*models.Post, error) {
 			return nil, repoErr
-		}})
+		}}, events.Nop{})
 		_, err := svc.CreatePost(ctx, authorID, "ok", true)
 		if !errors.Is(err, repoErr) {
 			t.Fatalf("expected %v, got %v", repoErr, err)
@@ -76,12 +126,13 @@ func TestPostService_CreatePost(t *testing.T) {
 		)
 
 		expected := &models.Post{ID: uuid.New(), AuthorID: authorID, Text: "ok", WithoutComment: true}
+		pub := &mockPublisher{}
 		svc := New(&mockPostRepo{createFn: func(ctx context.Context, ownerID uuid.UUID, text string, withoutComment bool) (*models.Post, error) {
 			gotAuthor = ownerID
 			gotText = text
 			gotWithout = withoutComment
 			return expected, nil
-		}})
+		}}, pub)
 
 		got, err := svc.CreatePost(ctx, authorID, "ok", true)
 		if err != nil {
@@ -93,5 +144,164 @@ func TestPostService_CreatePost(t *testing.T) {
 		if gotAuthor != authorID || gotText != "ok" || !gotWithout {
 			t.Fatalf("unexpected repo args: author=%s text=%q without=%v", gotAuthor, gotText, gotWithout)
 		}
+		if len(pub.created) != 1 || pub.created[0] != expected {
+			t.Fatalf("expected one post_created event, got %d", len(pub.created))
+		}
+	})
+}
+
+func TestPostService_UpdatePost(t *testing.T) {
+	ctx := context.Background()
+	authorID := uuid.New()
+	postID := uuid.New()
+	text := "fixed typo"
+	blank := "  "
+	existing := &models.Post{ID: postID, AuthorID: authorID, Text: "fixed tpyo"}
+
+	tests := []struct {
+		name     string
+		repo     *mockPostRepo
+		authorID uuid.UUID
+		text     *string
+		wantErr  error
+	}{
+		{name: "nothing to update", repo: &mockPostRepo{post: existing}, authorID: authorID, wantErr: ErrNothingToUpdate},
+		{name: "blank text", repo: &mockPostRepo{post: existing}, authorID: authorID, text: &blank, wantErr: ErrTextRequired},
+		{name: "post not found", repo: &mockPostRepo{getErr: repositories.ErrPostNotFound}, authorID: authorID, text: &text, wantErr: ErrPostNotFound},
+		{name: "not author", repo: &mockPostRepo{post: existing}, authorID: uuid.New(), text: &text, wantErr: ErrNotPostAuthor},
+		{
+			name: "deleted concurrently",
+			repo: &mockPostRepo{post: existing, updateFn: func(ctx context.Context, postID uuid.UUID, text *string, withoutComment *bool) (*models.Post, error) {
+				return nil, repositories.ErrPostNotFound
+			}},
+			authorID: authorID,
+			text:     &text,
+			wantErr:  ErrPostNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := New(tt.repo, events.Nop{})
+			_, err := svc.UpdatePost(ctx, postID, tt.authorID, tt.text, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var gotText *string
+		svc := New(&mockPostRepo{post: existing, updateFn: func(ctx context.Context, id uuid.UUID, text *string, withoutComment *bool) (*models.Post, error) {
+			gotText = text
+			return &models.Post{ID: id, AuthorID: authorID, Text: *text}, nil
+		}}, events.Nop{})
+
+		got, err := svc.UpdatePost(ctx, postID, authorID, &text, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotText == nil || *gotText != text || got.Text != text {
+			t.Fatalf("unexpected update: %+v", got)
+		}
+	})
+}
+
+func TestPostService_DeletePost(t *testing.T) {
+	ctx := context.Background()
+	authorID := uuid.New()
+	postID := uuid.New()
+	existing := &models.Post{ID: postID, AuthorID: authorID}
+
+	t.Run("not author", func(t *testing.T) {
+		repo := &mockPostRepo{post: existing}
+		err := New(repo, events.Nop{}).DeletePost(ctx, postID, uuid.New())
+		if !errors.Is(err, ErrNotPostAuthor) {
+			t.Fatalf("expected %v, got %v", ErrNotPostAuthor, err)
+		}
+		if len(repo.deleted) != 0 {
+			t.Fatalf("repo delete must not be called")
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		repo := &mockPostRepo{post: existing}
+		if err := New(repo, events.Nop{}).DeletePost(ctx, postID, authorID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(repo.deleted) != 1 || repo.deleted[0] != postID {
+			t.Fatalf("unexpected repo delete calls: %v", repo.deleted)
+		}
+	})
+}
+
+func TestPostService_GetPostsByIDs(t *testing.T) {
+	ctx := context.Background()
+	first := &models.Post{ID: uuid.New()}
+	second := &models.Post{ID: uuid.New()}
+	missing := uuid.NewString()
+
+	tests := []struct {
+		name        string
+		ids         []string
+		want        []*models.Post
+		wantMissing []string
+		wantRepoIDs int
+		wantErr     error
+	}{
+		{name: "invalid id", ids: []string{first.ID.String(), "bad"}, wantErr: ErrProblemsWithIDs},
+		{
+			name:        "requested order kept",
+			ids:         []string{first.ID.String(), second.ID.String()},
+			want:        []*models.Post{first, second},
+			wantMissing: []string{},
+			wantRepoIDs: 2,
+		},
+		{
+			name:        "missing reported and duplicates collapsed",
+			ids:         []string{second.ID.String(), missing, second.ID.String(), first.ID.String()},
+			want:        []*models.Post{second, first},
+			wantMissing: []string{missing},
+			wantRepoIDs: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockPostRepo{byID: map[string]*models.Post{
+				first.ID.String():  first,
+				second.ID.String(): second,
+			}}
+			got, gotMissing, err := New(repo, events.Nop{}).GetPostsByIDs(ctx, tt.ids)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(repo.gotIDs) != tt.wantRepoIDs {
+				t.Fatalf("expected %d ids in one repo call, got %v", tt.wantRepoIDs, repo.gotIDs)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("unexpected posts order")
+			}
+			if !reflect.DeepEqual(gotMissing, tt.wantMissing) {
+				t.Fatalf("unexpected missing ids: got %v want %v", gotMissing, tt.wantMissing)
+			}
+		})
+	}
+
+	t.Run("too many ids", func(t *testing.T) {
+		ids := make([]string, maxIDsPerBatch+1)
+		for i := range ids {
+			ids[i] = uuid.NewString()
+		}
+		_, _, err := New(&mockPostRepo{}, events.Nop{}).GetPostsByIDs(ctx, ids)
+		if !errors.Is(err, ErrTooManyIDs) {
+			t.Fatalf("expected %v, got %v", ErrTooManyIDs, err)
+		}
 	})
 }
@@ -2,14 +2,29 @@ package users
 
 import (
 	"context"
+	"errors"
+	"github.com/Parnishkaspb/ozon_posts/internal/auth"
 	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
 	"github.com/google/uuid"
+	"regexp"
+	"strings"
 )
 
+var (
+	ErrInvalidLogin    = errors.New("login must be 3-32 characters: latin letters, digits, '.', '_' or '-'")
+	ErrNameRequired    = errors.New("name is required")
+	ErrSurnameRequired = errors.New("surname is required")
+	ErrLoginTaken      = errors.New("login is already taken")
+)
+
+var loginRe = regexp.MustCompile(`^[A-Za-z0-9._-]{3,32}$`)
+
 type UserRepo interface {
 	GetAllUsers(ctx context.Context) ([]*models.User, error)
 	GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error)
 	GetUsersByIDs(ctx context.Context, ids []string) ([]*models.User, error)
+	CreateUser(ctx context.Context, login, passwordHash, name, surname string) (*models.User, error)
 }
 
 type UserService struct {
@@ -36,3 +51,37 @@ func (s *UserService) GetUsersByIds(ctx context.Context, ids []string) ([]*model
 	}
 	return s.repo.GetUsersByIDs(ctx, ids)
 }
+
+// CreateUser регистрирует пользователя; пароль сохраняется только в виде bcrypt-хэша.
+func (s *UserService) CreateUser(ctx context.Context, login, password, name, surname string) (*models.User, error) {
+	login = strings.TrimSpace(login)
+	name = strings.TrimSpace(name)
+	surname = strings.TrimSpace(surname)
+
+	if !loginRe.MatchString(login) {
+		return nil, ErrInvalidLogin
+	}
+	if name == "" {
+		return nil, ErrNameRequired
+	}
+	if surname == "" {
+		return nil, ErrSurnameRequired
+	}
+	if err := auth.ValidatePassword(login, password); err != nil {
+		return nil, err
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.CreateUser(ctx, login, hash, name, surname)
+	if err != nil {
+		if errors.Is(err, repositories.ErrLoginTaken) {
+			return nil, ErrLoginTaken
+		}
+		return nil, err
+	}
+	return user, nil
+}
@@ -6,7 +6,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/Parnishkaspb/ozon_posts/internal/auth"
 	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
 	"github.com/google/uuid"
 )
 
@@ -20,6 +22,8 @@ type mockUsersRepo struct {
 	calledByIDs int
 	gotIDs      []string
 	gotUserID   uuid.UUID
+	created     *models.User
+	createErr   error
 }
 
 func (m *mockUsersRepo) GetAllUsers(ctx context.Context) ([]*models.User, error) {
@@ -39,6 +43,14 @@ func (m *mockUsersRepo) GetUsersByIDs(ctx context.Context, ids []string) ([]*mod
 	return m.usersByIDs, m.err
 }
 
+func (m *mockUsersRepo) CreateUser(ctx context.Context, login, passwordHash, name, surname string) (*models.User, error) {
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+	m.created = &models.User{ID: uuid.New(), Login: login, Password: passwordHash, Name: name, Surname: surname}
+	return m.created, nil
+}
+
 func TestUserService_GetUsersByIds(t *testing.T) {
 	ctx := context.Background()
 	expectedAll := []*models.User{{ID: uuid.New()}}
@@ -145,3 +157,43 @@ func TestUserService_GetUserByID(t *testing.T) {
 		})
 	}
 }
+
+func TestUserService_CreateUser(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		repo     *mockUsersRepo
+		login    string
+		password string
+		wantErr  error
+	}{
+		{name: "invalid login", repo: &mockUsersRepo{}, login: "a b", password: "long enough", wantErr: ErrInvalidLogin},
+		{name: "short password", repo: &mockUsersRepo{}, login: "Petr", password: "short", wantErr: auth.ErrPasswordTooShort},
+		{name: "password equals login", repo: &mockUsersRepo{}, login: "PetrPervyi", password: "petrpervyi", wantErr: auth.ErrPasswordIsLogin},
+		{name: "login taken", repo: &mockUsersRepo{createErr: repositories.ErrLoginTaken}, login: "Ivan", password: "long enough", wantErr: ErrLoginTaken},
+		{name: "success", repo: &mockUsersRepo{}, login: " Petr ", password: "long enough"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewUserService(tt.repo)
+			u, err := svc.CreateUser(ctx, tt.login, tt.password, "Пётр", "Первый")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if u.Login != "Petr" {
+				t.Fatalf("expected trimmed login, got %q", u.Login)
+			}
+			if u.Password == tt.password || !auth.CheckPassword(u.Password, tt.password) {
+				t.Fatalf("password must be stored hashed")
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryTracer пишет спан на каждый запрос pgx. Запросы вне трассы (пинги
+// health-монитора, LISTEN, миграции) не трассируются, чтобы не плодить
+// корневые спаны.
+type QueryTracer struct {
+	tracer trace.Tracer
+}
+
+var _ pgx.QueryTracer = (*QueryTracer)(nil)
+
+func NewQueryTracer() *QueryTracer {
+	return &QueryTracer{tracer: otel.Tracer(scope)}
+}
+
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	op := operation(data.SQL)
+	ctx, _ = t.tracer.Start(ctx, "postgres "+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBOperationName(op),
+			semconv.DBQueryText(data.SQL),
+		),
+	)
+	return ctx
+}
+
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	defer span.End()
+
+	span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+	// ErrNoRows — обычный «не найдено», а не сбой запроса
+	if data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows) {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	}
+}
+
+// operation — первое слово запроса: SELECT, INSERT, WITH и т.п.
+func operation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	return strings.ToUpper(fields[0])
+}
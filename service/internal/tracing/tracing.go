@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+const scope = "github.com/Parnishkaspb/ozon_posts"
+
+var ErrUnknownExporter = errors.New("unknown tracing exporter")
+
+// Options — куда и сколько экспортировать.
+type Options struct {
+	ServiceName string
+	// Exporter: none, stdout или otlp (OTLP/gRPC).
+	Exporter string
+	// Endpoint — host:port коллектора; пусто — OTEL_EXPORTER_OTLP_ENDPOINT или localhost:4317.
+	Endpoint string
+	Insecure bool
+	// SampleRatio — доля трасс, начатых здесь; для продолженных из traceparent
+	// решение принимает вызывающая сторона.
+	SampleRatio float64
+}
+
+// Validate проверяет имя экспортера и долю сэмплирования.
+func (o Options) Validate() error {
+	switch o.Exporter {
+	case "", "none", "stdout", "otlp":
+	default:
+		return fmt.Errorf("%w: %q, want none, stdout or otlp", ErrUnknownExporter, o.Exporter)
+	}
+	if o.SampleRatio < 0 || o.SampleRatio > 1 {
+		return fmt.Errorf("sample_ratio: must be in 0..1, got %g", o.SampleRatio)
+	}
+	return nil
+}
+
+// Setup ставит глобальные propagator и TracerProvider. Propagator ставится
+// всегда, чтобы trace context проходил насквозь даже без экспорта.
+// Возвращённый shutdown дописывает буфер спанов.
+func Setup(ctx context.Context, opts Options) (shutdown func(context.Context) error, err error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	switch opts.Exporter {
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	case "stdout":
+		exporter, err = stdouttrace.New()
+	case "otlp":
+		var clientOpts []otlptracegrpc.Option
+		if opts.Endpoint != "" {
+			clientOpts = append(clientOpts, otlptracegrpc.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, clientOpts...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s exporter: %w", opts.Exporter, err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(semconv.ServiceName(opts.ServiceName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
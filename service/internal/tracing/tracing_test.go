@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{name: "none", opts: Options{Exporter: "none", SampleRatio: 1}},
+		{name: "empty exporter", opts: Options{}},
+		{name: "otlp", opts: Options{Exporter: "otlp", SampleRatio: 0.1}},
+		{name: "unknown exporter", opts: Options{Exporter: "jaeger"}, wantErr: true},
+		{name: "ratio above one", opts: Options{Exporter: "stdout", SampleRatio: 1.5}, wantErr: true},
+		{name: "negative ratio", opts: Options{SampleRatio: -0.1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQueryTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	qt := NewQueryTracer()
+
+	tests := []struct {
+		name       string
+		parent     bool
+		sql        string
+		err        error
+		wantSpan   string
+		wantStatus codes.Code
+	}{
+		{name: "no parent span", sql: "SELECT 1"},
+		{name: "select", parent: true, sql: "  select id from posts", wantSpan: "postgres SELECT", wantStatus: codes.Unset},
+		{name: "no rows is not an error", parent: true, sql: "SELECT 1", err: pgx.ErrNoRows, wantSpan: "postgres SELECT", wantStatus: codes.Unset},
+		{name: "failed insert", parent: true, sql: "INSERT INTO posts", err: errors.New("boom"), wantSpan: "postgres INSERT", wantStatus: codes.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(recorder.Ended())
+
+			ctx := context.Background()
+			if tt.parent {
+				var span trace.Span
+				ctx, span = tp.Tracer("test").Start(ctx, "rpc")
+				defer span.End()
+			}
+
+			ctx = qt.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: tt.sql})
+			qt.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1"), Err: tt.err})
+
+			ended := recorder.Ended()[before:]
+			if tt.wantSpan == "" {
+				if len(ended) != 0 {
+					t.Fatalf("got %d spans, want none", len(ended))
+				}
+				return
+			}
+			if len(ended) != 1 {
+				t.Fatalf("got %d spans, want 1", len(ended))
+			}
+			if got := ended[0].Name(); got != tt.wantSpan {
+				t.Errorf("span name = %q, want %q", got, tt.wantSpan)
+			}
+			if got := ended[0].Status().Code; got != tt.wantStatus {
+				t.Errorf("span status = %v, want %v", got, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Parnishkaspb/ozon_posts/internal/auth"
+	"github.com/Parnishkaspb/ozon_posts/internal/repositories"
+	"github.com/Parnishkaspb/ozon_posts/internal/services/comments"
+	"github.com/Parnishkaspb/ozon_posts/internal/services/posts"
+	"github.com/Parnishkaspb/ozon_posts/internal/services/users"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain — domain в google.rpc.ErrorInfo всех ошибок сервиса.
+const ErrorDomain = "ozon_posts"
+
+// domainError описывает, как доменная ошибка уходит клиенту: код, reason для
+// ErrorInfo и поле запроса. Для InvalidArgument поле попадает в BadRequest,
+// для остальных кодов — в метаданные ErrorInfo. msg заменяет текст ошибки.
+type domainError struct {
+	err    error
+	code   codes.Code
+	reason string
+	field  string
+	msg    string
+}
+
+var domainErrors = []domainError{
+	// auth
+	{err: auth.ErrEmpty, code: codes.InvalidArgument, reason: "CREDENTIALS_REQUIRED"},
+	{err: auth.ErrIncorrect, code: codes.Unauthenticated, reason: "INVALID_CREDENTIALS", msg: "invalid login or password"},
+	{err: auth.ErrInvalidRefreshToken, code: codes.Unauthenticated, reason: "INVALID_REFRESH_TOKEN", field: "refresh_token"},
+	{err: auth.ErrPasswordTooShort, code: codes.InvalidArgument, reason: "PASSWORD_TOO_SHORT", field: "password"},
+	{err: auth.ErrPasswordTooLong, code: codes.InvalidArgument, reason: "PASSWORD_TOO_LONG", field: "password"},
+	{err: auth.ErrPasswordIsLogin, code: codes.InvalidArgument, reason: "PASSWORD_IS_LOGIN", field: "password"},
+	{err: auth.ErrPasswordBlank, code: codes.InvalidArgument, reason: "PASSWORD_BLANK", field: "password"},
+
+	// users
+	{err: users.ErrInvalidLogin, code: codes.InvalidArgument, reason: "INVALID_LOGIN", field: "login"},
+	{err: users.ErrNameRequired, code: codes.InvalidArgument, reason: "NAME_REQUIRED", field: "name"},
+	{err: users.ErrSurnameRequired, code: codes.InvalidArgument, reason: "SURNAME_REQUIRED", field: "surname"},
+	{err: users.ErrLoginTaken, code: codes.AlreadyExists, reason: "LOGIN_TAKEN", field: "login"},
+
+	// posts
+	{err: posts.ErrAuthorIDRequired, code: codes.InvalidArgument, reason: "AUTHOR_ID_REQUIRED", field: "author_id"},
+	{err: posts.ErrTextRequired, code: codes.InvalidArgument, reason: "TEXT_REQUIRED", field: "text"},
+	{err: posts.ErrProblemsWithIDs, code: codes.InvalidArgument, reason: "INVALID_ID", field: "ids", msg: "ids must be valid UUIDs"},
+	{err: posts.ErrPostIDRequired, code: codes.InvalidArgument, reason: "POST_ID_REQUIRED", field: "id"},
+	{err: posts.ErrNothingToUpdate, code: codes.InvalidArgument, reason: "NOTHING_TO_UPDATE"},
+	{err: posts.ErrTooManyIDs, code: codes.InvalidArgument, reason: "TOO_MANY_IDS", field: "ids"},
+	{err: posts.ErrInvalidCursor, code: codes.InvalidArgument, reason: "INVALID_CURSOR", field: "after"},
+	{err: posts.ErrPostNotFound, code: codes.NotFound, reason: "POST_NOT_FOUND"},
+	{err: posts.ErrNotPostAuthor, code: codes.PermissionDenied, reason: "NOT_POST_AUTHOR"},
+	{err: posts.ErrCommentsDisabled, code: codes.PermissionDenied, reason: "COMMENTS_DISABLED", field: "post_id"},
+
+	// comments
+	{err: comments.ErrPostIDRequired, code: codes.InvalidArgument, reason: "POST_ID_REQUIRED", field: "post_id"},
+	{err: comments.ErrAuthorIDRequired, code: codes.InvalidArgument, reason: "AUTHOR_ID_REQUIRED", field: "author_id"},
+	{err: comments.ErrCommentIDRequired, code: codes.InvalidArgument, reason: "COMMENT_ID_REQUIRED", field: "id"},
+	{err: comments.ErrTextRequired, code: codes.InvalidArgument, reason: "TEXT_REQUIRED", field: "text"},
+	{err: comments.ErrMax2000Symbols, code: codes.InvalidArgument, reason: "TEXT_TOO_LONG", field: "text"},
+	{err: comments.ErrInvalidCursor, code: codes.InvalidArgument, reason: "INVALID_CURSOR", field: "after"},
+	{err: comments.ErrInvalidParentID, code: codes.InvalidArgument, reason: "INVALID_ID", field: "parent_id"},
+	{err: comments.ErrBadFirst, code: codes.InvalidArgument, reason: "INVALID_PAGE_SIZE", field: "first"},
+	{err: comments.ErrInvalidCommentID, code: codes.InvalidArgument, reason: "INVALID_ID", field: "ids"},
+	{err: comments.ErrTooManyIDs, code: codes.InvalidArgument, reason: "TOO_MANY_IDS", field: "ids"},
+	{err: comments.ErrCantWriteComment, code: codes.PermissionDenied, reason: "COMMENTS_DISABLED", field: "post_id"},
+	{err: comments.ErrPostNotFound, code: codes.NotFound, reason: "POST_NOT_FOUND", field: "post_id"},
+	{err: comments.ErrParentNotFound, code: codes.NotFound, reason: "PARENT_NOT_FOUND", field: "parent_id"},
+	{err: comments.ErrCommentNotFound, code: codes.NotFound, reason: "COMMENT_NOT_FOUND", field: "id"},
+	{err: comments.ErrNotCommentAuthor, code: codes.PermissionDenied, reason: "NOT_COMMENT_AUTHOR"},
+	{err: comments.ErrCommentDeleted, code: codes.FailedPrecondition, reason: "COMMENT_DELETED", field: "id"},
+
+	// репозитории: на случай, если сервис пропустил ошибку без перевода
+	{err: repositories.ErrUserNotFound, code: codes.NotFound, reason: "USER_NOT_FOUND"},
+	{err: repositories.ErrLoginTaken, code: codes.AlreadyExists, reason: "LOGIN_TAKEN", field: "login"},
+	{err: repositories.ErrPostNotFound, code: codes.NotFound, reason: "POST_NOT_FOUND"},
+	{err: repositories.ErrCommentNotFound, code: codes.NotFound, reason: "COMMENT_NOT_FOUND"},
+	{err: repositories.ErrParentNotFound, code: codes.NotFound, reason: "PARENT_NOT_FOUND", field: "parent_id"},
+}
+
+// grpcErr переводит ошибку сервисов и репозиториев в статус gRPC по таблице
+// domainErrors. Уже готовые статусы и ошибки контекста проходят как есть,
+// всё остальное становится Internal, а причина пишется в лог.
+func grpcErr(ctx context.Context, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	for _, de := range domainErrors {
+		if errors.Is(err, de.err) {
+			return de.status()
+		}
+	}
+	return internalErr(ctx, err, "internal server error")
+}
+
+func (de domainError) status() error {
+	msg := de.msg
+	if msg == "" {
+		msg = de.err.Error()
+	}
+
+	info := &errdetails.ErrorInfo{Reason: de.reason, Domain: ErrorDomain}
+	if de.field != "" {
+		info.Metadata = map[string]string{"field": de.field}
+	}
+
+	var (
+		st  *status.Status
+		err error
+	)
+	if de.code == codes.InvalidArgument && de.field != "" {
+		st, err = status.New(de.code, msg).WithDetails(badRequest(de.field, msg), info)
+	} else {
+		st, err = status.New(de.code, msg).WithDetails(info)
+	}
+	if err != nil {
+		return status.Error(de.code, msg)
+	}
+	return st.Err()
+}
+
+// invalidArgument — ошибка разбора поля запроса (например, невалидный UUID).
+func invalidArgument(field, description string) error {
+	msg := field + " " + description
+	st, err := status.New(codes.InvalidArgument, msg).WithDetails(
+		badRequest(field, description),
+		&errdetails.ErrorInfo{Reason: "INVALID_ARGUMENT", Domain: ErrorDomain, Metadata: map[string]string{"field": field}},
+	)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, msg)
+	}
+	return st.Err()
+}
+
+func badRequest(field, description string) *errdetails.BadRequest {
+	return &errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+		{Field: field, Description: description},
+	}}
+}
@@ -2,19 +2,36 @@ package grpc
 
 import (
 	"context"
-	"errors"
 	"github.com/Parnishkaspb/ozon_posts/internal/app"
-	"github.com/Parnishkaspb/ozon_posts/internal/auth"
+	authhelper "github.com/Parnishkaspb/ozon_posts/internal/auth/helper"
+	"github.com/Parnishkaspb/ozon_posts/internal/events"
 	"github.com/Parnishkaspb/ozon_posts/internal/models"
 	"github.com/Parnishkaspb/ozon_posts/internal/services/comments"
 	"github.com/Parnishkaspb/ozon_posts/internal/services/posts"
 	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
 	"github.com/google/uuid"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"slices"
 )
 
+// replayPageSize — по сколько комментариев WatchComments дочитывает историю.
+const replayPageSize = 100
+
+var commentKinds = map[servicepb.CommentEventType]events.Kind{
+	servicepb.CommentEventType_COMMENT_EVENT_TYPE_CREATED: events.KindCommentCreated,
+	servicepb.CommentEventType_COMMENT_EVENT_TYPE_EDITED:  events.KindCommentEdited,
+	servicepb.CommentEventType_COMMENT_EVENT_TYPE_DELETED: events.KindCommentDeleted,
+}
+
+var commentEventTypes = map[events.Kind]servicepb.CommentEventType{
+	events.KindCommentCreated: servicepb.CommentEventType_COMMENT_EVENT_TYPE_CREATED,
+	events.KindCommentEdited:  servicepb.CommentEventType_COMMENT_EVENT_TYPE_EDITED,
+	events.KindCommentDeleted: servicepb.CommentEventType_COMMENT_EVENT_TYPE_DELETED,
+}
+
 type Handler struct {
 	servicepb.UnimplementedAuthServiceServer
 	servicepb.UnimplementedUserServiceServer
@@ -30,41 +47,91 @@ func New(a *app.App) *Handler {
 
 func (h *Handler) Login(ctx context.Context, req *servicepb.LoginRequest) (*servicepb.LoginResponse, error) {
 
-	token, err := h.app.Auth.Authenticate(ctx, req.GetLogin(), req.GetPassword())
+	tokens, err := h.app.Auth.Authenticate(ctx, req.GetLogin(), req.GetPassword())
 
 	if err != nil {
-		if errors.Is(err, auth.ErrEmpty) {
-			return nil, status.Error(codes.InvalidArgument, auth.ErrEmpty.Error())
-		}
-		if errors.Is(err, auth.ErrIncorrect) {
-			return nil, status.Error(codes.Unauthenticated, "invalid login or password")
-		}
-		return nil, status.Error(codes.Internal, "internal authentication error")
+		return nil, grpcErr(ctx, err)
 	}
 
 	return &servicepb.LoginResponse{
-		Token: token,
+		Token:        tokens.Access,
+		RefreshToken: tokens.Refresh,
+	}, nil
+}
+
+func (h *Handler) RefreshToken(ctx context.Context, req *servicepb.RefreshTokenRequest) (*servicepb.RefreshTokenResponse, error) {
+	tokens, err := h.app.Auth.Refresh(ctx, req.GetRefreshToken())
+	if err != nil {
+		return nil, grpcErr(ctx, err)
+	}
+
+	return &servicepb.RefreshTokenResponse{
+		Token:        tokens.Access,
+		RefreshToken: tokens.Refresh,
 	}, nil
 }
 
+func (h *Handler) Logout(ctx context.Context, req *servicepb.LogoutRequest) (*servicepb.LogoutResponse, error) {
+	if err := h.app.Auth.Logout(ctx, req.GetRefreshToken()); err != nil {
+		return nil, grpcErr(ctx, err)
+	}
+
+	return &servicepb.LogoutResponse{}, nil
+}
+
+func (h *Handler) CheckSession(ctx context.Context, req *servicepb.CheckSessionRequest) (*servicepb.CheckSessionResponse, error) {
+	sessionID, err := uuid.Parse(req.GetSessionId())
+	if err != nil {
+		return nil, invalidArgument("session_id", "must be a valid UUID")
+	}
+
+	active, err := h.app.Auth.SessionActive(ctx, sessionID)
+	if err != nil {
+		return nil, grpcErr(ctx, err)
+	}
+
+	return &servicepb.CheckSessionResponse{Active: active}, nil
+}
+
+func (h *Handler) GetJWKS(ctx context.Context, req *servicepb.GetJWKSRequest) (*servicepb.GetJWKSResponse, error) {
+	keys := h.app.JWT.JWKS()
+	out := make([]*servicepb.JWK, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, &servicepb.JWK{
+			Kid: k.KID,
+			Kty: k.KTY,
+			Alg: k.ALG,
+			Use: k.Use,
+			Crv: k.CRV,
+			X:   k.X,
+			N:   k.N,
+			E:   k.E,
+		})
+	}
+
+	return &servicepb.GetJWKSResponse{Keys: out}, nil
+}
+
+func (h *Handler) CreateUser(ctx context.Context, req *servicepb.CreateUserRequest) (*servicepb.CreateUserResponse, error) {
+	user, err := h.app.UserSRV.CreateUser(ctx, req.GetLogin(), req.GetPassword(), req.GetName(), req.GetSurname())
+	if err != nil {
+		return nil, grpcErr(ctx, err)
+	}
+
+	return &servicepb.CreateUserResponse{Id: user.ID.String()}, nil
+}
+
 func (h *Handler) CreatePost(ctx context.Context, req *servicepb.CreatePostRequest) (*servicepb.CreatePostResponse, error) {
 
-	authorID, err := uuid.Parse(req.GetAuthorId())
+	authorID, err := callerID(ctx, req.GetAuthorId())
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "author_id must be a valid UUID")
+		return nil, err
 	}
 
 	post, err := h.app.PostSRV.CreatePost(ctx, authorID, req.GetText(), req.GetWithoutComment())
 
 	if err != nil {
-		switch {
-		case errors.Is(err, posts.ErrAuthorIDRequired):
-			return nil, status.Error(codes.InvalidArgument, posts.ErrAuthorIDRequired.Error())
-		case errors.Is(err, posts.ErrTextRequired):
-			return nil, status.Error(codes.InvalidArgument, posts.ErrTextRequired.Error())
-		default:
-			return nil, status.Error(codes.Internal, "internal error")
-		}
+		return nil, grpcErr(ctx, err)
 	}
 
 	return &servicepb.CreatePostResponse{
@@ -80,9 +147,22 @@ func (h *Handler) CreatePost(ctx context.Context, req *servicepb.CreatePostReque
 }
 
 func (h *Handler) GetPosts(ctx context.Context, req *servicepb.GetPostsRequest) (*servicepb.GetPostsResponse, error) {
+	if len(req.GetIds()) > 0 {
+		found, missing, err := h.app.PostSRV.GetPostsByIDs(ctx, req.GetIds())
+		if err != nil {
+			return nil, grpcErr(ctx, err)
+		}
+
+		result := make([]*servicepb.Post, 0, len(found))
+		for _, p := range found {
+			result = append(result, posts.ToPBPost(p))
+		}
+		return &servicepb.GetPostsResponse{Posts: result, MissingIds: missing}, nil
+	}
+
 	postsAnswer, endCursor, hasNext, err := h.app.PostSRV.GetPostsByPage(ctx, int(req.GetFirst()), req.GetAfter())
 	if err != nil {
-		return nil, status.Error(codes.Internal, "internal server error")
+		return nil, grpcErr(ctx, err)
 	}
 
 	return &servicepb.GetPostsResponse{
@@ -93,9 +173,13 @@ func (h *Handler) GetPosts(ctx context.Context, req *servicepb.GetPostsRequest)
 }
 
 func (h *Handler) GetPost(ctx context.Context, req *servicepb.GetPostRequest) (*servicepb.GetPostResponse, error) {
+	if _, err := uuid.Parse(req.GetId()); err != nil {
+		return nil, invalidArgument("id", "must be a valid UUID")
+	}
+
 	postsFound, err := h.app.PostSRV.GetAllPosts(ctx, []string{req.GetId()})
 	if err != nil {
-		return nil, status.Error(codes.Internal, "internal server error")
+		return nil, grpcErr(ctx, err)
 	}
 	if len(postsFound) == 0 {
 		return &servicepb.GetPostResponse{}, nil
@@ -114,10 +198,77 @@ func (h *Handler) GetPost(ctx context.Context, req *servicepb.GetPostRequest) (*
 	}, nil
 }
 
+func (h *Handler) UpdatePost(ctx context.Context, req *servicepb.UpdatePostRequest) (*servicepb.UpdatePostResponse, error) {
+	postID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, invalidArgument("id", "must be a valid UUID")
+	}
+
+	authorID, err := callerID(ctx, req.GetAuthorId())
+	if err != nil {
+		return nil, err
+	}
+
+	post, err := h.app.PostSRV.UpdatePost(ctx, postID, authorID, req.Text, req.WithoutComment)
+	if err != nil {
+		return nil, grpcErr(ctx, err)
+	}
+
+	return &servicepb.UpdatePostResponse{
+		Post: &servicepb.Post{
+			Id:             post.ID.String(),
+			AuthorId:       post.AuthorID.String(),
+			Text:           post.Text,
+			WithoutComment: post.WithoutComment,
+			CreatedAt:      timestamppb.New(post.CreatedAt),
+			UpdatedAt:      timestamppb.New(post.UpdatedAt),
+		},
+	}, nil
+}
+
+func (h *Handler) DeletePost(ctx context.Context, req *servicepb.DeletePostRequest) (*servicepb.DeletePostResponse, error) {
+	postID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, invalidArgument("id", "must be a valid UUID")
+	}
+
+	authorID, err := callerID(ctx, req.GetAuthorId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.app.PostSRV.DeletePost(ctx, postID, authorID); err != nil {
+		return nil, grpcErr(ctx, err)
+	}
+
+	return &servicepb.DeletePostResponse{}, nil
+}
+
+// WatchPosts держит поток открытым, пока клиент не отключится, и отправляет
+// каждый новый пост ленты.
+func (h *Handler) WatchPosts(req *servicepb.WatchPostsRequest, stream grpc.ServerStreamingServer[servicepb.WatchPostsResponse]) error {
+	live := h.app.Events.Subscribe(stream.Context(), events.Filter{Kinds: []events.Kind{events.KindPostCreated}})
+
+	for ev := range live {
+		resp := &servicepb.WatchPostsResponse{
+			Post:    posts.ToPBPost(ev.Post),
+			Dropped: int32(ev.Dropped),
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+
+	if stream.Context().Err() == nil {
+		return status.Error(codes.Unavailable, "server is shutting down")
+	}
+	return nil
+}
+
 func (h *Handler) GetUsers(ctx context.Context, req *servicepb.GetUsersRequest) (*servicepb.GetUsersResponse, error) {
 	users, err := h.app.UserSRV.GetUsersByIds(ctx, req.GetIds())
 	if err != nil {
-		return nil, status.Error(codes.Internal, "internal server error")
+		return nil, grpcErr(ctx, err)
 	}
 
 	result := make([]*servicepb.User, 0, len(users))
@@ -133,26 +284,26 @@ func (h *Handler) GetUsers(ctx context.Context, req *servicepb.GetUsersRequest)
 }
 
 func (h *Handler) CreateComment(ctx context.Context, req *servicepb.CreateCommentRequest) (*servicepb.CreateCommentResponse, error) {
-	uuidPostId, err := uuid.Parse(req.GetPostId())
+	uuidAuthorId, err := callerID(ctx, req.GetAuthorId())
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "post_id must be a valid UUID")
+		return nil, err
 	}
 
-	err = h.app.PostSRV.CanWriteComment(ctx, uuidPostId)
+	uuidPostId, err := uuid.Parse(req.GetPostId())
 	if err != nil {
-		return nil, status.Error(codes.PermissionDenied, "permission denied")
+		return nil, invalidArgument("post_id", "must be a valid UUID")
 	}
 
-	uuidAuthorId, err := uuid.Parse(req.GetAuthorId())
+	err = h.app.PostSRV.CanWriteComment(ctx, uuidPostId)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "author_id must be a valid UUID")
+		return nil, grpcErr(ctx, err)
 	}
 
 	var comment *models.Comment
 	if req.GetParentId() != "" {
-		uuidParentId, err := uuid.Parse(req.GetParentId())
-		if err != nil {
-			return nil, status.Error(codes.InvalidArgument, "parent_id must be a valid UUID")
+		uuidParentId, parseErr := uuid.Parse(req.GetParentId())
+		if parseErr != nil {
+			return nil, invalidArgument("parent_id", "must be a valid UUID")
 		}
 
 		comment, err = h.app.CommentSRV.CommentAnswer(ctx, req.GetText(), uuidAuthorId, uuidPostId, uuidParentId)
@@ -161,11 +312,7 @@ func (h *Handler) CreateComment(ctx context.Context, req *servicepb.CreateCommen
 	}
 
 	if err != nil {
-		if errors.Is(err, comments.ErrCantWriteComment) {
-			return nil, status.Error(codes.InvalidArgument, comments.ErrCantWriteComment.Error())
-		}
-
-		return nil, status.Error(codes.Internal, "internal server error")
+		return nil, grpcErr(ctx, err)
 	}
 
 	parentID := ""
@@ -186,18 +333,166 @@ func (h *Handler) CreateComment(ctx context.Context, req *servicepb.CreateCommen
 func (h *Handler) GetComments(ctx context.Context, req *servicepb.GetCommentsRequest) (*servicepb.GetCommentsResponse, error) {
 	resp, err := h.app.CommentSRV.GetComments(ctx, req)
 	if err != nil {
-		return nil, grpcErr(err)
+		return nil, grpcErr(ctx, err)
+	}
+	return resp, nil
+}
+
+func (h *Handler) GetCommentsSince(ctx context.Context, req *servicepb.GetCommentsSinceRequest) (*servicepb.GetCommentsSinceResponse, error) {
+	resp, err := h.app.CommentSRV.GetCommentsSince(ctx, req)
+	if err != nil {
+		return nil, grpcErr(ctx, err)
+	}
+	return resp, nil
+}
+
+func (h *Handler) GetCommentsByIDs(ctx context.Context, req *servicepb.GetCommentsByIDsRequest) (*servicepb.GetCommentsByIDsResponse, error) {
+	resp, err := h.app.CommentSRV.GetCommentsByIDs(ctx, req)
+	if err != nil {
+		return nil, grpcErr(ctx, err)
 	}
 	return resp, nil
 }
 
-func grpcErr(err error) error {
-	switch {
-	case errors.Is(err, comments.ErrPostIDRequired):
-		return status.Error(codes.InvalidArgument, err.Error())
-	case errors.Is(err, comments.ErrInvalidCursor):
-		return status.Error(codes.InvalidArgument, err.Error())
-	default:
-		return status.Error(codes.Internal, "internal server error")
+func (h *Handler) EditComment(ctx context.Context, req *servicepb.EditCommentRequest) (*servicepb.EditCommentResponse, error) {
+	commentID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, invalidArgument("id", "must be a valid UUID")
+	}
+
+	authorID, err := callerID(ctx, req.GetAuthorId())
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := h.app.CommentSRV.EditComment(ctx, commentID, authorID, req.GetText())
+	if err != nil {
+		return nil, grpcErr(ctx, err)
+	}
+
+	return &servicepb.EditCommentResponse{Comment: comments.ToPB(comment)}, nil
+}
+
+func (h *Handler) DeleteComment(ctx context.Context, req *servicepb.DeleteCommentRequest) (*servicepb.DeleteCommentResponse, error) {
+	commentID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, invalidArgument("id", "must be a valid UUID")
+	}
+
+	authorID, err := callerID(ctx, req.GetAuthorId())
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := h.app.CommentSRV.DeleteComment(ctx, commentID, authorID)
+	if err != nil {
+		return nil, grpcErr(ctx, err)
+	}
+
+	return &servicepb.DeleteCommentResponse{Comment: comments.ToPB(comment)}, nil
+}
+
+// WatchComments держит поток открытым, пока клиент не отключится, и отправляет
+// события types о комментариях поста (или ответах на parent_id); без types —
+// только новые комментарии. С after сначала досылает комментарии новее
+// курсора, затем переключается на живые события.
+func (h *Handler) WatchComments(req *servicepb.WatchCommentsRequest, stream grpc.ServerStreamingServer[servicepb.WatchCommentsResponse]) error {
+	postID, err := uuid.Parse(req.GetPostId())
+	if err != nil {
+		return invalidArgument("post_id", "must be a valid UUID")
+	}
+
+	filter := events.Filter{PostID: postID}
+	if req.GetParentId() != "" {
+		parentID, err := uuid.Parse(req.GetParentId())
+		if err != nil {
+			return invalidArgument("parent_id", "must be a valid UUID")
+		}
+		filter.ParentID = &parentID
+	}
+	for _, t := range req.GetTypes() {
+		kind, ok := commentKinds[t]
+		if !ok {
+			return invalidArgument("types", "unknown comment event type")
+		}
+		filter.Kinds = append(filter.Kinds, kind)
+	}
+
+	// подписываемся до чтения истории, чтобы не потерять комментарии между ними
+	live := h.app.Events.Subscribe(stream.Context(), filter)
+
+	replayed := make(map[string]struct{})
+	if len(filter.Kinds) == 0 || slices.Contains(filter.Kinds, events.KindCommentCreated) {
+		replayed, err = h.replayComments(stream, req)
+		if err != nil {
+			return err
+		}
+	}
+
+	for ev := range live {
+		if _, ok := replayed[ev.Comment.ID.String()]; ok && ev.Kind == events.KindCommentCreated {
+			continue
+		}
+		resp := &servicepb.WatchCommentsResponse{
+			Comment: comments.ToPB(ev.Comment),
+			Dropped: int32(ev.Dropped),
+			Type:    commentEventTypes[ev.Kind],
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+
+	// канал закрылся без отмены клиента — шина остановлена при завершении сервера
+	if stream.Context().Err() == nil {
+		return status.Error(codes.Unavailable, "server is shutting down")
+	}
+	return nil
+}
+
+// replayComments отправляет комментарии новее req.After и возвращает их id.
+func (h *Handler) replayComments(stream grpc.ServerStreamingServer[servicepb.WatchCommentsResponse], req *servicepb.WatchCommentsRequest) (map[string]struct{}, error) {
+	replayed := make(map[string]struct{})
+	if req.GetAfter() == "" {
+		return replayed, nil
+	}
+
+	ctx := stream.Context()
+	after := req.GetAfter()
+	for {
+		page, err := h.app.CommentSRV.GetCommentsSince(ctx, &servicepb.GetCommentsSinceRequest{
+			PostId:   req.GetPostId(),
+			ParentId: req.GetParentId(),
+			First:    replayPageSize,
+			After:    after,
+		})
+		if err != nil {
+			return nil, grpcErr(ctx, err)
+		}
+
+		for _, c := range page.GetComments() {
+			replayed[c.GetId()] = struct{}{}
+			if err := stream.Send(&servicepb.WatchCommentsResponse{Comment: c, Type: servicepb.CommentEventType_COMMENT_EVENT_TYPE_CREATED}); err != nil {
+				return nil, err
+			}
+		}
+
+		if !page.GetHasNextPage() {
+			return replayed, nil
+		}
+		after = page.GetEndCursor()
+	}
+}
+
+// callerID возвращает пользователя, которого AuthInterceptor положил в контекст.
+// author_id из запроса оставлен для совместимости и должен совпадать с вызывающим.
+func callerID(ctx context.Context, claimed string) (uuid.UUID, error) {
+	u, ok := authhelper.FromContext(ctx)
+	if !ok || u == nil {
+		return uuid.Nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+	if claimed != "" && claimed != u.ID.String() {
+		return uuid.Nil, status.Error(codes.PermissionDenied, "author_id does not match the authenticated user")
 	}
+	return u.ID, nil
 }
@@ -1,29 +1,75 @@
 package grpc
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/Parnishkaspb/ozon_posts/internal/services/comments"
+	"github.com/Parnishkaspb/ozon_posts/internal/services/posts"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 func TestGrpcErr(t *testing.T) {
 	tests := []struct {
-		name string
-		err  error
-		code codes.Code
+		name   string
+		err    error
+		code   codes.Code
+		reason string
+		field  string
 	}{
 		{
-			name: "post required",
-			err:  comments.ErrPostIDRequired,
-			code: codes.InvalidArgument,
+			name:   "post required",
+			err:    comments.ErrPostIDRequired,
+			code:   codes.InvalidArgument,
+			reason: "POST_ID_REQUIRED",
+			field:  "post_id",
 		},
 		{
-			name: "invalid cursor",
-			err:  comments.ErrInvalidCursor,
-			code: codes.InvalidArgument,
+			name:   "invalid cursor",
+			err:    comments.ErrInvalidCursor,
+			code:   codes.InvalidArgument,
+			reason: "INVALID_CURSOR",
+			field:  "after",
+		},
+		{
+			name:   "wrapped post cursor",
+			err:    fmt.Errorf("%w: illegal base64 data", posts.ErrInvalidCursor),
+			code:   codes.InvalidArgument,
+			reason: "INVALID_CURSOR",
+			field:  "after",
+		},
+		{
+			name:   "text too long",
+			err:    comments.ErrMax2000Symbols,
+			code:   codes.InvalidArgument,
+			reason: "TEXT_TOO_LONG",
+			field:  "text",
+		},
+		{
+			name:   "post not found",
+			err:    posts.ErrPostNotFound,
+			code:   codes.NotFound,
+			reason: "POST_NOT_FOUND",
+		},
+		{
+			name:   "parent not found",
+			err:    comments.ErrParentNotFound,
+			code:   codes.NotFound,
+			reason: "PARENT_NOT_FOUND",
+		},
+		{
+			name: "deadline",
+			err:  fmt.Errorf("query: %w", context.DeadlineExceeded),
+			code: codes.DeadlineExceeded,
+		},
+		{
+			name: "ready status",
+			err:  status.Error(codes.Unauthenticated, "authentication required"),
+			code: codes.Unauthenticated,
 		},
 		{
 			name: "internal",
@@ -34,13 +80,49 @@ func TestGrpcErr(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			st, ok := status.FromError(grpcErr(tt.err))
+			st, ok := status.FromError(grpcErr(context.Background(), tt.err))
 			if !ok {
 				t.Fatalf("expected grpc status")
 			}
 			if st.Code() != tt.code {
 				t.Fatalf("expected %v, got %v", tt.code, st.Code())
 			}
+
+			var (
+				info       *errdetails.ErrorInfo
+				badRequest *errdetails.BadRequest
+			)
+			for _, d := range st.Details() {
+				switch d := d.(type) {
+				case *errdetails.ErrorInfo:
+					info = d
+				case *errdetails.BadRequest:
+					badRequest = d
+				}
+			}
+
+			if tt.reason == "" {
+				if info != nil {
+					t.Fatalf("unexpected ErrorInfo %v", info)
+				}
+				return
+			}
+			if info == nil || info.GetReason() != tt.reason || info.GetDomain() != ErrorDomain {
+				t.Fatalf("expected ErrorInfo %s, got %v", tt.reason, info)
+			}
+			if tt.code != codes.InvalidArgument {
+				return
+			}
+			if badRequest == nil || len(badRequest.GetFieldViolations()) != 1 || badRequest.GetFieldViolations()[0].GetField() != tt.field {
+				t.Fatalf("expected BadRequest for %s, got %v", tt.field, badRequest)
+			}
 		})
 	}
 }
+
+func TestGrpcErrHidesInternalCause(t *testing.T) {
+	err := grpcErr(context.Background(), fmt.Errorf("%w: db down", errors.New("pgx")))
+	if status.Convert(err).Message() != "internal server error" {
+		t.Fatalf("cause must not leak: %v", err)
+	}
+}
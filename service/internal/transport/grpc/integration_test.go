@@ -2,13 +2,20 @@ package grpc
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/Parnishkaspb/ozon_posts/internal/app"
 	"github.com/Parnishkaspb/ozon_posts/internal/auth"
+	authhelper "github.com/Parnishkaspb/ozon_posts/internal/auth/helper"
 	"github.com/Parnishkaspb/ozon_posts/internal/config"
+	"github.com/Parnishkaspb/ozon_posts/internal/events"
+	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	"github.com/Parnishkaspb/ozon_posts/internal/services/comments"
 	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -17,15 +24,23 @@ func newMemoryHandler(t *testing.T) *Handler {
 	t.Helper()
 	cfg := &config.Config{
 		Storage: config.StorageConfig{Driver: "memory"},
-		JWT:     config.Token{Secret: "secret", TTL: time.Minute},
+		JWT:     config.Token{TTL: time.Minute},
 	}
-	a, err := app.New(context.Background(), cfg, auth.New("secret", time.Minute))
+	key, err := auth.GenerateSigningKey("test")
+	if err != nil {
+		t.Fatalf("generate signing key: %v", err)
+	}
+	a, err := app.New(context.Background(), cfg, auth.New(time.Minute, key))
 	if err != nil {
 		t.Fatalf("app.New: %v", err)
 	}
 	return New(a)
 }
 
+func asUser(ctx context.Context, userID string) context.Context {
+	return authhelper.WithUser(ctx, &models.User{ID: uuid.MustParse(userID)})
+}
+
 func TestHandler_AuthAndPostsFlow(t *testing.T) {
 	h := newMemoryHandler(t)
 	ctx := context.Background()
@@ -40,6 +55,7 @@ func TestHandler_AuthAndPostsFlow(t *testing.T) {
 		t.Fatalf("get users failed: %v", err)
 	}
 	authorID := usersResp.GetUsers()[0].GetId()
+	ctx = asUser(ctx, authorID)
 
 	createResp, err := h.CreatePost(ctx, &servicepb.CreatePostRequest{AuthorId: authorID, Text: "hello", WithoutComment: true})
 	if err != nil {
@@ -59,6 +75,29 @@ func TestHandler_AuthAndPostsFlow(t *testing.T) {
 	if len(pageResp.GetPosts()) != 1 || pageResp.GetPosts()[0].GetId() != postID {
 		t.Fatalf("unexpected posts page")
 	}
+
+	secondResp, err := h.CreatePost(ctx, &servicepb.CreatePostRequest{AuthorId: authorID, Text: "second"})
+	if err != nil {
+		t.Fatalf("create second post failed: %v", err)
+	}
+	secondID := secondResp.GetPost().GetId()
+
+	missingID := uuid.NewString()
+	byIDsResp, err := h.GetPosts(ctx, &servicepb.GetPostsRequest{Ids: []string{postID, missingID, secondID}})
+	if err != nil {
+		t.Fatalf("get posts by ids failed: %v", err)
+	}
+	if len(byIDsResp.GetPosts()) != 2 || byIDsResp.GetPosts()[0].GetId() != postID || byIDsResp.GetPosts()[1].GetId() != secondID {
+		t.Fatalf("unexpected posts by ids")
+	}
+	if len(byIDsResp.GetMissingIds()) != 1 || byIDsResp.GetMissingIds()[0] != missingID {
+		t.Fatalf("unexpected missing ids: %v", byIDsResp.GetMissingIds())
+	}
+
+	_, err = h.GetPosts(ctx, &servicepb.GetPostsRequest{Ids: []string{"bad-uuid"}})
+	if st, ok := status.FromError(err); !ok || st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
 }
 
 func TestHandler_CommentsHierarchyFlow(t *testing.T) {
@@ -70,6 +109,7 @@ func TestHandler_CommentsHierarchyFlow(t *testing.T) {
 		t.Fatalf("get users failed: %v", err)
 	}
 	authorID := usersResp.GetUsers()[0].GetId()
+	ctx = asUser(ctx, authorID)
 
 	postResp, err := h.CreatePost(ctx, &servicepb.CreatePostRequest{AuthorId: authorID, Text: "post", WithoutComment: true})
 	if err != nil {
@@ -99,6 +139,14 @@ func TestHandler_CommentsHierarchyFlow(t *testing.T) {
 		t.Fatalf("unexpected root comments")
 	}
 
+	byIDs, err := h.GetCommentsByIDs(ctx, &servicepb.GetCommentsByIDsRequest{Ids: []string{replyResp.GetComment().GetId(), rootID}})
+	if err != nil {
+		t.Fatalf("get comments by ids failed: %v", err)
+	}
+	if len(byIDs.GetComments()) != 2 || byIDs.GetComments()[0].GetParentId() != rootID || byIDs.GetComments()[1].GetId() != rootID {
+		t.Fatalf("unexpected comments by ids")
+	}
+
 	repliesPage, err := h.GetComments(ctx, &servicepb.GetCommentsRequest{PostId: postID, ParentId: rootID, First: 10})
 	if err != nil {
 		t.Fatalf("get replies failed: %v", err)
@@ -108,17 +156,659 @@ func TestHandler_CommentsHierarchyFlow(t *testing.T) {
 	}
 }
 
+func TestHandler_UpdateAndDeletePost(t *testing.T) {
+	h := newMemoryHandler(t)
+	ctx := context.Background()
+
+	usersResp, err := h.GetUsers(ctx, &servicepb.GetUsersRequest{})
+	if err != nil || len(usersResp.GetUsers()) == 0 {
+		t.Fatalf("get users failed: %v", err)
+	}
+	authorID := usersResp.GetUsers()[0].GetId()
+	ctx = asUser(ctx, authorID)
+
+	createResp, err := h.CreatePost(ctx, &servicepb.CreatePostRequest{AuthorId: authorID, Text: "tpyo"})
+	if err != nil {
+		t.Fatalf("create post failed: %v", err)
+	}
+	postID := createResp.GetPost().GetId()
+
+	text := "typo"
+	tests := []struct {
+		name string
+		req  *servicepb.UpdatePostRequest
+		code codes.Code
+	}{
+		{name: "bad id", req: &servicepb.UpdatePostRequest{Id: "bad-uuid", AuthorId: authorID, Text: &text}, code: codes.InvalidArgument},
+		{name: "nothing to update", req: &servicepb.UpdatePostRequest{Id: postID, AuthorId: authorID}, code: codes.InvalidArgument},
+		{name: "not found", req: &servicepb.UpdatePostRequest{Id: uuid.NewString(), AuthorId: authorID, Text: &text}, code: codes.NotFound},
+		{name: "not author", req: &servicepb.UpdatePostRequest{Id: postID, AuthorId: uuid.NewString(), Text: &text}, code: codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := h.UpdatePost(ctx, tt.req)
+			if st, ok := status.FromError(err); !ok || st.Code() != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, err)
+			}
+		})
+	}
+
+	updResp, err := h.UpdatePost(ctx, &servicepb.UpdatePostRequest{Id: postID, AuthorId: authorID, Text: &text})
+	if err != nil {
+		t.Fatalf("update post failed: %v", err)
+	}
+	if updResp.GetPost().GetText() != text {
+		t.Fatalf("unexpected updated text %q", updResp.GetPost().GetText())
+	}
+
+	_, err = h.DeletePost(ctx, &servicepb.DeletePostRequest{Id: postID, AuthorId: uuid.NewString()})
+	if st, ok := status.FromError(err); !ok || st.Code() != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", err)
+	}
+
+	if _, err := h.DeletePost(ctx, &servicepb.DeletePostRequest{Id: postID, AuthorId: authorID}); err != nil {
+		t.Fatalf("delete post failed: %v", err)
+	}
+
+	getResp, err := h.GetPost(ctx, &servicepb.GetPostRequest{Id: postID})
+	if err == nil && getResp.GetPost() != nil {
+		t.Fatalf("post must be deleted")
+	}
+}
+
+func TestHandler_EditAndDeleteComment(t *testing.T) {
+	h := newMemoryHandler(t)
+	ctx := context.Background()
+
+	usersResp, err := h.GetUsers(ctx, &servicepb.GetUsersRequest{})
+	if err != nil || len(usersResp.GetUsers()) == 0 {
+		t.Fatalf("get users failed: %v", err)
+	}
+	authorID := usersResp.GetUsers()[0].GetId()
+	ctx = asUser(ctx, authorID)
+
+	postResp, err := h.CreatePost(ctx, &servicepb.CreatePostRequest{AuthorId: authorID, Text: "post", WithoutComment: true})
+	if err != nil {
+		t.Fatalf("create post failed: %v", err)
+	}
+	postID := postResp.GetPost().GetId()
+
+	rootResp, err := h.CreateComment(ctx, &servicepb.CreateCommentRequest{PostId: postID, AuthorId: authorID, Text: "root"})
+	if err != nil {
+		t.Fatalf("create root comment failed: %v", err)
+	}
+	rootID := rootResp.GetComment().GetId()
+
+	if _, err := h.CreateComment(ctx, &servicepb.CreateCommentRequest{PostId: postID, AuthorId: authorID, ParentId: rootID, Text: "reply"}); err != nil {
+		t.Fatalf("create reply failed: %v", err)
+	}
+
+	editResp, err := h.EditComment(ctx, &servicepb.EditCommentRequest{Id: rootID, AuthorId: authorID, Text: "root v2"})
+	if err != nil {
+		t.Fatalf("edit comment failed: %v", err)
+	}
+	if editResp.GetComment().GetText() != "root v2" || editResp.GetComment().GetEditedAt() == nil {
+		t.Fatalf("unexpected edited comment")
+	}
+
+	_, err = h.DeleteComment(ctx, &servicepb.DeleteCommentRequest{Id: rootID, AuthorId: uuid.NewString()})
+	if st, ok := status.FromError(err); !ok || st.Code() != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", err)
+	}
+
+	if _, err := h.DeleteComment(ctx, &servicepb.DeleteCommentRequest{Id: rootID, AuthorId: authorID}); err != nil {
+		t.Fatalf("delete comment failed: %v", err)
+	}
+
+	rootsPage, err := h.GetComments(ctx, &servicepb.GetCommentsRequest{PostId: postID, First: 10})
+	if err != nil || len(rootsPage.GetComments()) != 1 {
+		t.Fatalf("get root comments failed: %v", err)
+	}
+	tomb := rootsPage.GetComments()[0]
+	if !tomb.GetDeleted() || tomb.GetText() != "[deleted]" || tomb.GetAuthorId() != "" {
+		t.Fatalf("unexpected tombstone: %+v", tomb)
+	}
+
+	repliesPage, err := h.GetComments(ctx, &servicepb.GetCommentsRequest{PostId: postID, ParentId: rootID, First: 10})
+	if err != nil || len(repliesPage.GetComments()) != 1 {
+		t.Fatalf("replies of deleted comment must stay reachable: %v", err)
+	}
+
+	_, err = h.EditComment(ctx, &servicepb.EditCommentRequest{Id: rootID, AuthorId: authorID, Text: "resurrect"})
+	if st, ok := status.FromError(err); !ok || st.Code() != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition, got %v", err)
+	}
+}
+
+func TestHandler_RefreshAndLogout(t *testing.T) {
+	h := newMemoryHandler(t)
+	ctx := context.Background()
+
+	loginResp, err := h.Login(ctx, &servicepb.LoginRequest{Login: "Ivan", Password: "MoscowNeverSleep"})
+	if err != nil || loginResp.GetRefreshToken() == "" {
+		t.Fatalf("login failed: %v", err)
+	}
+	user, err := h.app.JWT.ParseClaims(loginResp.GetToken())
+	if err != nil {
+		t.Fatalf("parse access token: %v", err)
+	}
+
+	refreshResp, err := h.RefreshToken(ctx, &servicepb.RefreshTokenRequest{RefreshToken: loginResp.GetRefreshToken()})
+	if err != nil || refreshResp.GetToken() == "" || refreshResp.GetRefreshToken() == loginResp.GetRefreshToken() {
+		t.Fatalf("refresh failed: %v", err)
+	}
+
+	check, err := h.CheckSession(ctx, &servicepb.CheckSessionRequest{SessionId: user.SessionID.String()})
+	if err != nil || !check.GetActive() {
+		t.Fatalf("expected active session: %v", err)
+	}
+
+	if _, err := h.Logout(ctx, &servicepb.LogoutRequest{RefreshToken: refreshResp.GetRefreshToken()}); err != nil {
+		t.Fatalf("logout failed: %v", err)
+	}
+
+	check, err = h.CheckSession(ctx, &servicepb.CheckSessionRequest{SessionId: user.SessionID.String()})
+	if err != nil || check.GetActive() {
+		t.Fatalf("expected revoked session: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "rotated token", token: loginResp.GetRefreshToken()},
+		{name: "revoked token", token: refreshResp.GetRefreshToken()},
+		{name: "empty token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := h.RefreshToken(ctx, &servicepb.RefreshTokenRequest{RefreshToken: tt.token})
+			if st, ok := status.FromError(err); !ok || st.Code() != codes.Unauthenticated {
+				t.Fatalf("expected Unauthenticated, got %v", err)
+			}
+		})
+	}
+}
+
+func TestHandler_GetJWKS(t *testing.T) {
+	h := newMemoryHandler(t)
+
+	resp, err := h.GetJWKS(context.Background(), &servicepb.GetJWKSRequest{})
+	if err != nil {
+		t.Fatalf("get jwks: %v", err)
+	}
+	if len(resp.GetKeys()) != 1 {
+		t.Fatalf("expected one key, got %d", len(resp.GetKeys()))
+	}
+	k := resp.GetKeys()[0]
+	if k.GetKid() != "test" || k.GetKty() != "OKP" || k.GetAlg() != "EdDSA" || k.GetX() == "" {
+		t.Fatalf("unexpected jwk: %+v", k)
+	}
+}
+
+func TestHandler_CreateUserAndLogin(t *testing.T) {
+	h := newMemoryHandler(t)
+	ctx := context.Background()
+
+	req := &servicepb.CreateUserRequest{Login: "Petr", Password: "WindowToEurope", Name: "Пётр", Surname: "Первый"}
+	resp, err := h.CreateUser(ctx, req)
+	if err != nil || resp.GetId() == "" {
+		t.Fatalf("create user failed: %v", err)
+	}
+
+	loginResp, err := h.Login(ctx, &servicepb.LoginRequest{Login: "Petr", Password: "WindowToEurope"})
+	if err != nil || loginResp.GetToken() == "" {
+		t.Fatalf("login of registered user failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		req  *servicepb.CreateUserRequest
+		code codes.Code
+	}{
+		{name: "duplicate login", req: req, code: codes.AlreadyExists},
+		{name: "weak password", req: &servicepb.CreateUserRequest{Login: "Anna", Password: "123", Name: "Анна", Surname: "Иоанновна"}, code: codes.InvalidArgument},
+		{name: "missing name", req: &servicepb.CreateUserRequest{Login: "Anna", Password: "long enough"}, code: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := h.CreateUser(ctx, tt.req)
+			if st, ok := status.FromError(err); !ok || st.Code() != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, err)
+			}
+		})
+	}
+}
+
 func TestHandler_CreatePostValidation(t *testing.T) {
 	h := newMemoryHandler(t)
 	ctx := context.Background()
+	callerID := uuid.NewString()
 
-	_, err := h.CreatePost(ctx, &servicepb.CreatePostRequest{AuthorId: "bad-uuid", Text: "x"})
-	if err == nil {
-		t.Fatalf("expected validation error")
+	tests := []struct {
+		name string
+		ctx  context.Context
+		req  *servicepb.CreatePostRequest
+		code codes.Code
+	}{
+		{name: "anonymous caller", ctx: ctx, req: &servicepb.CreatePostRequest{Text: "x"}, code: codes.Unauthenticated},
+		{name: "foreign author_id", ctx: asUser(ctx, callerID), req: &servicepb.CreatePostRequest{AuthorId: uuid.NewString(), Text: "x"}, code: codes.PermissionDenied},
+		{name: "empty text", ctx: asUser(ctx, callerID), req: &servicepb.CreatePostRequest{}, code: codes.InvalidArgument},
 	}
 
-	st, ok := status.FromError(err)
-	if !ok || st.Code() != codes.InvalidArgument {
-		t.Fatalf("expected InvalidArgument, got %v", err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := h.CreatePost(tt.ctx, tt.req)
+			if st, ok := status.FromError(err); !ok || st.Code() != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, err)
+			}
+		})
+	}
+}
+
+// fakeStream — серверный поток Watch*: отправленные ответы попадают в sent.
+type fakeStream[T any] struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *T
+}
+
+func newFakeStream[T any](ctx context.Context, buffer int) *fakeStream[T] {
+	return &fakeStream[T]{ctx: ctx, sent: make(chan *T, buffer)}
+}
+
+func (s *fakeStream[T]) Context() context.Context { return s.ctx }
+
+func (s *fakeStream[T]) Send(resp *T) error {
+	s.sent <- resp
+	return nil
+}
+
+func TestHandler_WatchComments(t *testing.T) {
+	h := newMemoryHandler(t)
+	ctx := context.Background()
+
+	usersResp, err := h.GetUsers(ctx, &servicepb.GetUsersRequest{})
+	if err != nil || len(usersResp.GetUsers()) == 0 {
+		t.Fatalf("get users failed: %v", err)
+	}
+	authorID := usersResp.GetUsers()[0].GetId()
+	ctx = asUser(ctx, authorID)
+
+	postResp, err := h.CreatePost(ctx, &servicepb.CreatePostRequest{Text: "post", WithoutComment: true})
+	if err != nil {
+		t.Fatalf("create post failed: %v", err)
+	}
+	postID := postResp.GetPost().GetId()
+
+	rootResp, err := h.CreateComment(ctx, &servicepb.CreateCommentRequest{PostId: postID, Text: "root"})
+	if err != nil {
+		t.Fatalf("create root comment failed: %v", err)
+	}
+	rootID := rootResp.GetComment().GetId()
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	stream := newFakeStream[servicepb.WatchCommentsResponse](watchCtx, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- h.WatchComments(&servicepb.WatchCommentsRequest{PostId: postID, ParentId: rootID}, stream)
+	}()
+
+	reply := func(text string) string {
+		t.Helper()
+		resp, err := h.CreateComment(ctx, &servicepb.CreateCommentRequest{PostId: postID, ParentId: rootID, Text: text})
+		if err != nil {
+			t.Fatalf("create reply failed: %v", err)
+		}
+		return resp.GetComment().GetId()
+	}
+
+	// подписка появляется асинхронно, поэтому пишем ответы, пока первый не дойдёт
+	deadline := time.After(2 * time.Second)
+	for received := false; !received; {
+		reply("reply")
+		select {
+		case resp := <-stream.sent:
+			c := resp.GetComment()
+			if c.GetParentId() != rootID {
+				t.Fatalf("unexpected comment %s", c.GetId())
+			}
+			received = true
+		case <-time.After(20 * time.Millisecond):
+		case <-deadline:
+			t.Fatalf("no comments received")
+		}
+	}
+	for len(stream.sent) > 0 {
+		<-stream.sent
+	}
+
+	if _, err := h.CreateComment(ctx, &servicepb.CreateCommentRequest{PostId: postID, Text: "another root"}); err != nil {
+		t.Fatalf("create root comment failed: %v", err)
+	}
+	lastID := reply("last reply")
+
+	select {
+	case resp := <-stream.sent:
+		c := resp.GetComment()
+		if c.GetId() != lastID {
+			t.Fatalf("expected only replies to %s, got %s", rootID, c.GetId())
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("reply was not delivered")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("watch returned error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("watch did not stop after cancel")
+	}
+}
+
+func TestHandler_WatchCommentsEndsOnShutdown(t *testing.T) {
+	h := newMemoryHandler(t)
+	postID := uuid.New()
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		stream := newFakeStream[servicepb.WatchCommentsResponse](watchCtx, 1)
+		done <- h.WatchComments(&servicepb.WatchCommentsRequest{PostId: postID.String()}, stream)
+	}()
+
+	// подписка на шине появляется асинхронно
+	deadline := time.Now().Add(2 * time.Second)
+	for !h.app.Events.HasSubscribers(events.KindCommentCreated, postID, nil) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	h.app.Events.Close()
+
+	select {
+	case err := <-done:
+		if status.Code(err) != codes.Unavailable {
+			t.Fatalf("expected Unavailable, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("watch did not stop after bus close")
+	}
+}
+
+func TestHandler_WatchCommentsReplaysFromCursor(t *testing.T) {
+	h := newMemoryHandler(t)
+	ctx := context.Background()
+
+	usersResp, err := h.GetUsers(ctx, &servicepb.GetUsersRequest{})
+	if err != nil || len(usersResp.GetUsers()) == 0 {
+		t.Fatalf("get users failed: %v", err)
+	}
+	ctx = asUser(ctx, usersResp.GetUsers()[0].GetId())
+
+	postResp, err := h.CreatePost(ctx, &servicepb.CreatePostRequest{Text: "post", WithoutComment: true})
+	if err != nil {
+		t.Fatalf("create post failed: %v", err)
+	}
+	postID := postResp.GetPost().GetId()
+
+	ids := make([]string, 0, 3)
+	for _, text := range []string{"first", "second", "third"} {
+		resp, err := h.CreateComment(ctx, &servicepb.CreateCommentRequest{PostId: postID, Text: text})
+		if err != nil {
+			t.Fatalf("create comment failed: %v", err)
+		}
+		ids = append(ids, resp.GetComment().GetId())
+	}
+
+	// последняя страница по убыванию заканчивается на самом старом комментарии
+	page, err := h.GetComments(ctx, &servicepb.GetCommentsRequest{PostId: postID, First: 3})
+	if err != nil {
+		t.Fatalf("get comments failed: %v", err)
+	}
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := newFakeStream[servicepb.WatchCommentsResponse](watchCtx, 16)
+	go func() {
+		_ = h.WatchComments(&servicepb.WatchCommentsRequest{PostId: postID, After: page.GetEndCursor()}, stream)
+	}()
+
+	for _, want := range ids[1:] {
+		select {
+		case resp := <-stream.sent:
+			c := resp.GetComment()
+			if c.GetId() != want {
+				t.Fatalf("expected replayed %s, got %s", want, c.GetId())
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("comment %s was not replayed", want)
+		}
+	}
+
+	err = h.WatchComments(&servicepb.WatchCommentsRequest{PostId: postID, After: "garbage"}, newFakeStream[servicepb.WatchCommentsResponse](watchCtx, 0))
+	if st, ok := status.FromError(err); !ok || st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for bad cursor, got %v", err)
+	}
+}
+
+func TestHandler_WatchCommentsEditAndDelete(t *testing.T) {
+	h := newMemoryHandler(t)
+	ctx := context.Background()
+
+	usersResp, err := h.GetUsers(ctx, &servicepb.GetUsersRequest{})
+	if err != nil || len(usersResp.GetUsers()) == 0 {
+		t.Fatalf("get users failed: %v", err)
+	}
+	ctx = asUser(ctx, usersResp.GetUsers()[0].GetId())
+
+	postResp, err := h.CreatePost(ctx, &servicepb.CreatePostRequest{Text: "post", WithoutComment: true})
+	if err != nil {
+		t.Fatalf("create post failed: %v", err)
+	}
+	postID := postResp.GetPost().GetId()
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := newFakeStream[servicepb.WatchCommentsResponse](watchCtx, 16)
+	go func() {
+		_ = h.WatchComments(&servicepb.WatchCommentsRequest{
+			PostId: postID,
+			Types: []servicepb.CommentEventType{
+				servicepb.CommentEventType_COMMENT_EVENT_TYPE_EDITED,
+				servicepb.CommentEventType_COMMENT_EVENT_TYPE_DELETED,
+			},
+		}, stream)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !h.app.Events.HasSubscribers(events.KindCommentEdited, uuid.MustParse(postID), nil) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	created, err := h.CreateComment(ctx, &servicepb.CreateCommentRequest{PostId: postID, Text: "text"})
+	if err != nil {
+		t.Fatalf("create comment failed: %v", err)
+	}
+	commentID := created.GetComment().GetId()
+	if _, err := h.EditComment(ctx, &servicepb.EditCommentRequest{Id: commentID, Text: "edited"}); err != nil {
+		t.Fatalf("edit comment failed: %v", err)
+	}
+	if _, err := h.DeleteComment(ctx, &servicepb.DeleteCommentRequest{Id: commentID}); err != nil {
+		t.Fatalf("delete comment failed: %v", err)
+	}
+
+	// создание в поток не попадает: запрошены только правки и удаления
+	want := []struct {
+		typ  servicepb.CommentEventType
+		text string
+	}{
+		{servicepb.CommentEventType_COMMENT_EVENT_TYPE_EDITED, "edited"},
+		{servicepb.CommentEventType_COMMENT_EVENT_TYPE_DELETED, comments.DeletedText},
+	}
+	for _, w := range want {
+		select {
+		case resp := <-stream.sent:
+			if resp.GetType() != w.typ || resp.GetComment().GetId() != commentID || resp.GetComment().GetText() != w.text {
+				t.Fatalf("got %v %q, want %v %q", resp.GetType(), resp.GetComment().GetText(), w.typ, w.text)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%v was not delivered", w.typ)
+		}
+	}
+}
+
+func TestHandler_WatchPosts(t *testing.T) {
+	h := newMemoryHandler(t)
+	ctx := context.Background()
+
+	usersResp, err := h.GetUsers(ctx, &servicepb.GetUsersRequest{})
+	if err != nil || len(usersResp.GetUsers()) == 0 {
+		t.Fatalf("get users failed: %v", err)
+	}
+	ctx = asUser(ctx, usersResp.GetUsers()[0].GetId())
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	stream := newFakeStream[servicepb.WatchPostsResponse](watchCtx, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- h.WatchPosts(&servicepb.WatchPostsRequest{}, stream)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !h.app.Events.HasSubscribers(events.KindPostCreated, uuid.Nil, nil) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	postResp, err := h.CreatePost(ctx, &servicepb.CreatePostRequest{Text: "post"})
+	if err != nil {
+		t.Fatalf("create post failed: %v", err)
+	}
+
+	select {
+	case resp := <-stream.sent:
+		if resp.GetPost().GetId() != postResp.GetPost().GetId() {
+			t.Fatalf("got post %s, want %s", resp.GetPost().GetId(), postResp.GetPost().GetId())
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("post was not delivered")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("watch returned error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("watch did not stop after cancel")
+	}
+}
+
+func TestHandler_WatchCommentsValidation(t *testing.T) {
+	h := newMemoryHandler(t)
+
+	tests := []struct {
+		name string
+		req  *servicepb.WatchCommentsRequest
+	}{
+		{name: "invalid post_id", req: &servicepb.WatchCommentsRequest{PostId: "nope"}},
+		{name: "invalid parent_id", req: &servicepb.WatchCommentsRequest{PostId: uuid.NewString(), ParentId: "nope"}},
+		{name: "unknown type", req: &servicepb.WatchCommentsRequest{PostId: uuid.NewString(), Types: []servicepb.CommentEventType{servicepb.CommentEventType_COMMENT_EVENT_TYPE_UNSPECIFIED}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := h.WatchComments(tt.req, newFakeStream[servicepb.WatchCommentsResponse](context.Background(), 0))
+			if st, ok := status.FromError(err); !ok || st.Code() != codes.InvalidArgument {
+				t.Fatalf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
+func TestHandler_ErrorMapping(t *testing.T) {
+	h := newMemoryHandler(t)
+	ctx := context.Background()
+
+	usersResp, err := h.GetUsers(ctx, &servicepb.GetUsersRequest{})
+	if err != nil || len(usersResp.GetUsers()) == 0 {
+		t.Fatalf("get users failed: %v", err)
+	}
+	authorID := usersResp.GetUsers()[0].GetId()
+	ctx = asUser(ctx, authorID)
+
+	open, err := h.CreatePost(ctx, &servicepb.CreatePostRequest{Text: "open", WithoutComment: true})
+	if err != nil {
+		t.Fatalf("create post failed: %v", err)
+	}
+	closed, err := h.CreatePost(ctx, &servicepb.CreatePostRequest{Text: "closed"})
+	if err != nil {
+		t.Fatalf("create post failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{
+			name: "malformed posts cursor",
+			call: func() error {
+				_, err := h.GetPosts(ctx, &servicepb.GetPostsRequest{First: 10, After: "garbage"})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "comment on missing post",
+			call: func() error {
+				_, err := h.CreateComment(ctx, &servicepb.CreateCommentRequest{PostId: uuid.NewString(), Text: "hi"})
+				return err
+			},
+			code: codes.NotFound,
+		},
+		{
+			name: "comment on post with comments disabled",
+			call: func() error {
+				_, err := h.CreateComment(ctx, &servicepb.CreateCommentRequest{PostId: closed.GetPost().GetId(), Text: "hi"})
+				return err
+			},
+			code: codes.PermissionDenied,
+		},
+		{
+			name: "reply to missing parent",
+			call: func() error {
+				_, err := h.CreateComment(ctx, &servicepb.CreateCommentRequest{PostId: open.GetPost().GetId(), ParentId: uuid.NewString(), Text: "hi"})
+				return err
+			},
+			code: codes.NotFound,
+		},
+		{
+			name: "empty comment",
+			call: func() error {
+				_, err := h.CreateComment(ctx, &servicepb.CreateCommentRequest{PostId: open.GetPost().GetId(), Text: "  "})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "comment too long",
+			call: func() error {
+				_, err := h.CreateComment(ctx, &servicepb.CreateCommentRequest{PostId: open.GetPost().GetId(), Text: strings.Repeat("я", 2001)})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if st, ok := status.FromError(tt.call()); !ok || st.Code() != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, st)
+			}
+		})
 	}
 }
@@ -0,0 +1,129 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Parnishkaspb/ozon_posts/internal/auth"
+	authhelper "github.com/Parnishkaspb/ozon_posts/internal/auth/helper"
+	"github.com/Parnishkaspb/ozon_posts/internal/models"
+	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionalphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+)
+
+// PublicMethods доступны без токена; остальные RPC требуют Bearer-токен в metadata.
+var PublicMethods = map[string]bool{
+	servicepb.AuthService_Login_FullMethodName:               true,
+	servicepb.AuthService_RefreshToken_FullMethodName:        true,
+	servicepb.AuthService_Logout_FullMethodName:              true,
+	servicepb.AuthService_CheckSession_FullMethodName:        true,
+	servicepb.AuthService_GetJWKS_FullMethodName:             true,
+	servicepb.UserService_CreateUser_FullMethodName:          true,
+	servicepb.UserService_GetUsers_FullMethodName:            true,
+	servicepb.PostService_GetPosts_FullMethodName:            true,
+	servicepb.PostService_GetPost_FullMethodName:             true,
+	servicepb.PostService_WatchPosts_FullMethodName:          true,
+	servicepb.CommentService_GetComments_FullMethodName:      true,
+	servicepb.CommentService_GetCommentsSince_FullMethodName: true,
+	servicepb.CommentService_GetCommentsByIDs_FullMethodName: true,
+	servicepb.CommentService_WatchComments_FullMethodName:    true,
+	healthpb.Health_Check_FullMethodName:                     true,
+	healthpb.Health_List_FullMethodName:                      true,
+	healthpb.Health_Watch_FullMethodName:                     true,
+	// reflection для grpcurl и Postman: отдаёт только описание API
+	reflectionpb.ServerReflection_ServerReflectionInfo_FullMethodName:      true,
+	reflectionalphapb.ServerReflection_ServerReflectionInfo_FullMethodName: true,
+}
+
+type TokenParser interface {
+	ParseClaims(tokenString string) (*auth.Claims, error)
+}
+
+type SessionChecker interface {
+	SessionActive(ctx context.Context, sessionID uuid.UUID) (bool, error)
+}
+
+// AuthInterceptor проверяет токен из metadata "authorization" и кладёт
+// пользователя в контекст. Для публичных методов токен необязателен.
+func AuthInterceptor(tokens TokenParser, sessions SessionChecker, public map[string]bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		user, err := authenticate(ctx, tokens, sessions, !public[info.FullMethod])
+		if err != nil && !public[info.FullMethod] {
+			return nil, err
+		}
+		if user != nil {
+			ctx = authhelper.WithUser(ctx, user)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// AuthStreamInterceptor — то же для потоковых RPC.
+func AuthStreamInterceptor(tokens TokenParser, sessions SessionChecker, public map[string]bool) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		user, err := authenticate(ctx, tokens, sessions, !public[info.FullMethod])
+		if err != nil && !public[info.FullMethod] {
+			return err
+		}
+		if user != nil {
+			ss = &authStream{ServerStream: ss, ctx: authhelper.WithUser(ctx, user)}
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context { return s.ctx }
+
+// authenticate разбирает токен; checkSession дополнительно сверяет сессию с
+// хранилищем. Публичным методам пользователь не нужен для решения о доступе,
+// а gateway шлёт токен с каждым батчем загрузчика, поэтому там запрос сессии
+// пропускается.
+func authenticate(ctx context.Context, tokens TokenParser, sessions SessionChecker, checkSession bool) (*models.User, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	const prefix = "Bearer "
+	if !strings.HasPrefix(values[0], prefix) {
+		return nil, status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+	}
+
+	claims, err := tokens.ParseClaims(strings.TrimSpace(strings.TrimPrefix(values[0], prefix)))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	if checkSession {
+		active, err := sessions.SessionActive(ctx, claims.SessionID)
+		if err != nil {
+			return nil, internalErr(ctx, err, "internal authentication error")
+		}
+		if !active {
+			return nil, status.Error(codes.Unauthenticated, "session revoked")
+		}
+	}
+
+	return &models.User{
+		ID:      claims.UserID,
+		Login:   claims.Login,
+		Name:    claims.Name,
+		Surname: claims.Surname,
+	}, nil
+}
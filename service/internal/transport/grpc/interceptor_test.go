@@ -0,0 +1,224 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Parnishkaspb/ozon_posts/internal/auth"
+	authhelper "github.com/Parnishkaspb/ozon_posts/internal/auth/helper"
+	servicepb "github.com/Parnishkaspb/ozon_posts_proto/gen/service/v1"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionalphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type mockSessions struct {
+	active bool
+	err    error
+	calls  int
+}
+
+func (m *mockSessions) SessionActive(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	m.calls++
+	return m.active, m.err
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	key, err := auth.GenerateSigningKey("test")
+	if err != nil {
+		t.Fatalf("generate signing key: %v", err)
+	}
+	tokens := auth.New(time.Minute, key)
+	userID := uuid.New()
+	token, err := tokens.GenerateToken(userID, uuid.New(), "Ivan", "Иван", "Грозный")
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+
+	const (
+		private = servicepb.PostService_CreatePost_FullMethodName
+		public  = servicepb.PostService_GetPosts_FullMethodName
+	)
+
+	tests := []struct {
+		name     string
+		method   string
+		header   string
+		sessions *mockSessions
+		code     codes.Code
+		wantUser bool
+		lookups  int
+	}{
+		{name: "private with token", method: private, header: "Bearer " + token, sessions: &mockSessions{active: true}, code: codes.OK, wantUser: true, lookups: 1},
+		{name: "private without token", method: private, sessions: &mockSessions{active: true}, code: codes.Unauthenticated},
+		{name: "private with garbage token", method: private, header: "Bearer garbage", sessions: &mockSessions{active: true}, code: codes.Unauthenticated},
+		{name: "private with basic auth", method: private, header: "Basic abc", sessions: &mockSessions{active: true}, code: codes.Unauthenticated},
+		{name: "private with revoked session", method: private, header: "Bearer " + token, sessions: &mockSessions{}, code: codes.Unauthenticated, lookups: 1},
+		{name: "private with session store down", method: private, header: "Bearer " + token, sessions: &mockSessions{err: errors.New("db down")}, code: codes.Internal, lookups: 1},
+		{name: "public without token", method: public, sessions: &mockSessions{}, code: codes.OK},
+		// публичный метод не ходит в хранилище сессий даже с токеном
+		{name: "public with token", method: public, header: "Bearer " + token, sessions: &mockSessions{err: errors.New("db down")}, code: codes.OK, wantUser: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.header != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.header))
+			}
+
+			var gotUser bool
+			handler := func(ctx context.Context, req any) (any, error) {
+				u, ok := authhelper.FromContext(ctx)
+				gotUser = ok && u.ID == userID
+				return nil, nil
+			}
+
+			interceptor := AuthInterceptor(tokens, tt.sessions, PublicMethods)
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if status.Code(err) != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, err)
+			}
+			if gotUser != tt.wantUser {
+				t.Fatalf("expected user in context=%v, got %v", tt.wantUser, gotUser)
+			}
+			if tt.sessions.calls != tt.lookups {
+				t.Fatalf("expected %d session lookups, got %d", tt.lookups, tt.sessions.calls)
+			}
+		})
+	}
+}
+
+type ctxStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *ctxStream) Context() context.Context { return s.ctx }
+
+func TestAuthStreamInterceptor(t *testing.T) {
+	key, err := auth.GenerateSigningKey("test")
+	if err != nil {
+		t.Fatalf("generate signing key: %v", err)
+	}
+	tokens := auth.New(time.Minute, key)
+	userID := uuid.New()
+	token, err := tokens.GenerateToken(userID, uuid.New(), "Ivan", "Иван", "Грозный")
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+
+	const public = servicepb.CommentService_WatchComments_FullMethodName
+
+	tests := []struct {
+		name     string
+		method   string
+		header   string
+		code     codes.Code
+		wantUser bool
+	}{
+		{name: "public without token", method: public, code: codes.OK},
+		{name: "public with token", method: public, header: "Bearer " + token, code: codes.OK, wantUser: true},
+		{name: "private without token", method: "/service.v1.CommentService/Private", code: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.header != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.header))
+			}
+
+			var gotUser bool
+			handler := func(srv any, ss grpc.ServerStream) error {
+				u, ok := authhelper.FromContext(ss.Context())
+				gotUser = ok && u.ID == userID
+				return nil
+			}
+
+			interceptor := AuthStreamInterceptor(tokens, &mockSessions{active: true}, PublicMethods)
+			err := interceptor(nil, &ctxStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: tt.method}, handler)
+			if status.Code(err) != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, err)
+			}
+			if gotUser != tt.wantUser {
+				t.Fatalf("expected user in context=%v, got %v", tt.wantUser, gotUser)
+			}
+		})
+	}
+}
+
+func TestReflectionWithoutToken(t *testing.T) {
+	key, err := auth.GenerateSigningKey("test")
+	if err != nil {
+		t.Fatalf("generate signing key: %v", err)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(grpc.ChainStreamInterceptor(
+		AuthStreamInterceptor(auth.New(time.Minute, key), &mockSessions{}, PublicMethods),
+	))
+	servicepb.RegisterCommentServiceServer(srv, servicepb.UnimplementedCommentServiceServer{})
+	reflection.Register(srv)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t.Run("v1", func(t *testing.T) {
+		stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+		if err != nil {
+			t.Fatalf("open stream: %v", err)
+		}
+		req := &reflectionpb.ServerReflectionRequest{MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{}}
+		if err := stream.Send(req); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("list services without token: %v", err)
+		}
+		var names []string
+		for _, s := range resp.GetListServicesResponse().GetService() {
+			names = append(names, s.GetName())
+		}
+		if !slices.Contains(names, servicepb.CommentService_ServiceDesc.ServiceName) {
+			t.Fatalf("services = %v, want %s", names, servicepb.CommentService_ServiceDesc.ServiceName)
+		}
+	})
+
+	t.Run("v1alpha", func(t *testing.T) {
+		stream, err := reflectionalphapb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+		if err != nil {
+			t.Fatalf("open stream: %v", err)
+		}
+		req := &reflectionalphapb.ServerReflectionRequest{MessageRequest: &reflectionalphapb.ServerReflectionRequest_ListServices{}}
+		if err := stream.Send(req); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+		if _, err := stream.Recv(); err != nil {
+			t.Fatalf("list services without token: %v", err)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Parnishkaspb/ozon_posts_pkg/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// LoggingInterceptor берёт request id из metadata (или создаёт новый), кладёт
+// его в контекст и пишет access-лог по каждому RPC. Ставится первым в цепочке,
+// чтобы в лог попадали и отказы аутентификации.
+func LoggingInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		id := requestID(ctx)
+		ctx = logging.WithRequestID(ctx, id)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(logging.RequestIDKey, id))
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logRPC(ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// LoggingStreamInterceptor — то же для потоковых RPC; запись пишется при закрытии потока.
+func LoggingStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id := requestID(ss.Context())
+		ctx := logging.WithRequestID(ss.Context(), id)
+		_ = ss.SetHeader(metadata.Pairs(logging.RequestIDKey, id))
+
+		start := time.Now()
+		err := handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+		logRPC(ctx, info.FullMethod, start, err)
+		return err
+	}
+}
+
+func requestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(logging.RequestIDKey); len(values) > 0 && logging.ValidRequestID(values[0]) {
+			return values[0]
+		}
+	}
+	return logging.NewRequestID()
+}
+
+func logRPC(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		level = slog.LevelError
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		attrs = append(attrs, slog.String("peer", p.Addr.String()))
+	}
+	if err != nil && code != codes.Internal {
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+	}
+	slog.LogAttrs(ctx, level, "rpc", attrs...)
+}
+
+// internalErr пишет в лог настоящую причину и отдаёт клиенту только msg.
+func internalErr(ctx context.Context, err error, msg string) error {
+	method, _ := grpc.Method(ctx)
+	slog.ErrorContext(ctx, msg, "method", method, "error", err)
+	return status.Error(codes.Internal, msg)
+}
@@ -0,0 +1,135 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/Parnishkaspb/ozon_posts_pkg/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// captureLogs подменяет логгер по умолчанию и возвращает записанные JSON-записи.
+func captureLogs(t *testing.T) func() []map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "info", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	return func() []map[string]any {
+		var out []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var rec map[string]any
+			if err := json.Unmarshal([]byte(line), &rec); err != nil {
+				t.Fatal(err)
+			}
+			out = append(out, rec)
+		}
+		return out
+	}
+}
+
+func TestLoggingInterceptor(t *testing.T) {
+	records := captureLogs(t)
+	interceptor := LoggingInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/service.v1.PostService/GetPosts"}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(logging.RequestIDKey, "from-gateway"))
+	var seen string
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, _ any) (any, error) {
+		seen = logging.RequestID(ctx)
+		return nil, internalErr(ctx, errors.New("db is on fire"), "internal server error")
+	})
+	if status.Code(err) != codes.Internal || status.Convert(err).Message() != "internal server error" {
+		t.Fatalf("cause must not leak to the client: %v", err)
+	}
+	if seen != "from-gateway" {
+		t.Fatalf("request id from metadata not propagated, got %q", seen)
+	}
+
+	recs := records()
+	if len(recs) != 2 {
+		t.Fatalf("expected cause and access records, got %v", recs)
+	}
+	cause, access := recs[0], recs[1]
+	if cause["error"] != "db is on fire" || cause["request_id"] != "from-gateway" {
+		t.Fatalf("cause record = %v", cause)
+	}
+	if access["msg"] != "rpc" || access["code"] != "Internal" || access["level"] != "ERROR" ||
+		access["method"] != info.FullMethod || access["request_id"] != "from-gateway" {
+		t.Fatalf("access record = %v", access)
+	}
+}
+
+func TestLoggingInterceptor_GeneratesRequestID(t *testing.T) {
+	records := captureLogs(t)
+	interceptor := LoggingInterceptor()
+
+	// чужой id с переводом строки не должен попасть в лог
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(logging.RequestIDKey, "bad\nid"))
+	var seen string
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/m"}, func(ctx context.Context, _ any) (any, error) {
+		seen = logging.RequestID(ctx)
+		return nil, status.Error(codes.NotFound, "post not found")
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !logging.ValidRequestID(seen) || seen == "bad\nid" {
+		t.Fatalf("expected a fresh request id, got %q", seen)
+	}
+
+	recs := records()
+	if len(recs) != 1 || recs[0]["level"] != "INFO" || recs[0]["error"] != "post not found" || recs[0]["request_id"] != seen {
+		t.Fatalf("access record = %v", recs)
+	}
+}
+
+type headerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (s *headerStream) Context() context.Context { return s.ctx }
+
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestLoggingStreamInterceptor(t *testing.T) {
+	records := captureLogs(t)
+	interceptor := LoggingStreamInterceptor()
+
+	ss := &headerStream{ctx: context.Background()}
+	var seen string
+	err := interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: "/w"}, func(_ any, stream grpc.ServerStream) error {
+		seen = logging.RequestID(stream.Context())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seen == "" || ss.header.Get(logging.RequestIDKey)[0] != seen {
+		t.Fatalf("request id %q must be returned in header %v", seen, ss.header)
+	}
+	if recs := records(); len(recs) != 1 || recs[0]["code"] != "OK" {
+		t.Fatalf("access record = %v", recs)
+	}
+}